@@ -0,0 +1,465 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+const (
+	// ProxyProtocolHTTPPortName is the container port name which makes a
+	// LoadBalancer Service expose PROXY protocol HTTP traffic.
+	ProxyProtocolHTTPPortName = "proxy-http"
+	// ProxyProtocolHTTPSPortName is the container port name which makes a
+	// LoadBalancer Service expose PROXY protocol HTTPS traffic.
+	ProxyProtocolHTTPSPortName = "proxy-https"
+
+	// HTTPSOverHTTPAnnotation set to "true" on the Service annotations makes
+	// the Service's https port target the nginx http port.
+	HTTPSOverHTTPAnnotation = "nginx.tsuru.io/https-over-http"
+
+	// conversionDataAnnotation keeps the v1beta1 fields which cannot be
+	// represented in this version, so they survive a round trip.
+	conversionDataAnnotation = "nginx.tsuru.io/conversion-data"
+)
+
+type conversionData struct {
+	ProxyProtocol *v1beta1.NginxProxyProtocol `json:"proxyProtocol,omitempty"`
+	// PortsProxyProtocol is the proxy protocol inferred from the port names
+	// by then, telling whether they were changed in this version since.
+	PortsProxyProtocol *v1beta1.NginxProxyProtocol `json:"portsProxyProtocol,omitempty"`
+
+	ServicePorts      []corev1.ServicePort           `json:"servicePorts,omitempty"`
+	Services          []v1beta1.NginxNamedService    `json:"services,omitempty"`
+	Gateway           *v1beta1.NginxGateway          `json:"gateway,omitempty"`
+	DisruptionBudget  *v1beta1.NginxDisruptionBudget `json:"disruptionBudget,omitempty"`
+	Autoscaling       *v1beta1.NginxAutoscaling      `json:"autoscaling,omitempty"`
+	TLSReloadStrategy v1beta1.TLSReloadStrategy      `json:"tlsReloadStrategy,omitempty"`
+	// TLSIssuerRefs are the TLS issuers keyed by the Secret name.
+	TLSIssuerRefs map[string]*v1beta1.NginxTLSIssuerRef `json:"tlsIssuerRefs,omitempty"`
+	ConfigServers []v1beta1.NginxServerConfig           `json:"configServers,omitempty"`
+
+	ProgressDeadlineSeconds *int32                 `json:"progressDeadlineSeconds,omitempty"`
+	RevisionHistoryLimit    *int32                 `json:"revisionHistoryLimit,omitempty"`
+	Strategy                *v1beta1.NginxStrategy `json:"strategy,omitempty"`
+
+	Monitoring *v1beta1.NginxMonitoring `json:"monitoring,omitempty"`
+
+	Status *statusConversionData `json:"status,omitempty"`
+}
+
+// statusConversionData keeps the v1beta1 status fields, so that a status
+// update through this version doesn't wipe them.
+type statusConversionData struct {
+	CurrentRevision  int64                 `json:"currentRevision,omitempty"`
+	PreviousRevision int64                 `json:"previousRevision,omitempty"`
+	Routes           []v1beta1.RouteStatus `json:"routes,omitempty"`
+	// Deployments are the Deployments' status keyed by their name.
+	Deployments map[string]v1beta1.DeploymentStatus `json:"deployments,omitempty"`
+}
+
+var _ conversion.Convertible = &Nginx{}
+
+// ConvertTo converts this Nginx to the hub version (v1beta1).
+func (src *Nginx) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1beta1.Nginx)
+	if !ok {
+		return fmt.Errorf("unsupported conversion to %T", dstRaw)
+	}
+
+	in := src.DeepCopy()
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Spec = convertSpecToV1beta1(in.Spec)
+	dst.Status = convertStatusToV1beta1(in.Status)
+
+	data, ok := dst.Annotations[conversionDataAnnotation]
+	if !ok {
+		return nil
+	}
+
+	delete(dst.Annotations, conversionDataAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	var restored conversionData
+	if err := json.Unmarshal([]byte(data), &restored); err != nil {
+		return fmt.Errorf("failed to unmarshal %q annotation: %w", conversionDataAnnotation, err)
+	}
+
+	if dst.Spec.Service != nil {
+		// NOTE: proxy protocol set through the port names in this version
+		// takes precedence once they are changed.
+		if reflect.DeepEqual(dst.Spec.Service.ProxyProtocol, restored.PortsProxyProtocol) {
+			dst.Spec.Service.ProxyProtocol = restored.ProxyProtocol
+		}
+		dst.Spec.Service.Ports = restored.ServicePorts
+	}
+
+	dst.Spec.Services = restored.Services
+	dst.Spec.Gateway = restored.Gateway
+	dst.Spec.DisruptionBudget = restored.DisruptionBudget
+	dst.Spec.Autoscaling = restored.Autoscaling
+	dst.Spec.TLSReloadStrategy = restored.TLSReloadStrategy
+	dst.Spec.ProgressDeadlineSeconds = restored.ProgressDeadlineSeconds
+	dst.Spec.RevisionHistoryLimit = restored.RevisionHistoryLimit
+	dst.Spec.Strategy = restored.Strategy
+	dst.Spec.Monitoring = restored.Monitoring
+
+	for i := range dst.Spec.TLS {
+		dst.Spec.TLS[i].IssuerRef = restored.TLSIssuerRefs[dst.Spec.TLS[i].SecretName]
+	}
+
+	if dst.Spec.Config != nil {
+		dst.Spec.Config.Servers = restored.ConfigServers
+	}
+
+	if restored.Status != nil {
+		dst.Status.CurrentRevision = restored.Status.CurrentRevision
+		dst.Status.PreviousRevision = restored.Status.PreviousRevision
+		dst.Status.Routes = restored.Status.Routes
+
+		for i, d := range dst.Status.Deployments {
+			if status, ok := restored.Status.Deployments[d.Name]; ok {
+				dst.Status.Deployments[i] = status
+			}
+		}
+	}
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1beta1) to this version.
+func (dst *Nginx) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1beta1.Nginx)
+	if !ok {
+		return fmt.Errorf("unsupported conversion from %T", srcRaw)
+	}
+
+	in := src.DeepCopy()
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Spec = convertSpecFromV1beta1(in.Spec)
+	dst.Status = convertStatusFromV1beta1(in.Status)
+
+	restore := conversionData{
+		Services:          in.Spec.Services,
+		Gateway:           in.Spec.Gateway,
+		DisruptionBudget:  in.Spec.DisruptionBudget,
+		Autoscaling:       in.Spec.Autoscaling,
+		TLSReloadStrategy: in.Spec.TLSReloadStrategy,
+
+		ProgressDeadlineSeconds: in.Spec.ProgressDeadlineSeconds,
+		RevisionHistoryLimit:    in.Spec.RevisionHistoryLimit,
+		Strategy:                in.Spec.Strategy,
+		Monitoring:              in.Spec.Monitoring,
+	}
+
+	for _, tls := range in.Spec.TLS {
+		if tls.IssuerRef == nil {
+			continue
+		}
+
+		if restore.TLSIssuerRefs == nil {
+			restore.TLSIssuerRefs = make(map[string]*v1beta1.NginxTLSIssuerRef)
+		}
+		restore.TLSIssuerRefs[tls.SecretName] = tls.IssuerRef
+	}
+
+	if in.Spec.Config != nil {
+		restore.ConfigServers = in.Spec.Config.Servers
+	}
+
+	if in.Spec.Service != nil {
+		restore.ProxyProtocol = in.Spec.Service.ProxyProtocol
+		restore.ServicePorts = in.Spec.Service.Ports
+	}
+
+	restore.Status = statusConversionDataFrom(in.Status)
+
+	// NOTE: proxy protocol is inferred from the port names in this version,
+	// any other setting must be kept aside to not be lost.
+	var inferred conversionData
+	if dst.Spec.Service != nil {
+		inferred.ProxyProtocol = proxyProtocolFromPorts(dst.Spec.PodTemplate.Ports)
+	}
+
+	if reflect.DeepEqual(restore, inferred) {
+		return nil
+	}
+
+	restore.PortsProxyProtocol = inferred.ProxyProtocol
+
+	data, err := json.Marshal(restore)
+	if err != nil {
+		return err
+	}
+
+	if dst.Annotations == nil {
+		dst.Annotations = make(map[string]string)
+	}
+	dst.Annotations[conversionDataAnnotation] = string(data)
+	return nil
+}
+
+func statusConversionDataFrom(in v1beta1.NginxStatus) *statusConversionData {
+	out := statusConversionData{
+		CurrentRevision:  in.CurrentRevision,
+		PreviousRevision: in.PreviousRevision,
+		Routes:           in.Routes,
+	}
+
+	for _, d := range in.Deployments {
+		if reflect.DeepEqual(d, v1beta1.DeploymentStatus{Name: d.Name}) {
+			continue
+		}
+
+		if out.Deployments == nil {
+			out.Deployments = make(map[string]v1beta1.DeploymentStatus)
+		}
+		out.Deployments[d.Name] = d
+	}
+
+	if reflect.DeepEqual(out, statusConversionData{}) {
+		return nil
+	}
+
+	return &out
+}
+
+func convertSpecToV1beta1(in NginxSpec) v1beta1.NginxSpec {
+	out := v1beta1.NginxSpec{
+		Replicas:        in.Replicas,
+		Image:           in.Image,
+		Config:          convertConfigRefToV1beta1(in.Config),
+		PodTemplate:     convertPodTemplateToV1beta1(in.PodTemplate),
+		Service:         convertServiceToV1beta1(in.Service, in.PodTemplate.Ports),
+		Ingress:         (*v1beta1.NginxIngress)(in.Ingress),
+		ExtraFiles:      (*v1beta1.FilesRef)(in.ExtraFiles),
+		HealthcheckPath: in.HealthcheckPath,
+		Resources:       in.Resources,
+		Cache:           v1beta1.NginxCacheSpec(in.Cache),
+	}
+
+	for _, tls := range in.TLS {
+		out.TLS = append(out.TLS, v1beta1.NginxTLS{SecretName: tls.SecretName, Hosts: tls.Hosts})
+	}
+
+	if in.Lifecycle != nil {
+		out.Lifecycle = &v1beta1.NginxLifecycle{
+			PostStart: (*v1beta1.NginxLifecycleHandler)(in.Lifecycle.PostStart),
+			PreStop:   (*v1beta1.NginxLifecycleHandler)(in.Lifecycle.PreStop),
+		}
+	}
+
+	return out
+}
+
+func convertSpecFromV1beta1(in v1beta1.NginxSpec) NginxSpec {
+	out := NginxSpec{
+		Replicas:        in.Replicas,
+		Image:           in.Image,
+		Config:          convertConfigRefFromV1beta1(in.Config),
+		PodTemplate:     convertPodTemplateFromV1beta1(in.PodTemplate),
+		Service:         convertServiceFromV1beta1(in.Service),
+		Ingress:         (*NginxIngress)(in.Ingress),
+		ExtraFiles:      (*FilesRef)(in.ExtraFiles),
+		HealthcheckPath: in.HealthcheckPath,
+		Resources:       in.Resources,
+		Cache:           NginxCacheSpec(in.Cache),
+	}
+
+	for _, tls := range in.TLS {
+		out.TLS = append(out.TLS, NginxTLS{SecretName: tls.SecretName, Hosts: tls.Hosts})
+	}
+
+	if in.Lifecycle != nil {
+		out.Lifecycle = &NginxLifecycle{
+			PostStart: (*NginxLifecycleHandler)(in.Lifecycle.PostStart),
+			PreStop:   (*NginxLifecycleHandler)(in.Lifecycle.PreStop),
+		}
+	}
+
+	return out
+}
+
+func convertConfigRefToV1beta1(in *ConfigRef) *v1beta1.ConfigRef {
+	if in == nil {
+		return nil
+	}
+
+	return &v1beta1.ConfigRef{Kind: v1beta1.ConfigKind(in.Kind), Name: in.Name, Value: in.Value}
+}
+
+func convertConfigRefFromV1beta1(in *v1beta1.ConfigRef) *ConfigRef {
+	if in == nil {
+		return nil
+	}
+
+	return &ConfigRef{Kind: ConfigKind(in.Kind), Name: in.Name, Value: in.Value}
+}
+
+func convertPodTemplateToV1beta1(in NginxPodTemplateSpec) v1beta1.NginxPodTemplateSpec {
+	return v1beta1.NginxPodTemplateSpec{
+		Affinity:                      in.Affinity,
+		NodeSelector:                  in.NodeSelector,
+		Annotations:                   in.Annotations,
+		Labels:                        in.Labels,
+		HostNetwork:                   in.HostNetwork,
+		Ports:                         in.Ports,
+		TerminationGracePeriodSeconds: in.TerminationGracePeriodSeconds,
+		PodSecurityContext:            in.PodSecurityContext,
+		ContainerSecurityContext:      in.ContainerSecurityContext,
+		Volumes:                       in.Volumes,
+		VolumeMounts:                  in.VolumeMounts,
+		InitContainers:                in.InitContainers,
+		Containers:                    in.Containers,
+		RollingUpdate:                 in.RollingUpdate,
+		Tolerations:                   in.Toleration,
+		ServiceAccountName:            in.ServiceAccountName,
+		TopologySpreadConstraints:     in.TopologySpreadConstraints,
+	}
+}
+
+func convertPodTemplateFromV1beta1(in v1beta1.NginxPodTemplateSpec) NginxPodTemplateSpec {
+	return NginxPodTemplateSpec{
+		Affinity:                      in.Affinity,
+		NodeSelector:                  in.NodeSelector,
+		Annotations:                   in.Annotations,
+		Labels:                        in.Labels,
+		HostNetwork:                   in.HostNetwork,
+		Ports:                         in.Ports,
+		TerminationGracePeriodSeconds: in.TerminationGracePeriodSeconds,
+		PodSecurityContext:            in.PodSecurityContext,
+		ContainerSecurityContext:      in.ContainerSecurityContext,
+		Volumes:                       in.Volumes,
+		VolumeMounts:                  in.VolumeMounts,
+		InitContainers:                in.InitContainers,
+		Containers:                    in.Containers,
+		RollingUpdate:                 in.RollingUpdate,
+		Toleration:                    in.Tolerations,
+		ServiceAccountName:            in.ServiceAccountName,
+		TopologySpreadConstraints:     in.TopologySpreadConstraints,
+	}
+}
+
+func convertServiceToV1beta1(in *NginxService, ports []corev1.ContainerPort) *v1beta1.NginxService {
+	if in == nil {
+		return nil
+	}
+
+	out := &v1beta1.NginxService{
+		Type:                  in.Type,
+		LoadBalancerIP:        in.LoadBalancerIP,
+		Labels:                in.Labels,
+		Annotations:           in.Annotations,
+		ExternalTrafficPolicy: in.ExternalTrafficPolicy,
+		UsePodSelector:        in.UsePodSelector,
+		ProxyProtocol:         proxyProtocolFromPorts(ports),
+	}
+
+	if out.Annotations[HTTPSOverHTTPAnnotation] == "true" {
+		out.HTTPSOverHTTP = true
+		delete(out.Annotations, HTTPSOverHTTPAnnotation)
+		if len(out.Annotations) == 0 {
+			out.Annotations = nil
+		}
+	}
+
+	return out
+}
+
+func convertServiceFromV1beta1(in *v1beta1.NginxService) *NginxService {
+	if in == nil {
+		return nil
+	}
+
+	out := &NginxService{
+		Type:                  in.Type,
+		LoadBalancerIP:        in.LoadBalancerIP,
+		Labels:                in.Labels,
+		Annotations:           in.Annotations,
+		ExternalTrafficPolicy: in.ExternalTrafficPolicy,
+		UsePodSelector:        in.UsePodSelector,
+	}
+
+	if in.HTTPSOverHTTP {
+		if out.Annotations == nil {
+			out.Annotations = make(map[string]string)
+		}
+		out.Annotations[HTTPSOverHTTPAnnotation] = "true"
+	}
+
+	return out
+}
+
+func proxyProtocolFromPorts(ports []corev1.ContainerPort) *v1beta1.NginxProxyProtocol {
+	var pp v1beta1.NginxProxyProtocol
+	for _, port := range ports {
+		switch port.Name {
+		case ProxyProtocolHTTPPortName:
+			pp.HTTPPort = port.Name
+		case ProxyProtocolHTTPSPortName:
+			pp.HTTPSPort = port.Name
+		}
+	}
+
+	if pp == (v1beta1.NginxProxyProtocol{}) {
+		return nil
+	}
+
+	return &pp
+}
+
+func convertStatusToV1beta1(in NginxStatus) v1beta1.NginxStatus {
+	out := v1beta1.NginxStatus{
+		ObservedGeneration: in.ObservedGeneration,
+		CurrentReplicas:    in.CurrentReplicas,
+		PodSelector:        in.PodSelector,
+		Conditions:         in.Conditions,
+	}
+
+	for _, d := range in.Deployments {
+		out.Deployments = append(out.Deployments, v1beta1.DeploymentStatus{Name: d.Name})
+	}
+
+	for _, s := range in.Services {
+		out.Services = append(out.Services, v1beta1.ServiceStatus(s))
+	}
+
+	for _, i := range in.Ingresses {
+		out.Ingresses = append(out.Ingresses, v1beta1.IngressStatus(i))
+	}
+
+	return out
+}
+
+func convertStatusFromV1beta1(in v1beta1.NginxStatus) NginxStatus {
+	out := NginxStatus{
+		ObservedGeneration: in.ObservedGeneration,
+		CurrentReplicas:    in.CurrentReplicas,
+		PodSelector:        in.PodSelector,
+		Conditions:         in.Conditions,
+	}
+
+	for _, d := range in.Deployments {
+		out.Deployments = append(out.Deployments, DeploymentStatus{Name: d.Name})
+	}
+
+	for _, s := range in.Services {
+		out.Services = append(out.Services, ServiceStatus(s))
+	}
+
+	for _, i := range in.Ingresses {
+		out.Ingresses = append(out.Ingresses, IngressStatus(i))
+	}
+
+	return out
+}
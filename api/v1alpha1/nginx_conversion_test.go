@@ -0,0 +1,395 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+func TestNginx_ConvertTo(t *testing.T) {
+	tests := map[string]struct {
+		nginx    Nginx
+		expected v1beta1.Nginx
+	}{
+		"empty": {},
+
+		"renamed and reshaped fields": {
+			nginx: Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+				Spec: NginxSpec{
+					Replicas: func(n int32) *int32 { return &n }(int32(2)),
+					Config:   &ConfigRef{Kind: ConfigKindConfigMap, Name: "my-config"},
+					TLS:      []NginxTLS{{SecretName: "my-secret", Hosts: []string{"www.example.com"}}},
+					PodTemplate: NginxPodTemplateSpec{
+						Ports: []corev1.ContainerPort{
+							{Name: "http", ContainerPort: 8080},
+							{Name: "proxy-http", ContainerPort: 9080},
+						},
+						Toleration: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+					},
+					Service: &NginxService{
+						Type: corev1.ServiceTypeLoadBalancer,
+						Annotations: map[string]string{
+							"nginx.tsuru.io/https-over-http": "true",
+						},
+					},
+					Lifecycle: &NginxLifecycle{
+						PreStop: &NginxLifecycleHandler{Exec: &corev1.ExecAction{Command: []string{"sleep", "5"}}},
+					},
+				},
+				Status: NginxStatus{
+					CurrentReplicas: 2,
+					Services:        []ServiceStatus{{Name: "my-nginx-service", IPs: []string{"192.0.2.10"}}},
+				},
+			},
+			expected: v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+				Spec: v1beta1.NginxSpec{
+					Replicas: func(n int32) *int32 { return &n }(int32(2)),
+					Config:   &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindConfigMap, Name: "my-config"},
+					TLS:      []v1beta1.NginxTLS{{SecretName: "my-secret", Hosts: []string{"www.example.com"}}},
+					PodTemplate: v1beta1.NginxPodTemplateSpec{
+						Ports: []corev1.ContainerPort{
+							{Name: "http", ContainerPort: 8080},
+							{Name: "proxy-http", ContainerPort: 9080},
+						},
+						Tolerations: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+					},
+					Service: &v1beta1.NginxService{
+						Type:          corev1.ServiceTypeLoadBalancer,
+						ProxyProtocol: &v1beta1.NginxProxyProtocol{HTTPPort: "proxy-http"},
+						HTTPSOverHTTP: true,
+					},
+					Lifecycle: &v1beta1.NginxLifecycle{
+						PreStop: &v1beta1.NginxLifecycleHandler{Exec: &corev1.ExecAction{Command: []string{"sleep", "5"}}},
+					},
+				},
+				Status: v1beta1.NginxStatus{
+					CurrentReplicas: 2,
+					Services:        []v1beta1.ServiceStatus{{Name: "my-nginx-service", IPs: []string{"192.0.2.10"}}},
+				},
+			},
+		},
+
+		"proxy protocol ports without service": {
+			nginx: Nginx{
+				Spec: NginxSpec{
+					PodTemplate: NginxPodTemplateSpec{
+						Ports: []corev1.ContainerPort{{Name: "proxy-https", ContainerPort: 9443}},
+					},
+				},
+			},
+			expected: v1beta1.Nginx{
+				Spec: v1beta1.NginxSpec{
+					PodTemplate: v1beta1.NginxPodTemplateSpec{
+						Ports: []corev1.ContainerPort{{Name: "proxy-https", ContainerPort: 9443}},
+					},
+				},
+			},
+		},
+
+		"https over http annotation other than true is kept": {
+			nginx: Nginx{
+				Spec: NginxSpec{
+					Service: &NginxService{
+						Annotations: map[string]string{"nginx.tsuru.io/https-over-http": "false"},
+					},
+				},
+			},
+			expected: v1beta1.Nginx{
+				Spec: v1beta1.NginxSpec{
+					Service: &v1beta1.NginxService{
+						Annotations: map[string]string{"nginx.tsuru.io/https-over-http": "false"},
+					},
+				},
+			},
+		},
+
+		"restoring data from conversion annotation": {
+			nginx: Nginx{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"nginx.tsuru.io/conversion-data": `{"portsProxyProtocol":{"httpPort":"proxy-http"}}`,
+					},
+				},
+				Spec: NginxSpec{
+					PodTemplate: NginxPodTemplateSpec{
+						Ports: []corev1.ContainerPort{{Name: "proxy-http", ContainerPort: 9080}},
+					},
+					Service: &NginxService{Type: corev1.ServiceTypeLoadBalancer},
+				},
+			},
+			expected: v1beta1.Nginx{
+				Spec: v1beta1.NginxSpec{
+					PodTemplate: v1beta1.NginxPodTemplateSpec{
+						Ports: []corev1.ContainerPort{{Name: "proxy-http", ContainerPort: 9080}},
+					},
+					Service: &v1beta1.NginxService{Type: corev1.ServiceTypeLoadBalancer},
+				},
+			},
+		},
+
+		"proxy protocol port names changed since the conversion annotation": {
+			nginx: Nginx{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"nginx.tsuru.io/conversion-data": `{"portsProxyProtocol":{"httpPort":"proxy-http"}}`,
+					},
+				},
+				Spec: NginxSpec{
+					PodTemplate: NginxPodTemplateSpec{
+						Ports: []corev1.ContainerPort{{Name: "proxy-http", ContainerPort: 9080}, {Name: "proxy-https", ContainerPort: 9443}},
+					},
+					Service: &NginxService{Type: corev1.ServiceTypeLoadBalancer},
+				},
+			},
+			expected: v1beta1.Nginx{
+				Spec: v1beta1.NginxSpec{
+					PodTemplate: v1beta1.NginxPodTemplateSpec{
+						Ports: []corev1.ContainerPort{{Name: "proxy-http", ContainerPort: 9080}, {Name: "proxy-https", ContainerPort: 9443}},
+					},
+					Service: &v1beta1.NginxService{
+						Type:          corev1.ServiceTypeLoadBalancer,
+						ProxyProtocol: &v1beta1.NginxProxyProtocol{HTTPPort: "proxy-http", HTTPSPort: "proxy-https"},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got v1beta1.Nginx
+			require.NoError(t, tt.nginx.ConvertTo(&got))
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestNginx_ConvertTo_invalidConversionData(t *testing.T) {
+	nginx := &Nginx{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{"nginx.tsuru.io/conversion-data": "{"},
+		},
+	}
+
+	var got v1beta1.Nginx
+	assert.ErrorContains(t, nginx.ConvertTo(&got), `failed to unmarshal "nginx.tsuru.io/conversion-data" annotation`)
+}
+
+func TestNginx_ConvertTo_statusUpdate(t *testing.T) {
+	hub := &v1beta1.Nginx{
+		Status: v1beta1.NginxStatus{
+			CurrentRevision: 2,
+			Deployments:     []v1beta1.DeploymentStatus{{Name: "my-nginx-blue", Color: "blue", Active: true}},
+			Routes:          []v1beta1.RouteStatus{{Name: "my-nginx"}},
+		},
+	}
+
+	var spoke Nginx
+	require.NoError(t, spoke.ConvertFrom(hub))
+
+	spoke.Status.CurrentReplicas = 5
+	spoke.Status.Deployments = append(spoke.Status.Deployments, DeploymentStatus{Name: "my-nginx-green"})
+
+	var got v1beta1.Nginx
+	require.NoError(t, spoke.ConvertTo(&got))
+	assert.Equal(t, v1beta1.NginxStatus{
+		CurrentReplicas: 5,
+		CurrentRevision: 2,
+		Deployments: []v1beta1.DeploymentStatus{
+			{Name: "my-nginx-blue", Color: "blue", Active: true},
+			{Name: "my-nginx-green"},
+		},
+		Routes: []v1beta1.RouteStatus{{Name: "my-nginx"}},
+	}, got.Status)
+}
+
+func TestNginx_RoundTrip(t *testing.T) {
+	hubs := map[string]v1beta1.Nginx{
+		"proxy protocol with custom port names": {
+			ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Annotations: map[string]string{"foo": "bar"}},
+			Spec: v1beta1.NginxSpec{
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					Ports: []corev1.ContainerPort{{Name: "pp-http", ContainerPort: 9080}},
+				},
+				Service: &v1beta1.NginxService{
+					Type:          corev1.ServiceTypeLoadBalancer,
+					ProxyProtocol: &v1beta1.NginxProxyProtocol{HTTPPort: "pp-http"},
+				},
+			},
+		},
+
+		"proxy protocol port names without proxy protocol": {
+			Spec: v1beta1.NginxSpec{
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					Ports: []corev1.ContainerPort{{Name: "proxy-http", ContainerPort: 9080}},
+				},
+				Service: &v1beta1.NginxService{Type: corev1.ServiceTypeLoadBalancer},
+			},
+		},
+
+		"fields only available on v1beta1": {
+			Spec: v1beta1.NginxSpec{
+				Service: &v1beta1.NginxService{
+					Type:  corev1.ServiceTypeClusterIP,
+					Ports: []corev1.ServicePort{{Name: "http", Port: 8080}},
+				},
+				Services: []v1beta1.NginxNamedService{
+					{Name: "internal", NginxService: v1beta1.NginxService{Type: corev1.ServiceTypeLoadBalancer}},
+				},
+				Gateway: &v1beta1.NginxGateway{
+					ParentRefs: []v1beta1.NginxGatewayParentRef{{Name: "my-gateway", Namespace: "gateways"}},
+				},
+				DisruptionBudget: &v1beta1.NginxDisruptionBudget{
+					MaxUnavailable: func(v intstr.IntOrString) *intstr.IntOrString { return &v }(intstr.FromString("25%")),
+				},
+				Autoscaling: &v1beta1.NginxAutoscaling{
+					MinReplicas:                    func(n int32) *int32 { return &n }(int32(2)),
+					MaxReplicas:                    10,
+					TargetCPUUtilizationPercentage: func(n int32) *int32 { return &n }(int32(85)),
+				},
+				TLSReloadStrategy:       v1beta1.TLSReloadStrategyReload,
+				ProgressDeadlineSeconds: func(n int32) *int32 { return &n }(int32(300)),
+				RevisionHistoryLimit:    func(n int32) *int32 { return &n }(int32(3)),
+				Strategy: &v1beta1.NginxStrategy{
+					Type:   v1beta1.NginxStrategyCanary,
+					Canary: &v1beta1.NginxCanaryStrategy{Share: func(n int32) *int32 { return &n }(int32(20))},
+				},
+				TLS: []v1beta1.NginxTLS{
+					{SecretName: "example-com", Hosts: []string{"www.example.com"}, IssuerRef: &v1beta1.NginxTLSIssuerRef{Name: "letsencrypt", Kind: "ClusterIssuer"}},
+					{SecretName: "example-org"},
+				},
+			},
+		},
+
+		"structured config": {
+			Spec: v1beta1.NginxSpec{
+				Config: &v1beta1.ConfigRef{
+					Kind: v1beta1.ConfigKindStructured,
+					Servers: []v1beta1.NginxServerConfig{
+						{
+							ServerNames: []string{"www.example.com"},
+							Locations:   []v1beta1.NginxLocation{{Path: "/", ProxyPass: "http://backend:8080"}},
+						},
+					},
+				},
+			},
+		},
+
+		"status fields only available on v1beta1": {
+			Status: v1beta1.NginxStatus{
+				CurrentReplicas:  3,
+				CurrentRevision:  4,
+				PreviousRevision: 2,
+				Deployments: []v1beta1.DeploymentStatus{
+					{Name: "my-nginx-blue", Color: "blue", ScaleDownAt: func(t metav1.Time) *metav1.Time { return &t }(metav1.Date(2026, 1, 2, 3, 4, 5, 0, time.Local))},
+					{Name: "my-nginx-canary", Canary: &v1beta1.CanaryStatus{Phase: v1beta1.CanaryPhaseBaking}},
+					{Name: "my-nginx-green", Color: "green", Active: true},
+					{Name: "my-nginx-legacy"},
+				},
+				Routes: []v1beta1.RouteStatus{
+					{Name: "my-nginx", Parents: []v1beta1.RouteParentStatus{{Name: "my-gateway", Namespace: "gateways"}}},
+				},
+			},
+		},
+
+		"https over http": {
+			Spec: v1beta1.NginxSpec{
+				Service: &v1beta1.NginxService{
+					Annotations:   map[string]string{"foo": "bar"},
+					HTTPSOverHTTP: true,
+				},
+			},
+		},
+	}
+
+	for name, hub := range hubs {
+		t.Run(name, func(t *testing.T) {
+			var spoke Nginx
+			require.NoError(t, spoke.ConvertFrom(hub.DeepCopy()))
+
+			var got v1beta1.Nginx
+			require.NoError(t, spoke.ConvertTo(&got))
+			assert.Equal(t, hub, got)
+		})
+	}
+}
+
+func TestNginx_RoundTrip_proxyProtocolPortsChanged(t *testing.T) {
+	tests := map[string]struct {
+		hub      v1beta1.Nginx
+		ports    []corev1.ContainerPort
+		expected *v1beta1.NginxProxyProtocol
+	}{
+		"adding a proxy protocol port": {
+			hub: v1beta1.Nginx{
+				Spec: v1beta1.NginxSpec{
+					PodTemplate: v1beta1.NginxPodTemplateSpec{
+						Ports: []corev1.ContainerPort{{Name: "pp-http", ContainerPort: 9080}},
+					},
+					Service: &v1beta1.NginxService{
+						Type:          corev1.ServiceTypeLoadBalancer,
+						ProxyProtocol: &v1beta1.NginxProxyProtocol{HTTPPort: "pp-http"},
+					},
+				},
+			},
+			ports:    []corev1.ContainerPort{{Name: "pp-http", ContainerPort: 9080}, {Name: "proxy-https", ContainerPort: 9443}},
+			expected: &v1beta1.NginxProxyProtocol{HTTPSPort: "proxy-https"},
+		},
+
+		"removing the proxy protocol ports": {
+			hub: v1beta1.Nginx{
+				Spec: v1beta1.NginxSpec{
+					PodTemplate: v1beta1.NginxPodTemplateSpec{
+						Ports: []corev1.ContainerPort{{Name: "proxy-http", ContainerPort: 9080}},
+					},
+					Service:     &v1beta1.NginxService{Type: corev1.ServiceTypeLoadBalancer},
+					Autoscaling: &v1beta1.NginxAutoscaling{MaxReplicas: 3},
+				},
+			},
+			ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 9080}},
+		},
+
+		"keeping the proxy protocol ports": {
+			hub: v1beta1.Nginx{
+				Spec: v1beta1.NginxSpec{
+					PodTemplate: v1beta1.NginxPodTemplateSpec{
+						Ports: []corev1.ContainerPort{{Name: "pp-http", ContainerPort: 9080}},
+					},
+					Service: &v1beta1.NginxService{
+						Type:          corev1.ServiceTypeLoadBalancer,
+						ProxyProtocol: &v1beta1.NginxProxyProtocol{HTTPPort: "pp-http"},
+					},
+				},
+			},
+			ports:    []corev1.ContainerPort{{Name: "pp-http", ContainerPort: 8080}},
+			expected: &v1beta1.NginxProxyProtocol{HTTPPort: "pp-http"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var spoke Nginx
+			require.NoError(t, spoke.ConvertFrom(tt.hub.DeepCopy()))
+
+			spoke.Spec.PodTemplate.Ports = tt.ports
+
+			var got v1beta1.Nginx
+			require.NoError(t, spoke.ConvertTo(&got))
+			assert.Equal(t, tt.expected, got.Spec.Service.ProxyProtocol)
+			assert.Equal(t, tt.ports, got.Spec.PodTemplate.Ports)
+		})
+	}
+}
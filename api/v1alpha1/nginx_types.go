@@ -12,8 +12,10 @@ import (
 )
 
 // +kubebuilder:object:root=true
+// +kubebuilder:deprecatedversion:warning="nginx.tsuru.io/v1alpha1 Nginx is deprecated, use nginx.tsuru.io/v1beta1 instead"
 // +kubebuilder:subresource:status
 // +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.currentReplicas,selectorpath=.status.podSelector
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
 // +kubebuilder:printcolumn:name="Current",type=integer,JSONPath=`.status.currentReplicas`
 // +kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=`.spec.replicas`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
@@ -252,6 +254,11 @@ type NginxLifecycleHandler struct {
 
 // NginxStatus defines the observed state of Nginx
 type NginxStatus struct {
+	// ObservedGeneration is the most recent generation observed for this
+	// Nginx. It corresponds to the Nginx's generation, which is updated on
+	// mutation by the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// CurrentReplicas is the last observed number from the NGINX object.
 	CurrentReplicas int32 `json:"currentReplicas,omitempty"`
 	// PodSelector is the NGINX's pod label selector.
@@ -260,8 +267,38 @@ type NginxStatus struct {
 	Deployments []DeploymentStatus `json:"deployments,omitempty"`
 	Services    []ServiceStatus    `json:"services,omitempty"`
 	Ingresses   []IngressStatus    `json:"ingresses,omitempty"`
+
+	// Conditions represent the latest available observations of the Nginx's
+	// current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
+const (
+	// ConditionReady indicates whether the Nginx is fully operational, i.e. all
+	// the other conditions are in their healthy state.
+	ConditionReady = "Ready"
+	// ConditionDeploymentAvailable indicates whether the Nginx's Deployment has
+	// the minimum number of available replicas.
+	ConditionDeploymentAvailable = "DeploymentAvailable"
+	// ConditionServiceReady indicates whether the Nginx's Service has been
+	// successfully reconciled and, when it's a LoadBalancer, has an address.
+	ConditionServiceReady = "ServiceReady"
+	// ConditionIngressReady indicates whether the Nginx's Ingresses have been
+	// successfully reconciled.
+	ConditionIngressReady = "IngressReady"
+	// ConditionConfigValid indicates whether the Nginx's spec could be
+	// rendered into a valid pod template.
+	ConditionConfigValid = "ConfigValid"
+	// ConditionProgressing indicates whether a rollout of the Nginx's
+	// Deployment is in progress.
+	ConditionProgressing = "Progressing"
+)
+
 type DeploymentStatus struct {
 	// Name is the name of the Deployment created by nginx
 	Name string `json:"name"`
@@ -0,0 +1,9 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1beta1
+
+// Hub marks this version as the one every other Nginx version is converted
+// to and from.
+func (*Nginx) Hub() {}
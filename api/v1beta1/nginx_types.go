@@ -0,0 +1,791 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1beta1
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.currentReplicas,selectorpath=.status.podSelector
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Current",type=integer,JSONPath=`.status.currentReplicas`
+// +kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=`.spec.replicas`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:printcolumn:name="Ingress IPs",type=string,JSONPath=`.status.ingresses[*].ips[*]`
+// +kubebuilder:printcolumn:name="Service IPs",type=string,JSONPath=`.status.services[*].ips[*]`
+
+// Nginx is the Schema for the nginxes API
+type Nginx struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NginxSpec   `json:"spec,omitempty"`
+	Status NginxStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NginxList contains a list of Nginx
+type NginxList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Nginx `json:"items"`
+}
+
+// NginxSpec defines the desired state of Nginx
+type NginxSpec struct {
+	// Replicas is the number of desired pods. Defaults to the default deployment
+	// replicas value.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Image is the container image name. Defaults to "nginx:latest".
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Config is a reference to the NGINX config object which stores the NGINX
+	// configuration file. When provided the file is mounted in NGINX container on
+	// "/etc/nginx/nginx.conf".
+	// +optional
+	Config *ConfigRef `json:"config,omitempty"`
+	// TLS configuration.
+	// +optional
+	TLS []NginxTLS `json:"tls,omitempty"`
+	// TLSReloadStrategy defines how nginx picks up renewed certificates from
+	// the TLS Secrets. Defaults to "None", i.e. the certificates are only
+	// loaded when the pods start.
+	// +kubebuilder:validation:Enum=None;Reload;RollingRestart
+	// +optional
+	TLSReloadStrategy TLSReloadStrategy `json:"tlsReloadStrategy,omitempty"`
+	// Template used to configure the nginx pod.
+	// +optional
+	PodTemplate NginxPodTemplateSpec `json:"podTemplate,omitempty"`
+	// Service to expose the nginx pod
+	// +optional
+	Service *NginxService `json:"service,omitempty"`
+	// Services are additional Services to expose the nginx pod, each one
+	// named after the Nginx and its own name, e.g. "<nginx name>-internal".
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Services []NginxNamedService `json:"services,omitempty"`
+	// Ingress defines a convenient way to expose the Nginx service.
+	// +optional
+	Ingress *NginxIngress `json:"ingress,omitempty"`
+	// Gateway exposes the Nginx service through a Gateway API HTTPRoute.
+	// +optional
+	Gateway *NginxGateway `json:"gateway,omitempty"`
+	// ExtraFiles references to additional files into a object in the cluster.
+	// These additional files will be mounted on `/etc/nginx/extra_files`.
+	// +optional
+	ExtraFiles *FilesRef `json:"extraFiles,omitempty"`
+	// HealthcheckPath defines the endpoint used to check whether instance is
+	// working or not.
+	// +optional
+	HealthcheckPath string `json:"healthcheckPath,omitempty"`
+	// Resources requirements to be set on the NGINX container.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Cache allows configuring a cache volume for nginx to use.
+	// +optional
+	Cache NginxCacheSpec `json:"cache,omitempty"`
+	// Lifecycle describes actions that should be executed when
+	// some event happens to nginx container.
+	// +optional
+	Lifecycle *NginxLifecycle `json:"lifecycle,omitempty"`
+	// DisruptionBudget configures a PodDisruptionBudget which limits how many
+	// nginx pods can be taken down at once by voluntary disruptions, such as
+	// node drains.
+	// +optional
+	DisruptionBudget *NginxDisruptionBudget `json:"disruptionBudget,omitempty"`
+	// Autoscaling configures a HorizontalPodAutoscaler which scales the Nginx
+	// through its scale subresource. While enabled, the number of replicas is
+	// managed by the autoscaler.
+	// +optional
+	Autoscaling *NginxAutoscaling `json:"autoscaling,omitempty"`
+	// ProgressDeadlineSeconds is the maximum time, in seconds, for a rollout
+	// to make progress. Once exceeded, the Deployment is rolled back to the
+	// last spec which rolled out successfully, while this spec is kept as is.
+	// Defaults to 600s.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+	// RevisionHistoryLimit is the number of previous specs kept as
+	// ControllerRevisions, which can be restored through the
+	// "nginx.tsuru.io/rollback-to" annotation. Defaults to 10.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+	// Strategy defines how the spec changes are rolled out to the nginx
+	// pods. Defaults to rolling updates of the Deployment.
+	// +optional
+	Strategy *NginxStrategy `json:"strategy,omitempty"`
+	// Monitoring adds the nginx Prometheus exporter as a sidecar, along with
+	// the stub_status endpoint it scrapes, and the resources telling the
+	// Prometheus Operator to scrape it.
+	// +optional
+	Monitoring *NginxMonitoring `json:"monitoring,omitempty"`
+}
+
+type NginxTLS struct {
+	// SecretName is the name of the Secret which contains the certificate-key
+	// pair. It must reside in the same Namespace as the Nginx resource.
+	//
+	// NOTE: The Secret should follow the Kubernetes TLS secrets type.
+	// More info: https://kubernetes.io/docs/concepts/configuration/secret/#tls-secrets.
+	SecretName string `json:"secretName"`
+	// Hosts are a list of hosts included in the TLS certificate. Defaults to the
+	// wildcard of hosts: "*".
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
+	// IssuerRef is a reference to the cert-manager issuer of the certificate.
+	// When set, a cert-manager Certificate for Hosts is created and issued
+	// into SecretName.
+	// +optional
+	IssuerRef *NginxTLSIssuerRef `json:"issuerRef,omitempty"`
+}
+
+type NginxTLSIssuerRef struct {
+	// Name of the issuer.
+	Name string `json:"name"`
+	// Kind of the issuer, e.g. "Issuer" or "ClusterIssuer". Defaults to
+	// "Issuer".
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Group of the issuer. Defaults to "cert-manager.io".
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+type TLSReloadStrategy string
+
+const (
+	// TLSReloadStrategyNone doesn't reload the certificates, nginx keeps
+	// serving the ones loaded on start.
+	TLSReloadStrategyNone = TLSReloadStrategy("None")
+	// TLSReloadStrategyReload makes the operator reload nginx in place, through
+	// "nginx -s reload", once the renewed certificates are mounted in the pods.
+	TLSReloadStrategyReload = TLSReloadStrategy("Reload")
+	// TLSReloadStrategyRollingRestart rolls out new pods whenever the content
+	// of the TLS Secrets changes.
+	TLSReloadStrategyRollingRestart = TLSReloadStrategy("RollingRestart")
+)
+
+type NginxDisruptionBudget struct {
+	// MinAvailable is the number (or percentage) of nginx pods which must
+	// still be available after an eviction. Mutually exclusive with
+	// MaxUnavailable.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// MaxUnavailable is the number (or percentage) of nginx pods which can be
+	// unavailable after an eviction. Mutually exclusive with MinAvailable.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+type NginxAutoscaling struct {
+	// MinReplicas is the lower limit for the number of replicas. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// MaxReplicas is the upper limit for the number of replicas.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+	// TargetCPUUtilizationPercentage is the target average CPU utilization
+	// of the nginx pods, relative to their CPU requests.
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+	// TargetMemoryUtilizationPercentage is the target average memory
+	// utilization of the nginx pods, relative to their memory requests.
+	// +optional
+	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty"`
+	// Metrics are additional metrics, e.g. custom or external ones, used to
+	// calculate the desired number of replicas.
+	// +optional
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+}
+
+type NginxStrategyType string
+
+const (
+	// NginxStrategyRollingUpdate rolls out the spec changes by updating the
+	// Deployment in place.
+	NginxStrategyRollingUpdate = NginxStrategyType("RollingUpdate")
+	// NginxStrategyCanary rolls out the spec changes to a canary Deployment
+	// first, which is promoted once its pods stay ready for the bake time.
+	NginxStrategyCanary = NginxStrategyType("Canary")
+	// NginxStrategyBlueGreen rolls out the spec changes to the idle one of
+	// two Deployments (blue and green), switching the Services over to it
+	// once all of its pods are ready.
+	NginxStrategyBlueGreen = NginxStrategyType("BlueGreen")
+)
+
+type NginxStrategy struct {
+	// Type of the strategy, either "RollingUpdate", "Canary" or "BlueGreen".
+	// Defaults to "RollingUpdate".
+	// +kubebuilder:validation:Enum=RollingUpdate;Canary;BlueGreen
+	// +optional
+	Type NginxStrategyType `json:"type,omitempty"`
+	// Canary configures the "Canary" strategy.
+	// +optional
+	Canary *NginxCanaryStrategy `json:"canary,omitempty"`
+	// BlueGreen configures the "BlueGreen" strategy.
+	// +optional
+	BlueGreen *NginxBlueGreenStrategy `json:"blueGreen,omitempty"`
+}
+
+type NginxCanaryStrategy struct {
+	// Share is the number of replicas of the canary Deployment, as a
+	// percentage of the current replicas (rounded up). Its pods are behind
+	// the same Services. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Share *int32 `json:"share,omitempty"`
+	// BakeSeconds is how long, in seconds, the canary pods must stay ready
+	// before being promoted. Defaults to 300.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BakeSeconds *int32 `json:"bakeSeconds,omitempty"`
+}
+
+type NginxBlueGreenStrategy struct {
+	// KeepOldSeconds is how long, in seconds, the previous color keeps
+	// running after the Services are switched over, before being scaled
+	// down. Defaults to 300.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepOldSeconds *int32 `json:"keepOldSeconds,omitempty"`
+	// ManualPromotion, when set, only switches the Services over once the
+	// Nginx is annotated with "nginx.tsuru.io/promote=true".
+	// +optional
+	ManualPromotion bool `json:"manualPromotion,omitempty"`
+}
+
+type NginxMonitorKind string
+
+const (
+	NginxMonitorPodMonitor     = NginxMonitorKind("PodMonitor")
+	NginxMonitorServiceMonitor = NginxMonitorKind("ServiceMonitor")
+	NginxMonitorNone           = NginxMonitorKind("None")
+)
+
+type NginxMonitoring struct {
+	// Image of the nginx Prometheus exporter. Defaults to
+	// "nginx/nginx-prometheus-exporter:1.3.0".
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Resources of the exporter container.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Port where the exporter serves the metrics, named "metrics". Defaults
+	// to 9113.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// StubStatusPort is the port of the stub_status listener, named
+	// "stub-status", which only accepts connections from the pod itself.
+	// Custom configs must include "conf.d/*.conf" in their http block to
+	// enable it. Defaults to 8090.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	StubStatusPort int32 `json:"stubStatusPort,omitempty"`
+	// Monitor is the kind of the Prometheus Operator resource scraping the
+	// exporter, either "PodMonitor", "ServiceMonitor" or "None". It's only
+	// created when the Prometheus Operator CRDs are installed. Defaults to
+	// "PodMonitor".
+	// +kubebuilder:validation:Enum=PodMonitor;ServiceMonitor;None
+	// +optional
+	Monitor NginxMonitorKind `json:"monitor,omitempty"`
+	// Interval between the scrapes, e.g. "30s". Defaults to the Prometheus
+	// one.
+	// +optional
+	Interval string `json:"interval,omitempty"`
+	// Labels are extra labels for the monitor, e.g. to be selected by a
+	// Prometheus.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type NginxIngress struct {
+	// Annotations are extra annotations for the Ingress resource.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Labels are extra labels for the Ingress resource.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// IngressClassName is the class to be set on Ingress.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+}
+
+type NginxGateway struct {
+	// ParentRefs are the Gateways which the HTTPRoute attaches to.
+	// +kubebuilder:validation:MinItems=1
+	ParentRefs []NginxGatewayParentRef `json:"parentRefs"`
+	// Annotations are extra annotations for the HTTPRoute resource.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Labels are extra labels for the HTTPRoute resource.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type NginxGatewayParentRef struct {
+	// Name of the Gateway.
+	Name string `json:"name"`
+	// Namespace of the Gateway. Defaults to the Nginx namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// SectionName is the name of the Gateway listener to attach to. Defaults
+	// to all listeners which allow the route.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+type NginxService struct {
+	// Type is the type of the service. Defaults to the default service type value.
+	// +optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+	// LoadBalancerIP is an optional load balancer IP for the service.
+	// +optional
+	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
+	// Labels are extra labels for the service.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are extra annotations for the service.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// ExternalTrafficPolicy defines whether external traffic will be routed to
+	// node-local or cluster-wide endpoints. Defaults to the default Service
+	// externalTrafficPolicy value.
+	// +optional
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+	// UsePodSelector defines whether Service should automatically map the
+	// endpoints using the pod's label selector. Defaults to true.
+	// +optional
+	UsePodSelector *bool `json:"usePodSelector,omitempty"`
+	// ProxyProtocol makes a LoadBalancer Service target the container ports
+	// which accept PROXY protocol connections, instead of the http and https
+	// ones.
+	// +optional
+	ProxyProtocol *NginxProxyProtocol `json:"proxyProtocol,omitempty"`
+	// HTTPSOverHTTP makes the Service's https port target the nginx http port.
+	// Useful when TLS is terminated before reaching nginx, e.g. on the load
+	// balancer.
+	// +optional
+	HTTPSOverHTTP bool `json:"httpsOverHTTP,omitempty"`
+	// Ports exposed by the Service. Defaults to the http (80) and https (443)
+	// ports, targeting the nginx container ports of the same name.
+	// +optional
+	Ports []corev1.ServicePort `json:"ports,omitempty"`
+}
+
+type NginxNamedService struct {
+	// Name of the Service, appended to the Nginx name to build the Service
+	// object name. It must be unique among the Nginx's Services.
+	Name string `json:"name"`
+
+	NginxService `json:",inline"`
+}
+
+// NginxProxyProtocol names the container ports which accept PROXY protocol
+// connections.
+type NginxProxyProtocol struct {
+	// HTTPPort is the name of the container port serving HTTP behind PROXY
+	// protocol. It's exposed on the Service's port 80.
+	// +optional
+	HTTPPort string `json:"httpPort,omitempty"`
+	// HTTPSPort is the name of the container port serving HTTPS behind PROXY
+	// protocol. It's exposed on the Service's port 443.
+	// +optional
+	HTTPSPort string `json:"httpsPort,omitempty"`
+}
+
+// ConfigRef is a reference to a config object.
+type ConfigRef struct {
+	// Kind of the config object. Defaults to "ConfigMap".
+	Kind ConfigKind `json:"kind"`
+	// Name of the ConfigMap object with "nginx.conf" key inside. It must reside
+	// in the same Namespace as the Nginx resource. Required when Kind is "ConfigMap".
+	//
+	// It's mutually exclusive with Value field.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Value is the raw Nginx configuration. Required when Kind is "Inline" or
+	// "Template". When Kind is "Template", it's a Go text/template rendered by
+	// the operator, see k8s.ConfigTemplateData for the available data.
+	//
+	// It's mutually exclusive with Name field.
+	// +optional
+	Value string `json:"value,omitempty"`
+	// Servers are the virtual servers of the Nginx configuration generated by
+	// the operator. Required when Kind is "Structured".
+	//
+	// It's mutually exclusive with Name and Value fields.
+	// +optional
+	Servers []NginxServerConfig `json:"servers,omitempty"`
+}
+
+// NginxServerConfig is a virtual server, i.e. a "server" block.
+type NginxServerConfig struct {
+	// ServerNames are the virtual server names. Defaults to the catch-all
+	// server name "_".
+	// +optional
+	ServerNames []string `json:"serverNames,omitempty"`
+	// Listen are the container ports the server accepts requests on. Defaults
+	// to the "http" port.
+	// +optional
+	Listen []NginxListen `json:"listen,omitempty"`
+	// TLSSecretName is the name of the spec.tls Secret whose certificate is
+	// served on the SSL ports.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+	// Headers are added to the responses of every location.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// Locations of the server.
+	// +optional
+	Locations []NginxLocation `json:"locations,omitempty"`
+}
+
+// NginxListen describes a port a server accepts requests on.
+type NginxListen struct {
+	// Port is the name of the nginx container port, e.g. "http" or "https".
+	Port string `json:"port"`
+	// SSL enables TLS on the port.
+	// +optional
+	SSL bool `json:"ssl,omitempty"`
+	// HTTP2 enables the HTTP/2 protocol on the port.
+	// +optional
+	HTTP2 bool `json:"http2,omitempty"`
+	// ProxyProtocol accepts the PROXY protocol on the port.
+	// +optional
+	ProxyProtocol bool `json:"proxyProtocol,omitempty"`
+	// DefaultServer makes the server the default one of the port.
+	// +optional
+	DefaultServer bool `json:"defaultServer,omitempty"`
+}
+
+// NginxLocation is a "location" block. Exactly one of ProxyPass, Root,
+// Return and Redirect is expected.
+type NginxLocation struct {
+	// Path is the request URI matched by the location.
+	Path string `json:"path"`
+	// Modifier changes how Path is matched: "=" for exact match, "^~" for
+	// prefix match skipping regexes, "~" and "~*" for case-sensitive and
+	// case-insensitive regexes. Defaults to a prefix match.
+	// +kubebuilder:validation:Enum="=";"^~";"~";"~*"
+	// +optional
+	Modifier string `json:"modifier,omitempty"`
+	// ProxyPass is the URL requests are proxied to, e.g.
+	// "http://backend.default.svc:8080".
+	// +optional
+	ProxyPass string `json:"proxyPass,omitempty"`
+	// ProxyHeaders are set on the requests proxied to ProxyPass.
+	// +optional
+	ProxyHeaders map[string]string `json:"proxyHeaders,omitempty"`
+	// Root is the directory the static files are served from.
+	// +optional
+	Root string `json:"root,omitempty"`
+	// Headers are added to the responses, besides the server ones.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// Return responds with a fixed status code and body.
+	// +optional
+	Return *NginxReturn `json:"return,omitempty"`
+	// Redirect responds with a redirect to another URL.
+	// +optional
+	Redirect *NginxRedirect `json:"redirect,omitempty"`
+}
+
+type NginxReturn struct {
+	// Code is the HTTP status code.
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=599
+	Code int32 `json:"code"`
+	// Body of the response.
+	// +optional
+	Body string `json:"body,omitempty"`
+}
+
+type NginxRedirect struct {
+	// URL the requests are redirected to. It may contain nginx variables,
+	// e.g. "https://$host$request_uri".
+	URL string `json:"url"`
+	// Permanent uses the 301 status code instead of 302.
+	// +optional
+	Permanent bool `json:"permanent,omitempty"`
+}
+
+type ConfigKind string
+
+const (
+	// ConfigKindConfigMap is a Kind of configuration that points to a configmap
+	ConfigKindConfigMap = ConfigKind("ConfigMap")
+	// ConfigKindInline is a kinda of configuration that is setup as a annotation on the Pod
+	// and is inject as a file on the container using the Downward API.
+	ConfigKindInline = ConfigKind("Inline")
+	// ConfigKindTemplate is a Kind of configuration whose value is a Go
+	// text/template rendered by the operator, then setup the same way as the
+	// Inline one.
+	ConfigKindTemplate = ConfigKind("Template")
+	// ConfigKindStructured is a Kind of configuration generated by the
+	// operator from the Servers field, then setup the same way as the Inline
+	// one.
+	ConfigKindStructured = ConfigKind("Structured")
+)
+
+// FilesRef is a reference to arbitrary files stored into a ConfigMap in the
+// cluster.
+type FilesRef struct {
+	// Name points to a ConfigMap resource (in the same namespace) which holds
+	// the files.
+	Name string `json:"name"`
+	// Files maps each key entry from the ConfigMap to its relative location on
+	// the nginx filesystem.
+	// +optional
+	Files map[string]string `json:"files,omitempty"`
+}
+
+type NginxPodTemplateSpec struct {
+	// Affinity to be set on the nginx pod.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// NodeSelector to be set on the nginx pod.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Annotations are custom annotations to be set into Pod.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Labels are custom labels to be added into Pod.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// HostNetwork enabled causes the pod to use the host's network namespace.
+	// +optional
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+	// Ports is the list of ports used by nginx.
+	// +optional
+	Ports []corev1.ContainerPort `json:"ports,omitempty"`
+	// TerminationGracePeriodSeconds defines the max duration seconds which the
+	// pod needs to terminate gracefully. Defaults to pod's
+	// terminationGracePeriodSeconds default value.
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+	// PodSecurityContext configures security attributes for the nginx pod.
+	// +optional
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// ContainerSecurityContext configures security attributes for the nginx container.
+	// +optional
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+
+	// Volumes that will attach to nginx instances
+	// +optional
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+	// VolumeMounts will mount volume declared above in directories
+	// +optional
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+	// InitContainers are executed in order prior to containers being started
+	// More info: https://kubernetes.io/docs/concepts/workloads/pods/init-containers/
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+	// Containers are executed in parallel to the main nginx container
+	// +optional
+	Containers []corev1.Container `json:"containers,omitempty"`
+	// RollingUpdate defines params to control the desired behavior of rolling update.
+	// +optional
+	RollingUpdate *appsv1.RollingUpdateDeployment `json:"rollingUpdate,omitempty"`
+	// Tolerations defines list of taints that pod can tolerate.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// ServiceAccountName is the name of the ServiceAccount to use to run this nginx instance.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// TopologySpreadConstraints describes how a group of pods ought to spread across topology domains.
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+type NginxCacheSpec struct {
+	// InMemory if set to true creates a memory backed volume.
+	InMemory bool `json:"inMemory,omitempty"`
+	// Path is the mount path for the cache volume.
+	Path string `json:"path"`
+	// Size is the maximum size allowed for the cache volume.
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+}
+
+type NginxLifecycle struct {
+	PostStart *NginxLifecycleHandler `json:"postStart,omitempty"`
+	PreStop   *NginxLifecycleHandler `json:"preStop,omitempty"`
+}
+
+type NginxLifecycleHandler struct {
+	Exec *corev1.ExecAction `json:"exec,omitempty"`
+}
+
+// NginxStatus defines the observed state of Nginx
+type NginxStatus struct {
+	// ObservedGeneration is the most recent generation observed for this
+	// Nginx. It corresponds to the Nginx's generation, which is updated on
+	// mutation by the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// CurrentReplicas is the last observed number from the NGINX object.
+	CurrentReplicas int32 `json:"currentReplicas,omitempty"`
+	// PodSelector is the NGINX's pod label selector.
+	PodSelector string `json:"podSelector,omitempty"`
+	// CurrentRevision is the number of the ControllerRevision which records
+	// the current spec.
+	// +optional
+	CurrentRevision int64 `json:"currentRevision,omitempty"`
+	// PreviousRevision is the number of the ControllerRevision which records
+	// the spec applied before the current one.
+	// +optional
+	PreviousRevision int64 `json:"previousRevision,omitempty"`
+
+	Deployments []DeploymentStatus `json:"deployments,omitempty"`
+	Services    []ServiceStatus    `json:"services,omitempty"`
+	Ingresses   []IngressStatus    `json:"ingresses,omitempty"`
+	Routes      []RouteStatus      `json:"routes,omitempty"`
+
+	// Conditions represent the latest available observations of the Nginx's
+	// current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// ConditionReady indicates whether the Nginx is fully operational, i.e. all
+	// the other conditions are in their healthy state.
+	ConditionReady = "Ready"
+	// ConditionDeploymentAvailable indicates whether the Nginx's Deployment has
+	// the minimum number of available replicas.
+	ConditionDeploymentAvailable = "DeploymentAvailable"
+	// ConditionServiceReady indicates whether the Nginx's Service has been
+	// successfully reconciled and, when it's a LoadBalancer, has an address.
+	ConditionServiceReady = "ServiceReady"
+	// ConditionIngressReady indicates whether the Nginx's Ingresses have been
+	// successfully reconciled.
+	ConditionIngressReady = "IngressReady"
+	// ConditionConfigValid indicates whether the Nginx's spec could be
+	// rendered into a valid pod template.
+	ConditionConfigValid = "ConfigValid"
+	// ConditionProgressing indicates whether a rollout of the Nginx's
+	// Deployment is in progress.
+	ConditionProgressing = "Progressing"
+	// ConditionRouteAccepted indicates whether the Nginx's HTTPRoute has been
+	// accepted by all of its parent Gateways.
+	ConditionRouteAccepted = "RouteAccepted"
+	// ConditionCertificatesReady indicates whether the certificates requested
+	// to cert-manager have been issued.
+	ConditionCertificatesReady = "CertificatesReady"
+	// ConditionRolledBack indicates whether the Nginx's Deployment was rolled
+	// back to the last known good spec, as the rollout of the current spec
+	// exceeded its progress deadline.
+	ConditionRolledBack = "RolledBack"
+)
+
+type DeploymentStatus struct {
+	// Name is the name of the Deployment created by nginx
+	Name string `json:"name"`
+	// Canary is the state of the canary rollout, only set on the canary
+	// Deployment.
+	// +optional
+	Canary *CanaryStatus `json:"canary,omitempty"`
+	// Color of the Deployment, when using the blue/green strategy.
+	// +optional
+	Color string `json:"color,omitempty"`
+	// Active tells whether the Services send traffic to the Deployment, when
+	// using the blue/green strategy.
+	// +optional
+	Active bool `json:"active,omitempty"`
+	// ScaleDownAt is when the previous color is scaled down, once the
+	// Services were switched over to the other one.
+	// +optional
+	ScaleDownAt *metav1.Time `json:"scaleDownAt,omitempty"`
+}
+
+type CanaryPhase string
+
+const (
+	// CanaryPhaseProgressing means the canary pods are rolling out.
+	CanaryPhaseProgressing = CanaryPhase("Progressing")
+	// CanaryPhaseBaking means the canary pods are ready, waiting for the
+	// bake time to be promoted.
+	CanaryPhaseBaking = CanaryPhase("Baking")
+	// CanaryPhaseAborted means the canary pods failed to become (or stay)
+	// ready, thus the spec wasn't promoted.
+	CanaryPhaseAborted = CanaryPhase("Aborted")
+)
+
+type CanaryStatus struct {
+	// Phase of the canary rollout.
+	Phase CanaryPhase `json:"phase"`
+	// Replicas is the number of canary pods.
+	Replicas int32 `json:"replicas"`
+	// ReadyReplicas is the number of canary pods which are ready.
+	ReadyReplicas int32 `json:"readyReplicas"`
+	// PromoteAt is when the canary is promoted, while baking.
+	// +optional
+	PromoteAt *metav1.Time `json:"promoteAt,omitempty"`
+	// Message details the phase, e.g. why the canary was aborted.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type ServiceStatus struct {
+	// Name is the name of the Service created by nginx
+	Name      string   `json:"name"`
+	IPs       []string `json:"ips,omitempty"`
+	Hostnames []string `json:"hostnames,omitempty"`
+}
+
+type IngressStatus struct {
+	// Name is the name of the Ingress created by nginx
+	Name      string   `json:"name"`
+	IPs       []string `json:"ips,omitempty"`
+	Hostnames []string `json:"hostnames,omitempty"`
+}
+
+type RouteStatus struct {
+	// Name is the name of the HTTPRoute created by nginx
+	Name string `json:"name"`
+	// Parents describes the status of the HTTPRoute on each parent Gateway.
+	Parents []RouteParentStatus `json:"parents,omitempty"`
+}
+
+type RouteParentStatus struct {
+	// Name of the Gateway.
+	Name string `json:"name"`
+	// Namespace of the Gateway.
+	Namespace string `json:"namespace"`
+	// SectionName is the Gateway listener the status refers to, if any.
+	SectionName string `json:"sectionName,omitempty"`
+	// Accepted tells whether the Gateway accepted the HTTPRoute.
+	Accepted bool `json:"accepted"`
+	// Reason of the Gateway's Accepted condition.
+	Reason string `json:"reason,omitempty"`
+	// Message of the Gateway's Accepted condition.
+	Message string `json:"message,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Nginx{}, &NginxList{})
+}
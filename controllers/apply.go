@@ -0,0 +1,126 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+// fieldManager is the field manager of the server-side applied resources.
+const fieldManager = "nginx-operator"
+
+// apply creates or updates the object through server-side apply. Fields
+// which are no longer set are removed from the object, as long as they were
+// set by the operator, while the ones owned by others are kept untouched.
+//
+// The current object, if any, is used to take over the fields set by former
+// releases of the operator.
+func (r *NginxReconciler) apply(ctx context.Context, obj, current client.Object) error {
+	if current != nil {
+		if err := r.upgradeManagedFields(ctx, current, obj); err != nil {
+			return err
+		}
+	}
+
+	return r.Client.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership)
+}
+
+// upgradeManagedFields hands the fields set by former releases of the
+// operator, which used create/update requests, over to its server-side apply
+// field manager. Otherwise, they'd never be removed from the object.
+//
+// NOTE: former releases used the default field manager, i.e. the binary name,
+// which is the same as fieldManager. Their update requests carried the whole
+// object, so besides the fields the operator sets, they also own fields set
+// by others (e.g. the replicas set by the autoscaler and the labels added by
+// other controllers). Those are left out of the applied fields, as otherwise
+// the next apply would remove them from the object. The annotations are kept
+// though, so the ones the operator set but no longer desires (e.g. removed
+// from spec.service.annotations) are removed by the next apply.
+func (r *NginxReconciler) upgradeManagedFields(ctx context.Context, obj, desired client.Object) error {
+	managedFields := obj.GetManagedFields()
+
+	index := -1
+	for i, mf := range managedFields {
+		if mf.Manager != fieldManager || mf.Subresource != "" {
+			continue
+		}
+
+		if mf.Operation == metav1.ManagedFieldsOperationApply {
+			return nil
+		}
+
+		if index < 0 && mf.Operation == metav1.ManagedFieldsOperationUpdate {
+			index = i
+		}
+	}
+
+	if index < 0 {
+		return nil
+	}
+
+	patch := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+
+	upgraded := make([]metav1.ManagedFieldsEntry, len(managedFields))
+	copy(upgraded, managedFields)
+	upgraded[index].Operation = metav1.ManagedFieldsOperationApply
+
+	if fields := upgraded[index].FieldsV1; fields != nil {
+		applied, err := appliedFields(fields, obj, desired)
+		if err != nil {
+			return fmt.Errorf("failed to upgrade managed fields of %s: %w", obj.GetName(), err)
+		}
+		upgraded[index].FieldsV1 = applied
+	}
+
+	obj.SetManagedFields(upgraded)
+
+	if err := r.Client.Patch(ctx, obj, patch); err != nil {
+		return fmt.Errorf("failed to upgrade managed fields of %s: %w", obj.GetName(), err)
+	}
+
+	return nil
+}
+
+// appliedFields returns the fields updated by the operator but the ones it
+// doesn't apply: the replicas, unless desired, and the labels not found in the
+// desired object.
+func appliedFields(fields *metav1.FieldsV1, current, desired client.Object) (*metav1.FieldsV1, error) {
+	set := &fieldpath.Set{}
+	if err := set.FromJSON(bytes.NewReader(fields.Raw)); err != nil {
+		return nil, err
+	}
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	dropped := fieldpath.NewSet()
+	if _, found, _ := unstructured.NestedFieldNoCopy(u, "spec", "replicas"); !found {
+		dropped.Insert(fieldpath.MakePathOrDie("spec", "replicas"))
+	}
+
+	for key := range current.GetLabels() {
+		if _, found := desired.GetLabels()[key]; !found {
+			dropped.Insert(fieldpath.MakePathOrDie("metadata", "labels", key))
+		}
+	}
+
+	raw, err := set.Difference(dropped).ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return &metav1.FieldsV1{Raw: raw}, nil
+}
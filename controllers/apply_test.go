@@ -0,0 +1,406 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+// applyClient emulates server-side apply on top of the fake client, which
+// doesn't support it. Likewise "kubectl apply", the applied object is
+// three-way merged with the last applied one and the current object, so
+// fields no longer applied are removed while the others are kept untouched.
+type applyClient struct {
+	client.Client
+	applied map[string][]byte
+}
+
+// withServerSideApply wraps the client, considering the given objects as
+// previously applied by the operator.
+func withServerSideApply(c client.Client, applied ...client.Object) client.Client {
+	ac := &applyClient{Client: c, applied: make(map[string][]byte)}
+	for _, obj := range applied {
+		key, err := ac.key(obj)
+		if err != nil {
+			panic(err)
+		}
+
+		data, err := json.Marshal(obj)
+		if err != nil {
+			panic(err)
+		}
+
+		ac.applied[key] = data
+	}
+	return ac
+}
+
+func (c *applyClient) key(obj client.Object) (string, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/%s/%s", gvk, obj.GetNamespace(), obj.GetName()), nil
+}
+
+func (c *applyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+
+	modified, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+
+	key, err := c.key(obj)
+	if err != nil {
+		return err
+	}
+
+	current := obj.DeepCopyObject().(client.Object)
+	err = c.Client.Get(ctx, client.ObjectKeyFromObject(obj), current)
+	if errors.IsNotFound(err) {
+		if err = c.Client.Create(ctx, obj); err != nil {
+			return err
+		}
+
+		c.applied[key] = modified
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	original, found := c.applied[key]
+	if !found {
+		original = []byte("{}")
+	}
+
+	// NOTE: unlike server-side apply, a three-way merge removes the whole map
+	// when it's no longer applied, dropping the keys set by others as well.
+	if modified, err = keepMetadataMaps(modified); err != nil {
+		return err
+	}
+
+	currentData, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	merged, err := threeWayMerge(current, original, modified, currentData)
+	if err != nil {
+		return err
+	}
+
+	var updated runtime.Object = &unstructured.Unstructured{}
+	if _, isUnstructured := obj.(*unstructured.Unstructured); !isUnstructured {
+		if updated, err = c.Scheme().New(gvk); err != nil {
+			return err
+		}
+	}
+
+	if err = json.Unmarshal(merged, updated); err != nil {
+		return err
+	}
+
+	if svc, ok := updated.(*corev1.Service); ok {
+		dropServiceTypeDependentFields(svc, current.(*corev1.Service))
+	}
+
+	if err = c.Client.Update(ctx, updated.(client.Object)); err != nil {
+		return err
+	}
+
+	c.applied[key] = modified
+	return c.Client.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+}
+
+// threeWayMerge merges the applied object into the current one. Custom
+// resources (e.g. the Prometheus Operator monitors) have no patch strategies,
+// so they're merged as JSON.
+func threeWayMerge(current client.Object, original, modified, currentData []byte) ([]byte, error) {
+	if _, isUnstructured := current.(*unstructured.Unstructured); isUnstructured {
+		patch, err := jsonmergepatch.CreateThreeWayJSONMergePatch(original, modified, currentData)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonpatch.MergePatch(currentData, patch)
+	}
+
+	patchMeta, err := strategicpatch.NewPatchMetaFromStruct(current)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := strategicpatch.CreateThreeWayMergePatch(original, modified, currentData, patchMeta, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return strategicpatch.StrategicMergePatchUsingLookupPatchMeta(currentData, patch, patchMeta)
+}
+
+// keepMetadataMaps sets the metadata labels and annotations as empty maps,
+// when missing, so only the keys previously applied are removed.
+func keepMetadataMaps(data []byte) ([]byte, error) {
+	var obj map[string]any
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	metadata, _ := obj["metadata"].(map[string]any)
+	if metadata == nil {
+		metadata = make(map[string]any)
+		obj["metadata"] = metadata
+	}
+
+	for _, field := range []string{"labels", "annotations"} {
+		if _, found := metadata[field]; !found {
+			metadata[field] = map[string]any{}
+		}
+	}
+
+	return json.Marshal(obj)
+}
+
+// dropServiceTypeDependentFields mimics the API server, which drops the fields
+// no longer valid for the Service type (e.g. node ports when it changes from
+// LoadBalancer to ClusterIP).
+func dropServiceTypeDependentFields(svc, old *corev1.Service) {
+	if svc.Spec.Type == old.Spec.Type {
+		return
+	}
+
+	if svc.Spec.Type != corev1.ServiceTypeNodePort && svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		for i := range svc.Spec.Ports {
+			svc.Spec.Ports[i].NodePort = 0
+		}
+
+		svc.Spec.ExternalTrafficPolicy = ""
+	}
+
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		svc.Spec.HealthCheckNodePort = 0
+	}
+}
+
+func TestNginxReconciler_apply_removesFieldsNoLongerDesired(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+		Spec: v1beta1.NginxSpec{
+			Image: "nginx:stable",
+			PodTemplate: v1beta1.NginxPodTemplateSpec{
+				Annotations: map[string]string{"example.com/pod": "v1"},
+			},
+			Service: &v1beta1.NginxService{
+				Type:        corev1.ServiceTypeClusterIP,
+				Annotations: map[string]string{"example.com/service": "v1"},
+			},
+			Ingress: &v1beta1.NginxIngress{
+				Annotations: map[string]string{"example.com/ingress": "v1"},
+			},
+		},
+	}
+
+	c := withServerSideApply(fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		Build())
+
+	r := &NginxReconciler{Client: c, EventRecorder: record.NewFakeRecorder(10)}
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	require.NoError(t, r.reconcileService(context.TODO(), nginx))
+	require.NoError(t, r.reconcileIngress(context.TODO(), nginx))
+
+	// other controllers setting their own fields
+	var svc corev1.Service
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-service", Namespace: "default"}, &svc))
+	svc.Annotations["cloud.example.com/status"] = "ok"
+	require.NoError(t, c.Update(context.TODO(), &svc))
+
+	var ing networkingv1.Ingress
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &ing))
+	ing.Annotations["cloud.example.com/status"] = "ok"
+	require.NoError(t, c.Update(context.TODO(), &ing))
+
+	nginx.Spec.Image = "nginx:latest"
+	nginx.Spec.PodTemplate.Annotations = nil
+	nginx.Spec.Service.Annotations = nil
+	nginx.Spec.Ingress.Annotations = nil
+
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	require.NoError(t, r.reconcileService(context.TODO(), nginx))
+	require.NoError(t, r.reconcileIngress(context.TODO(), nginx))
+
+	var dep appsv1.Deployment
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &dep))
+	assert.NotContains(t, dep.Spec.Template.Annotations, "example.com/pod")
+
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-service", Namespace: "default"}, &svc))
+	assert.Equal(t, map[string]string{"cloud.example.com/status": "ok"}, svc.Annotations)
+
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &ing))
+	assert.Equal(t, map[string]string{"cloud.example.com/status": "ok"}, ing.Annotations)
+}
+
+func TestNginxReconciler_upgradeManagedFields(t *testing.T) {
+	tests := map[string]struct {
+		managedFields []metav1.ManagedFieldsEntry
+		expected      []metav1.ManagedFieldsEntry
+	}{
+		"without fields set by the operator": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate},
+			},
+			expected: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate},
+			},
+		},
+
+		"fields set by former releases": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate},
+				{Manager: "nginx-operator", Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status"},
+				{Manager: "nginx-operator", Operation: metav1.ManagedFieldsOperationUpdate},
+			},
+			expected: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate},
+				{Manager: "nginx-operator", Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status"},
+				{Manager: "nginx-operator", Operation: metav1.ManagedFieldsOperationApply},
+			},
+		},
+
+		"fields already applied": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "nginx-operator", Operation: metav1.ManagedFieldsOperationApply},
+				{Manager: "nginx-operator", Operation: metav1.ManagedFieldsOperationUpdate},
+			},
+			expected: []metav1.ManagedFieldsEntry{
+				{Manager: "nginx-operator", Operation: metav1.ManagedFieldsOperationApply},
+				{Manager: "nginx-operator", Operation: metav1.ManagedFieldsOperationUpdate},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-service", Namespace: "default", ManagedFields: tt.managedFields},
+			}
+
+			c := fake.NewClientBuilder().
+				WithScheme(newScheme()).
+				WithObjects(svc).
+				Build()
+
+			var current corev1.Service
+			require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-service", Namespace: "default"}, &current))
+
+			r := &NginxReconciler{Client: c}
+			require.NoError(t, r.upgradeManagedFields(context.TODO(), &current, &corev1.Service{}))
+
+			var got corev1.Service
+			require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-service", Namespace: "default"}, &got))
+			assert.Equal(t, tt.expected, got.ManagedFields)
+		})
+	}
+}
+
+func TestNginxReconciler_upgradeManagedFields_fieldsNotApplied(t *testing.T) {
+	updated := `{
+		"f:metadata": {
+			"f:annotations": {".": {}, "f:nginx.tsuru.io/generated-from": {}, "f:example.com/removed-from-spec": {}},
+			"f:labels": {".": {}, "f:nginx.tsuru.io/app": {}, "f:example.com/cost-center": {}}
+		},
+		"f:spec": {
+			"f:replicas": {},
+			"f:template": {"f:spec": {"f:containers": {}}}
+		}
+	}`
+
+	current := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-nginx",
+			Namespace:   "default",
+			Labels:      map[string]string{"nginx.tsuru.io/app": "nginx", "example.com/cost-center": "42"},
+			Annotations: map[string]string{"nginx.tsuru.io/generated-from": "{}", "example.com/removed-from-spec": "stale"},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "nginx-operator", Operation: metav1.ManagedFieldsOperationUpdate, FieldsType: "FieldsV1", FieldsV1: &metav1.FieldsV1{Raw: []byte(updated)}},
+			},
+		},
+		Spec: appsv1.DeploymentSpec{Replicas: func(n int32) *int32 { return &n }(int32(5))},
+	}
+
+	desired := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-nginx",
+			Namespace:   "default",
+			Labels:      map[string]string{"nginx.tsuru.io/app": "nginx"},
+			Annotations: map[string]string{"nginx.tsuru.io/generated-from": "{}"},
+		},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		WithObjects(current).
+		Build()
+
+	var dep appsv1.Deployment
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &dep))
+
+	r := &NginxReconciler{Client: c}
+	require.NoError(t, r.upgradeManagedFields(context.TODO(), &dep, desired))
+
+	var got appsv1.Deployment
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &got))
+	require.Len(t, got.ManagedFields, 1)
+	assert.Equal(t, metav1.ManagedFieldsOperationApply, got.ManagedFields[0].Operation)
+	// NOTE: the stale annotation is kept, so the next apply removes it.
+	assert.JSONEq(t, `{
+		"f:metadata": {
+			"f:annotations": {".": {}, "f:nginx.tsuru.io/generated-from": {}, "f:example.com/removed-from-spec": {}},
+			"f:labels": {".": {}, "f:nginx.tsuru.io/app": {}}
+		},
+		"f:spec": {
+			"f:template": {"f:spec": {"f:containers": {}}}
+		}
+	}`, string(got.ManagedFields[0].FieldsV1.Raw))
+
+	desired.Spec.Replicas = func(n int32) *int32 { return &n }(int32(5))
+	applied, err := appliedFields(&metav1.FieldsV1{Raw: []byte(updated)}, &dep, desired)
+	require.NoError(t, err)
+	assert.Contains(t, string(applied.Raw), `"f:replicas"`)
+}
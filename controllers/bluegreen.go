@@ -0,0 +1,294 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+const (
+	// promoteAnnotation, set to "true" on the Nginx, switches the Services
+	// over to the idle color once it's ready, when the blue/green strategy
+	// requires manual promotion. It's removed once handled.
+	promoteAnnotation = "nginx.tsuru.io/promote"
+
+	defaultKeepOldSeconds = 300
+)
+
+func isBlueGreenStrategy(nginx *nginxv1beta1.Nginx) bool {
+	return nginx.Spec.Strategy != nil && nginx.Spec.Strategy.Type == nginxv1beta1.NginxStrategyBlueGreen
+}
+
+func keepOldTime(nginx *nginxv1beta1.Nginx) time.Duration {
+	seconds := int32(defaultKeepOldSeconds)
+	if s := nginx.Spec.Strategy; s != nil && s.BlueGreen != nil && s.BlueGreen.KeepOldSeconds != nil {
+		seconds = *s.BlueGreen.KeepOldSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func isPromotionAllowed(nginx *nginxv1beta1.Nginx) bool {
+	if s := nginx.Spec.Strategy; s != nil && s.BlueGreen != nil && s.BlueGreen.ManualPromotion {
+		return nginx.Annotations[promoteAnnotation] == "true"
+	}
+	return true
+}
+
+// reconcileBlueGreen rolls out the desired Deployment to the idle color and,
+// once all of its pods are ready, switches the Services over to it. The
+// previous color is scaled down after the keep time.
+//
+// NOTE: the Nginx's Deployment is replaced by the blue and green ones, the
+// former is only deleted once the Services are switched over to a color. Until
+// then, the Services select the pods of both.
+func (r *NginxReconciler) reconcileBlueGreen(ctx context.Context, nginx *nginxv1beta1.Nginx, desired *appsv1.Deployment) error {
+	hash, err := k8s.PodTemplateHash(&desired.Spec.Template)
+	if err != nil {
+		return err
+	}
+
+	active, err := r.activeColor(ctx, nginx)
+	if err != nil {
+		return err
+	}
+
+	target := k8s.ColorBlue
+	if active != "" {
+		activeDeploy, err := r.getDeployment(ctx, nginx.Namespace, k8s.ColorDeploymentName(nginx, active))
+		if err != nil {
+			return err
+		}
+
+		target = active
+		if activeDeploy == nil || activeDeploy.Annotations[k8s.TemplateHashAnnotation] != hash {
+			target = k8s.OtherColor(active)
+		}
+	}
+
+	current, err := r.getDeployment(ctx, nginx.Namespace, k8s.ColorDeploymentName(nginx, target))
+	if err != nil {
+		return err
+	}
+
+	newDeploy, err := k8s.NewColorDeployment(nginx, desired, target)
+	if err != nil {
+		return fmt.Errorf("failed to build %s Deployment: %w", target, err)
+	}
+
+	if target == active {
+		newDeploy.Annotations[k8s.PromotedAtAnnotation] = current.Annotations[k8s.PromotedAtAnnotation]
+
+		// NOTE: changes other than the pod template (e.g. replicas) are
+		// applied in place.
+		if !sameAnnotation(current, newDeploy, k8s.GeneratedFromAnnotation) {
+			if err = r.apply(ctx, newDeploy, current); err != nil {
+				return fmt.Errorf("failed to apply %s Deployment: %w", target, err)
+			}
+		}
+
+		return r.scaleDownIdleColor(ctx, nginx, current)
+	}
+
+	if current == nil || !sameAnnotation(current, newDeploy, k8s.TemplateHashAnnotation) ||
+		!sameAnnotation(current, newDeploy, k8s.GeneratedFromAnnotation) || replicasOf(current) != replicasOf(newDeploy) {
+		if r.ConfigTest {
+			passed, err := r.testConfig(ctx, nginx, desired)
+			if err != nil || !passed {
+				return err
+			}
+		}
+
+		var live client.Object
+		if current != nil {
+			live = current
+		}
+
+		if err = r.apply(ctx, newDeploy, live); err != nil {
+			return fmt.Errorf("failed to apply %s Deployment: %w", target, err)
+		}
+
+		r.EventRecorder.Eventf(nginx, corev1.EventTypeNormal, "BlueGreenStarted", "%s Deployment %s is being rolled out with the new spec", target, newDeploy.Name)
+		return nil
+	}
+
+	if !isRolloutComplete(current) || !isPromotionAllowed(nginx) {
+		return nil
+	}
+
+	services := k8s.NewServices(nginx)
+	k8s.SetServicesColor(services, target)
+	for _, svc := range services {
+		if err = r.reconcileServiceObject(ctx, nginx, svc); err != nil {
+			return err
+		}
+	}
+
+	newDeploy.Annotations[k8s.PromotedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	if err = r.apply(ctx, newDeploy, current); err != nil {
+		return fmt.Errorf("failed to apply %s Deployment: %w", target, err)
+	}
+
+	r.EventRecorder.Eventf(nginx, corev1.EventTypeNormal, "BlueGreenPromoted", "Services switched over to the %s Deployment %s", target, newDeploy.Name)
+
+	if _, found := nginx.Annotations[promoteAnnotation]; found {
+		delete(nginx.Annotations, promoteAnnotation)
+		if err = r.Client.Update(ctx, nginx); err != nil {
+			return fmt.Errorf("failed to update Nginx: %w", err)
+		}
+	}
+
+	former, err := r.getDeployment(ctx, nginx.Namespace, nginx.Name)
+	if err != nil || former == nil || !metav1.IsControlledBy(former, nginx) {
+		return err
+	}
+
+	if err = r.Client.Delete(ctx, former); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete Deployment: %w", err)
+	}
+
+	return nil
+}
+
+// scaleDownIdleColor scales the Deployment of the idle color down once the
+// keep time since the active one was promoted is over.
+func (r *NginxReconciler) scaleDownIdleColor(ctx context.Context, nginx *nginxv1beta1.Nginx, active *appsv1.Deployment) error {
+	at := scaleDownAt(active, keepOldTime(nginx))
+	if at == nil || time.Now().Before(at.Time) {
+		return nil
+	}
+
+	idle, err := r.getDeployment(ctx, nginx.Namespace, k8s.ColorDeploymentName(nginx, k8s.OtherColor(active.Labels[k8s.ColorLabel])))
+	if err != nil || idle == nil || replicasOf(idle) == 0 {
+		return err
+	}
+
+	// NOTE: replicas are taken back by the next apply of the Deployment,
+	// i.e. once it rolls out a new spec.
+	patch := client.MergeFrom(idle.DeepCopy())
+	idle.Spec.Replicas = func(n int32) *int32 { return &n }(0)
+	if err = r.Client.Patch(ctx, idle, patch); err != nil {
+		return fmt.Errorf("failed to scale down Deployment: %w", err)
+	}
+
+	r.EventRecorder.Eventf(nginx, corev1.EventTypeNormal, "BlueGreenScaledDown", "previous %s Deployment %s scaled down", idle.Labels[k8s.ColorLabel], idle.Name)
+	return nil
+}
+
+// scaleDownAt returns when the idle color is scaled down, given the active
+// Deployment.
+func scaleDownAt(active *appsv1.Deployment, keep time.Duration) *metav1.Time {
+	promotedAt, err := time.Parse(time.RFC3339, active.Annotations[k8s.PromotedAtAnnotation])
+	if err != nil {
+		return nil
+	}
+
+	at := metav1.NewTime(promotedAt.Add(keep))
+	return &at
+}
+
+// activeColor returns the color selected by the Nginx's Services, if any.
+// When none of them selects pods (i.e. usePodSelector is disabled), it's the
+// color last promoted instead.
+func (r *NginxReconciler) activeColor(ctx context.Context, nginx *nginxv1beta1.Nginx) (string, error) {
+	for _, desired := range k8s.NewServices(nginx) {
+		if desired.Spec.Selector == nil {
+			continue
+		}
+
+		var svc corev1.Service
+		err := r.Client.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: nginx.Namespace}, &svc)
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("failed to retrieve Service: %w", err)
+		}
+
+		return svc.Spec.Selector[k8s.ColorLabel], nil
+	}
+
+	return r.promotedColor(ctx, nginx)
+}
+
+// promotedColor returns the color whose Deployment was promoted last, if any.
+func (r *NginxReconciler) promotedColor(ctx context.Context, nginx *nginxv1beta1.Nginx) (string, error) {
+	var color string
+	var promotedAt time.Time
+	for _, c := range []string{k8s.ColorBlue, k8s.ColorGreen} {
+		dep, err := r.getDeployment(ctx, nginx.Namespace, k8s.ColorDeploymentName(nginx, c))
+		if err != nil {
+			return "", err
+		}
+
+		if dep == nil {
+			continue
+		}
+
+		at, err := time.Parse(time.RFC3339, dep.Annotations[k8s.PromotedAtAnnotation])
+		if err == nil && at.After(promotedAt) {
+			color, promotedAt = c, at
+		}
+	}
+
+	return color, nil
+}
+
+// desiredServices returns the Services of the Nginx, which select the active
+// color when using the blue/green strategy.
+func (r *NginxReconciler) desiredServices(ctx context.Context, nginx *nginxv1beta1.Nginx) ([]*corev1.Service, error) {
+	services := k8s.NewServices(nginx)
+	if !isBlueGreenStrategy(nginx) {
+		return services, nil
+	}
+
+	active, err := r.activeColor(ctx, nginx)
+	if err != nil {
+		return nil, err
+	}
+
+	if active != "" {
+		k8s.SetServicesColor(services, active)
+	}
+
+	return services, nil
+}
+
+func (r *NginxReconciler) getDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error) {
+	var dep appsv1.Deployment
+	err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &dep)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve Deployment: %w", err)
+	}
+
+	return &dep, nil
+}
+
+func sameAnnotation(a, b *appsv1.Deployment, key string) bool {
+	return a.Annotations[key] == b.Annotations[key]
+}
+
+func replicasOf(d *appsv1.Deployment) int32 {
+	if d.Spec.Replicas == nil {
+		return 1
+	}
+	return *d.Spec.Replicas
+}
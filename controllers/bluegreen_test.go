@@ -0,0 +1,277 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+func TestNginxReconciler_reconcileDeployment_blueGreen(t *testing.T) {
+	c := withServerSideApply(fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		WithObjects(&v1beta1.Nginx{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+			Spec: v1beta1.NginxSpec{
+				Image:    "nginx:1.24",
+				Replicas: func(n int32) *int32 { return &n }(int32(2)),
+			},
+		}).
+		Build())
+
+	var nginx v1beta1.Nginx
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &nginx))
+
+	er := record.NewFakeRecorder(100)
+	r := &NginxReconciler{Client: c, EventRecorder: er}
+	reconcile := func() {
+		require.NoError(t, r.reconcileDeployment(context.TODO(), &nginx))
+		require.NoError(t, r.reconcileService(context.TODO(), &nginx))
+	}
+
+	// rolling update Deployment, replaced once switched over to a color
+	reconcile()
+	getDeployment(t, c)
+
+	nginx.Spec.Strategy = &v1beta1.NginxStrategy{
+		Type:      v1beta1.NginxStrategyBlueGreen,
+		BlueGreen: &v1beta1.NginxBlueGreenStrategy{KeepOldSeconds: func(n int32) *int32 { return &n }(int32(60))},
+	}
+	reconcile()
+
+	blue := getColorDeployment(t, c, "blue")
+	assert.Equal(t, "nginx:1.24", blue.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "blue", blue.Spec.Selector.MatchLabels["nginx.tsuru.io/color"])
+	assert.Equal(t, "blue", blue.Spec.Template.Labels["nginx.tsuru.io/color"])
+	assert.Equal(t, "", getServiceColor(t, c))
+
+	completeRollout(t, c, blue)
+	reconcile()
+	assert.Equal(t, "blue", getServiceColor(t, c))
+	assert.NotEmpty(t, getColorDeployment(t, c, "blue").Annotations[k8s.PromotedAtAnnotation])
+	err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &appsv1.Deployment{})
+	assert.True(t, errors.IsNotFound(err))
+
+	nginx.Spec.Image = "nginx:1.25"
+	reconcile()
+	green := getColorDeployment(t, c, "green")
+	assert.Equal(t, "nginx:1.25", green.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, int32(2), *green.Spec.Replicas)
+	assert.Equal(t, "nginx:1.24", getColorDeployment(t, c, "blue").Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "blue", getServiceColor(t, c))
+
+	completeRollout(t, c, green)
+	reconcile()
+	assert.Equal(t, "green", getServiceColor(t, c))
+
+	// previous color is kept for a while
+	reconcile()
+	assert.Equal(t, int32(2), *getColorDeployment(t, c, "blue").Spec.Replicas)
+
+	green = getColorDeployment(t, c, "green")
+	green.Annotations[k8s.PromotedAtAnnotation] = time.Now().Add(-2 * time.Minute).UTC().Format(time.RFC3339)
+	require.NoError(t, c.Update(context.TODO(), green))
+	reconcile()
+	assert.Equal(t, int32(0), *getColorDeployment(t, c, "blue").Spec.Replicas)
+
+	// changes other than the pod template are applied in place
+	nginx.Spec.Replicas = func(n int32) *int32 { return &n }(int32(3))
+	reconcile()
+	assert.Equal(t, int32(3), *getColorDeployment(t, c, "green").Spec.Replicas)
+	assert.Equal(t, int32(0), *getColorDeployment(t, c, "blue").Spec.Replicas)
+
+	nginx.Spec.Strategy.BlueGreen.ManualPromotion = true
+	nginx.Spec.Image = "nginx:1.26"
+	reconcile()
+	blue = getColorDeployment(t, c, "blue")
+	assert.Equal(t, "nginx:1.26", blue.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, int32(3), *blue.Spec.Replicas)
+
+	completeRollout(t, c, blue)
+	reconcile()
+	assert.Equal(t, "green", getServiceColor(t, c))
+
+	nginx.Annotations = map[string]string{"nginx.tsuru.io/promote": "true"}
+	reconcile()
+	assert.Equal(t, "blue", getServiceColor(t, c))
+
+	var stored v1beta1.Nginx
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &stored))
+	assert.NotContains(t, stored.Annotations, "nginx.tsuru.io/promote")
+
+	close(er.Events)
+	var events []string
+	for event := range er.Events {
+		if event != "Normal ServiceUpdated service updated successfully" && event != "Normal ServiceCreated service created successfully" {
+			events = append(events, event)
+		}
+	}
+	assert.Equal(t, []string{
+		"Normal BlueGreenStarted blue Deployment my-nginx-blue is being rolled out with the new spec",
+		"Normal BlueGreenPromoted Services switched over to the blue Deployment my-nginx-blue",
+		"Normal BlueGreenStarted green Deployment my-nginx-green is being rolled out with the new spec",
+		"Normal BlueGreenPromoted Services switched over to the green Deployment my-nginx-green",
+		"Normal BlueGreenScaledDown previous blue Deployment my-nginx-blue scaled down",
+		"Normal BlueGreenStarted blue Deployment my-nginx-blue is being rolled out with the new spec",
+		"Normal BlueGreenPromoted Services switched over to the blue Deployment my-nginx-blue",
+	}, events)
+}
+
+func TestNginxReconciler_reconcileDeployment_blueGreenWithoutPodSelector(t *testing.T) {
+	c := withServerSideApply(fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		WithObjects(
+			&v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+				Spec: v1beta1.NginxSpec{
+					Image:    "nginx:1.24",
+					Service:  &v1beta1.NginxService{UsePodSelector: func(b bool) *bool { return &b }(false)},
+					Strategy: &v1beta1.NginxStrategy{Type: v1beta1.NginxStrategyBlueGreen},
+				},
+			},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default", Labels: k8s.LabelsForNginx("my-nginx")}},
+		).
+		Build())
+
+	var nginx v1beta1.Nginx
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &nginx))
+
+	// NOTE: the Nginx's Deployment is only deleted when controlled by it.
+	former := &appsv1.Deployment{}
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, former))
+	former.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(&nginx, v1beta1.GroupVersion.WithKind("Nginx"))}
+	require.NoError(t, c.Update(context.TODO(), former))
+
+	r := &NginxReconciler{Client: c, EventRecorder: record.NewFakeRecorder(100)}
+	reconcile := func() {
+		require.NoError(t, r.reconcileDeployment(context.TODO(), &nginx))
+		require.NoError(t, r.reconcileService(context.TODO(), &nginx))
+		require.NoError(t, r.pruneOrphans(context.TODO(), &nginx))
+	}
+
+	getSelector := func() map[string]string {
+		var svc corev1.Service
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-service", Namespace: "default"}, &svc))
+		return svc.Spec.Selector
+	}
+
+	reconcile()
+	completeRollout(t, c, getColorDeployment(t, c, "blue"))
+	reconcile()
+
+	active, err := r.activeColor(context.TODO(), &nginx)
+	require.NoError(t, err)
+	assert.Equal(t, "blue", active)
+	assert.Nil(t, getSelector())
+	err = c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &appsv1.Deployment{})
+	assert.True(t, errors.IsNotFound(err))
+
+	blue := getColorDeployment(t, c, "blue")
+	blue.Annotations[k8s.PromotedAtAnnotation] = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	require.NoError(t, c.Update(context.TODO(), blue))
+
+	nginx.Spec.Image = "nginx:1.25"
+	reconcile()
+	completeRollout(t, c, getColorDeployment(t, c, "green"))
+	reconcile()
+
+	active, err = r.activeColor(context.TODO(), &nginx)
+	require.NoError(t, err)
+	assert.Equal(t, "green", active)
+	assert.Nil(t, getSelector())
+}
+
+func TestRequeueAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time { return &metav1.Time{Time: now.Add(d)} }
+
+	tests := map[string]struct {
+		deployments []v1beta1.DeploymentStatus
+		expected    time.Duration
+	}{
+		"nothing scheduled": {
+			deployments: []v1beta1.DeploymentStatus{{Name: "my-nginx"}},
+		},
+
+		"baking canary": {
+			deployments: []v1beta1.DeploymentStatus{
+				{Name: "my-nginx"},
+				{Name: "my-nginx-canary", Canary: &v1beta1.CanaryStatus{Phase: v1beta1.CanaryPhaseBaking, PromoteAt: at(time.Minute)}},
+			},
+			expected: time.Minute,
+		},
+
+		"aborted canary": {
+			deployments: []v1beta1.DeploymentStatus{
+				{Name: "my-nginx-canary", Canary: &v1beta1.CanaryStatus{Phase: v1beta1.CanaryPhaseAborted}},
+			},
+		},
+
+		"previous color": {
+			deployments: []v1beta1.DeploymentStatus{
+				{Name: "my-nginx-blue", Color: "blue", ScaleDownAt: at(30 * time.Second)},
+				{Name: "my-nginx-green", Color: "green", Active: true},
+			},
+			expected: 30 * time.Second,
+		},
+
+		"overdue": {
+			deployments: []v1beta1.DeploymentStatus{
+				{Name: "my-nginx-blue", Color: "blue", ScaleDownAt: at(-time.Minute)},
+			},
+			expected: time.Second,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nginx := &v1beta1.Nginx{Status: v1beta1.NginxStatus{Deployments: tt.deployments}}
+			assert.Equal(t, tt.expected, requeueAfter(nginx, now))
+		})
+	}
+}
+
+func getColorDeployment(t *testing.T, c client.Client, color string) *appsv1.Deployment {
+	var dep appsv1.Deployment
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-" + color, Namespace: "default"}, &dep))
+	return &dep
+}
+
+func getServiceColor(t *testing.T, c client.Client) string {
+	var svc corev1.Service
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-service", Namespace: "default"}, &svc))
+	return svc.Spec.Selector["nginx.tsuru.io/color"]
+}
+
+func completeRollout(t *testing.T, c client.Client, dep *appsv1.Deployment) {
+	replicas := replicasOf(dep)
+	dep.Status = appsv1.DeploymentStatus{
+		ObservedGeneration: dep.Generation,
+		Replicas:           replicas,
+		UpdatedReplicas:    replicas,
+		ReadyReplicas:      replicas,
+		AvailableReplicas:  replicas,
+		Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+			{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable"},
+		},
+	}
+	require.NoError(t, c.Update(context.TODO(), dep))
+}
@@ -0,0 +1,207 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+const (
+	defaultCanaryShare       = 10
+	defaultCanaryBakeSeconds = 300
+)
+
+type canaryAction int
+
+const (
+	canaryWait canaryAction = iota
+	canaryPromote
+	canaryAbort
+)
+
+func isCanaryStrategy(nginx *nginxv1beta1.Nginx) bool {
+	return nginx.Spec.Strategy != nil && nginx.Spec.Strategy.Type == nginxv1beta1.NginxStrategyCanary
+}
+
+func canaryBakeTime(nginx *nginxv1beta1.Nginx) time.Duration {
+	seconds := int32(defaultCanaryBakeSeconds)
+	if s := nginx.Spec.Strategy; s != nil && s.Canary != nil && s.Canary.BakeSeconds != nil {
+		seconds = *s.Canary.BakeSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// canaryReplicas returns the number of replicas of the canary Deployment, as
+// a share of the current ones.
+func canaryReplicas(nginx *nginxv1beta1.Nginx, current *appsv1.Deployment) int32 {
+	share := int32(defaultCanaryShare)
+	if s := nginx.Spec.Strategy; s != nil && s.Canary != nil && s.Canary.Share != nil {
+		share = *s.Canary.Share
+	}
+
+	replicas := int32(1)
+	if current.Spec.Replicas != nil {
+		replicas = *current.Spec.Replicas
+	}
+
+	n := int32(math.Ceil(float64(replicas) * float64(share) / 100))
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// podTemplateChanged reports whether the desired Deployment changes the pod
+// template of the current one. Changes such as the replicas set by the
+// autoscaler don't need to be tried out on a canary.
+func podTemplateChanged(desired, current *appsv1.Deployment, desiredSpec, currentSpec nginxv1beta1.NginxSpec) bool {
+	desiredSpec.Replicas, currentSpec.Replicas = nil, nil
+	return !reflect.DeepEqual(desiredSpec, currentSpec) ||
+		!sameTemplateAnnotation(current, desired, k8s.ConfigHashAnnotation) ||
+		!sameTemplateAnnotation(current, desired, k8s.TLSHashAnnotation)
+}
+
+// reconcileCanary rolls out the desired Deployment as a canary, reporting
+// whether it can be promoted, i.e. its pods stayed ready for the bake time.
+// A canary whose pods don't become (or stay) ready is aborted: it's scaled
+// down and kept until the spec changes.
+func (r *NginxReconciler) reconcileCanary(ctx context.Context, nginx *nginxv1beta1.Nginx, desired, current *appsv1.Deployment) (bool, error) {
+	hash, err := k8s.PodTemplateHash(&desired.Spec.Template)
+	if err != nil {
+		return false, err
+	}
+
+	var canary appsv1.Deployment
+	err = r.Client.Get(ctx, types.NamespacedName{Name: k8s.CanaryName(nginx), Namespace: nginx.Namespace}, &canary)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to retrieve canary Deployment: %w", err)
+	}
+
+	found := err == nil
+	if !found || canary.Annotations[k8s.CanaryHashAnnotation] != hash {
+		newCanary, err := k8s.NewCanaryDeployment(nginx, desired, canaryReplicas(nginx, current))
+		if err != nil {
+			return false, fmt.Errorf("failed to build canary Deployment: %w", err)
+		}
+
+		var live client.Object
+		if found {
+			live = &canary
+		}
+
+		if err = r.apply(ctx, newCanary, live); err != nil {
+			return false, fmt.Errorf("failed to apply canary Deployment: %w", err)
+		}
+
+		r.EventRecorder.Eventf(nginx, corev1.EventTypeNormal, "CanaryStarted", "canary Deployment %s started with %d replica(s)", newCanary.Name, *newCanary.Spec.Replicas)
+		return false, nil
+	}
+
+	status, action := evaluateCanary(&canary, canaryBakeTime(nginx), time.Now())
+	switch action {
+	case canaryPromote:
+		return true, nil
+
+	case canaryAbort:
+		aborted, err := k8s.NewCanaryDeployment(nginx, desired, 0)
+		if err != nil {
+			return false, fmt.Errorf("failed to build canary Deployment: %w", err)
+		}
+		aborted.Annotations[k8s.CanaryAbortedAnnotation] = status.Message
+
+		if err = r.apply(ctx, aborted, &canary); err != nil {
+			return false, fmt.Errorf("failed to abort canary Deployment: %w", err)
+		}
+
+		r.EventRecorder.Eventf(nginx, corev1.EventTypeWarning, "CanaryAborted", "canary Deployment %s aborted: %s", canary.Name, status.Message)
+	}
+
+	return false, nil
+}
+
+// evaluateCanary returns the state of the canary Deployment, along with what
+// should be done with it at the given time.
+func evaluateCanary(canary *appsv1.Deployment, bake time.Duration, now time.Time) (nginxv1beta1.CanaryStatus, canaryAction) {
+	status := nginxv1beta1.CanaryStatus{
+		Phase:         nginxv1beta1.CanaryPhaseProgressing,
+		Replicas:      canary.Status.Replicas,
+		ReadyReplicas: canary.Status.ReadyReplicas,
+	}
+
+	if msg, aborted := canary.Annotations[k8s.CanaryAbortedAnnotation]; aborted {
+		status.Phase = nginxv1beta1.CanaryPhaseAborted
+		status.Message = msg
+		return status, canaryWait
+	}
+
+	if isDeploymentStuck(canary) {
+		status.Message = "canary pods did not become ready within the progress deadline"
+		return status, canaryAbort
+	}
+
+	// NOTE: the Deployment controller sets this reason once the rollout
+	// completes, which is kept as is afterwards.
+	var completed *appsv1.DeploymentCondition
+	for i, c := range canary.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Reason == "NewReplicaSetAvailable" {
+			completed = &canary.Status.Conditions[i]
+		}
+	}
+
+	if completed == nil || canary.Generation > canary.Status.ObservedGeneration {
+		return status, canaryWait
+	}
+
+	status.Phase = nginxv1beta1.CanaryPhaseBaking
+	if canary.Spec.Replicas != nil && canary.Status.ReadyReplicas < *canary.Spec.Replicas {
+		status.Message = "canary pods became unready while baking"
+		return status, canaryAbort
+	}
+
+	promoteAt := metav1.NewTime(completed.LastUpdateTime.Add(bake))
+	status.PromoteAt = &promoteAt
+	if now.Before(promoteAt.Time) {
+		return status, canaryWait
+	}
+
+	return status, canaryPromote
+}
+
+// deleteCanary deletes the canary Deployment of the Nginx, if any.
+func (r *NginxReconciler) deleteCanary(ctx context.Context, nginx *nginxv1beta1.Nginx) error {
+	var canary appsv1.Deployment
+	err := r.Client.Get(ctx, types.NamespacedName{Name: k8s.CanaryName(nginx), Namespace: nginx.Namespace}, &canary)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to retrieve canary Deployment: %w", err)
+	}
+
+	if !metav1.IsControlledBy(&canary, nginx) {
+		return nil
+	}
+
+	if err = r.Client.Delete(ctx, &canary); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete canary Deployment: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+func TestNginxReconciler_reconcileDeployment_canary(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+		Spec: v1beta1.NginxSpec{
+			Image:    "nginx:1.24",
+			Replicas: func(n int32) *int32 { return &n }(int32(4)),
+			Strategy: &v1beta1.NginxStrategy{
+				Type: v1beta1.NginxStrategyCanary,
+				Canary: &v1beta1.NginxCanaryStrategy{
+					Share:       func(n int32) *int32 { return &n }(int32(30)),
+					BakeSeconds: func(n int32) *int32 { return &n }(int32(60)),
+				},
+			},
+		},
+	}
+
+	c := withServerSideApply(fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		Build())
+
+	er := record.NewFakeRecorder(10)
+	r := &NginxReconciler{Client: c, EventRecorder: er}
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+
+	nginx.Spec.Image = "nginx:1.25"
+	for i := 0; i < 2; i++ {
+		require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	}
+	assert.Equal(t, "nginx:1.24", getDeployment(t, c).Spec.Template.Spec.Containers[0].Image)
+
+	canary := getCanary(t, c)
+	assert.Equal(t, "nginx:1.25", canary.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, int32(2), *canary.Spec.Replicas)
+	assert.Equal(t, map[string]string{
+		"nginx.tsuru.io/resource-name": "my-nginx",
+		"nginx.tsuru.io/app":           "nginx",
+		"nginx.tsuru.io/track":         "canary",
+	}, canary.Spec.Selector.MatchLabels)
+	assert.Equal(t, "canary", canary.Spec.Template.Labels["nginx.tsuru.io/track"])
+
+	setCanaryRollout(t, c, canary, 2, appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable", LastUpdateTime: metav1.Now()})
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	assert.Equal(t, "nginx:1.24", getDeployment(t, c).Spec.Template.Spec.Containers[0].Image)
+
+	setCanaryRollout(t, c, getCanary(t, c), 2, appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable", LastUpdateTime: metav1.NewTime(time.Now().Add(-time.Minute))})
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	assert.Equal(t, "nginx:1.25", getDeployment(t, c).Spec.Template.Spec.Containers[0].Image)
+	assertCanaryNotFound(t, c)
+
+	nginx.Spec.Image = "nginx:broken"
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	setCanaryRollout(t, c, getCanary(t, c), 0, appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"})
+	for i := 0; i < 2; i++ {
+		require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	}
+
+	assert.Equal(t, "nginx:1.25", getDeployment(t, c).Spec.Template.Spec.Containers[0].Image)
+	canary = getCanary(t, c)
+	assert.Equal(t, "nginx:broken", canary.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, int32(0), *canary.Spec.Replicas)
+	assert.Equal(t, "canary pods did not become ready within the progress deadline", canary.Annotations[k8s.CanaryAbortedAnnotation])
+
+	// reverting the spec drops the aborted canary
+	nginx.Spec.Image = "nginx:1.25"
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	assertCanaryNotFound(t, c)
+
+	close(er.Events)
+	var events []string
+	for event := range er.Events {
+		events = append(events, event)
+	}
+	assert.Equal(t, []string{
+		"Normal CanaryStarted canary Deployment my-nginx-canary started with 2 replica(s)",
+		"Normal CanaryPromoted canary Deployment my-nginx-canary promoted",
+		"Normal CanaryStarted canary Deployment my-nginx-canary started with 2 replica(s)",
+		"Warning CanaryAborted canary Deployment my-nginx-canary aborted: canary pods did not become ready within the progress deadline",
+	}, events)
+}
+
+func TestEvaluateCanary(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	completed := appsv1.DeploymentCondition{
+		Type:           appsv1.DeploymentProgressing,
+		Status:         corev1.ConditionTrue,
+		Reason:         "NewReplicaSetAvailable",
+		LastUpdateTime: metav1.NewTime(now.Add(-time.Minute)),
+	}
+
+	tests := map[string]struct {
+		canary         func(d *appsv1.Deployment)
+		expectedStatus v1beta1.CanaryStatus
+		expectedAction canaryAction
+	}{
+		"rolling out": {
+			canary: func(d *appsv1.Deployment) {
+				d.Status.Replicas = 2
+				d.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "ReplicaSetUpdated"}}
+			},
+			expectedStatus: v1beta1.CanaryStatus{Phase: v1beta1.CanaryPhaseProgressing, Replicas: 2},
+			expectedAction: canaryWait,
+		},
+
+		"not ready within the progress deadline": {
+			canary: func(d *appsv1.Deployment) {
+				d.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"}}
+			},
+			expectedStatus: v1beta1.CanaryStatus{Phase: v1beta1.CanaryPhaseProgressing, Message: "canary pods did not become ready within the progress deadline"},
+			expectedAction: canaryAbort,
+		},
+
+		"baking": {
+			canary: func(d *appsv1.Deployment) {
+				d.Status.Replicas, d.Status.ReadyReplicas = 2, 2
+				d.Status.Conditions = []appsv1.DeploymentCondition{completed}
+			},
+			expectedStatus: v1beta1.CanaryStatus{Phase: v1beta1.CanaryPhaseBaking, Replicas: 2, ReadyReplicas: 2, PromoteAt: &metav1.Time{Time: now.Add(4 * time.Minute)}},
+			expectedAction: canaryWait,
+		},
+
+		"baked": {
+			canary: func(d *appsv1.Deployment) {
+				d.Status.Replicas, d.Status.ReadyReplicas = 2, 2
+				completed := completed
+				completed.LastUpdateTime = metav1.NewTime(now.Add(-5 * time.Minute))
+				d.Status.Conditions = []appsv1.DeploymentCondition{completed}
+			},
+			expectedStatus: v1beta1.CanaryStatus{Phase: v1beta1.CanaryPhaseBaking, Replicas: 2, ReadyReplicas: 2, PromoteAt: &metav1.Time{Time: now}},
+			expectedAction: canaryPromote,
+		},
+
+		"unready while baking": {
+			canary: func(d *appsv1.Deployment) {
+				d.Status.Replicas, d.Status.ReadyReplicas = 2, 1
+				d.Status.Conditions = []appsv1.DeploymentCondition{completed}
+			},
+			expectedStatus: v1beta1.CanaryStatus{Phase: v1beta1.CanaryPhaseBaking, Replicas: 2, ReadyReplicas: 1, Message: "canary pods became unready while baking"},
+			expectedAction: canaryAbort,
+		},
+
+		"aborted": {
+			canary: func(d *appsv1.Deployment) {
+				d.Annotations = map[string]string{k8s.CanaryAbortedAnnotation: "canary pods became unready while baking"}
+				d.Status.Conditions = []appsv1.DeploymentCondition{completed}
+			},
+			expectedStatus: v1beta1.CanaryStatus{Phase: v1beta1.CanaryPhaseAborted, Message: "canary pods became unready while baking"},
+			expectedAction: canaryWait,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			canary := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: func(n int32) *int32 { return &n }(int32(2))}}
+			tt.canary(canary)
+
+			status, action := evaluateCanary(canary, 5*time.Minute, now)
+			assert.Equal(t, tt.expectedStatus, status)
+			assert.Equal(t, tt.expectedAction, action)
+		})
+	}
+}
+
+func getCanary(t *testing.T, c client.Client) *appsv1.Deployment {
+	var dep appsv1.Deployment
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-canary", Namespace: "default"}, &dep))
+	return &dep
+}
+
+func assertCanaryNotFound(t *testing.T, c client.Client) {
+	err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-canary", Namespace: "default"}, &appsv1.Deployment{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func setCanaryRollout(t *testing.T, c client.Client, dep *appsv1.Deployment, ready int32, progressing appsv1.DeploymentCondition) {
+	dep.Status = appsv1.DeploymentStatus{
+		ObservedGeneration: dep.Generation,
+		Replicas:           ready,
+		ReadyReplicas:      ready,
+		Conditions:         []appsv1.DeploymentCondition{progressing},
+	}
+	require.NoError(t, c.Update(context.TODO(), dep))
+}
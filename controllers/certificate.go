@@ -0,0 +1,111 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;delete
+
+func (r *NginxReconciler) reconcileCertificates(ctx context.Context, nginx *nginxv1beta1.Nginx) error {
+	desired := k8s.NewCertificates(nginx)
+
+	var certList unstructured.UnstructuredList
+	certList.SetGroupVersionKind(k8s.CertificateGVK.GroupVersion().WithKind(k8s.CertificateGVK.Kind + "List"))
+	err := r.Client.List(ctx, &certList, client.InNamespace(nginx.Namespace), client.MatchingLabels(k8s.LabelsForNginx(nginx.Name)))
+	if meta.IsNoMatchError(err) {
+		if len(desired) == 0 {
+			setCondition(nginx, nginxv1beta1.ConditionCertificatesReady, metav1.ConditionTrue, reasonCertificatesNotRequired, "")
+			return nil
+		}
+
+		setCondition(nginx, nginxv1beta1.ConditionCertificatesReady, metav1.ConditionFalse, reasonCertManagerNotAvailable, "Certificate kind is not available in the cluster")
+		return fmt.Errorf("failed to list Certificate resources: %w", err)
+	}
+
+	if err != nil {
+		setCondition(nginx, nginxv1beta1.ConditionCertificatesReady, metav1.ConditionFalse, reasonCertificatesFailed, err.Error())
+		return fmt.Errorf("failed to list Certificate resources: %w", err)
+	}
+
+	current := make(map[string]*unstructured.Unstructured)
+	for i := range certList.Items {
+		current[certList.Items[i].GetName()] = &certList.Items[i]
+	}
+
+	var pending []string
+	for _, newCert := range desired {
+		currentCert, found := current[newCert.GetName()]
+		delete(current, newCert.GetName())
+
+		if !found {
+			if err = r.Client.Create(ctx, newCert); err != nil {
+				setCondition(nginx, nginxv1beta1.ConditionCertificatesReady, metav1.ConditionFalse, reasonCertificatesFailed, err.Error())
+				return fmt.Errorf("failed to create Certificate resource: %w", err)
+			}
+
+			pending = append(pending, newCert.GetName())
+			continue
+		}
+
+		if !k8s.IsCertificateReady(currentCert) {
+			pending = append(pending, newCert.GetName())
+		}
+
+		// NOTE: only the fields managed by the operator are overwritten,
+		// anything else set on the Certificate spec is kept as is.
+		updated := currentCert.DeepCopy()
+		updated.SetLabels(newCert.GetLabels())
+		spec, _, _ := unstructured.NestedMap(newCert.Object, "spec")
+		for field, value := range spec {
+			if err = unstructured.SetNestedField(updated.Object, value, "spec", field); err != nil {
+				return fmt.Errorf("failed to update Certificate resource: %w", err)
+			}
+		}
+
+		if equality.Semantic.DeepEqual(currentCert, updated) {
+			continue
+		}
+
+		if err = r.Client.Update(ctx, updated); err != nil {
+			setCondition(nginx, nginxv1beta1.ConditionCertificatesReady, metav1.ConditionFalse, reasonCertificatesFailed, err.Error())
+			return fmt.Errorf("failed to update Certificate resource: %w", err)
+		}
+	}
+
+	for _, cert := range current {
+		if !metav1.IsControlledBy(cert, nginx) {
+			continue
+		}
+
+		if err = r.Client.Delete(ctx, cert); client.IgnoreNotFound(err) != nil {
+			setCondition(nginx, nginxv1beta1.ConditionCertificatesReady, metav1.ConditionFalse, reasonCertificatesFailed, err.Error())
+			return fmt.Errorf("failed to delete Certificate resource: %w", err)
+		}
+	}
+
+	switch {
+	case len(desired) == 0:
+		setCondition(nginx, nginxv1beta1.ConditionCertificatesReady, metav1.ConditionTrue, reasonCertificatesNotRequired, "")
+	case len(pending) > 0:
+		setCondition(nginx, nginxv1beta1.ConditionCertificatesReady, metav1.ConditionFalse, reasonCertificatesPending, fmt.Sprintf("waiting for certificate(s) to be issued: %s", strings.Join(pending, ", ")))
+	default:
+		setCondition(nginx, nginxv1beta1.ConditionCertificatesReady, metav1.ConditionTrue, reasonCertificatesIssued, "")
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+func TestNginxReconciler_reconcileCertificates(t *testing.T) {
+	issuer := &v1beta1.NginxTLSIssuerRef{Name: "letsencrypt", Kind: "ClusterIssuer"}
+
+	existing := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+		Spec: v1beta1.NginxSpec{
+			TLS: []v1beta1.NginxTLS{
+				{SecretName: "example-com", Hosts: []string{"www.example.com"}, IssuerRef: issuer},
+				{SecretName: "example-org", Hosts: []string{"www.example.org"}, IssuerRef: issuer},
+			},
+		},
+	}
+
+	certs := k8s.NewCertificates(existing)
+	certs[0].Object["status"] = map[string]any{
+		"conditions": []any{map[string]any{"type": "Ready", "status": "True"}},
+	}
+	certs[0].SetAnnotations(map[string]string{"cert-manager.io/issue-temporary-certificate": "true"})
+
+	certs[1].Object["status"] = map[string]any{
+		"conditions": []any{map[string]any{"type": "Ready", "status": "False", "reason": "DoesNotExist"}},
+	}
+
+	resources := []runtime.Object{certs[0], certs[1]}
+
+	tests := map[string]struct {
+		nginx             *v1beta1.Nginx
+		expectedCondition string
+		expectedMessage   string
+		assert            func(t *testing.T, c client.Client)
+	}{
+		"without issuers": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-nginx", Namespace: "default"},
+				Spec: v1beta1.NginxSpec{
+					TLS: []v1beta1.NginxTLS{{SecretName: "hand-made"}},
+				},
+			},
+			expectedCondition: "True/CertificatesNotRequired",
+			assert: func(t *testing.T, c client.Client) {
+				got := newCertificateList()
+				require.NoError(t, c.List(context.TODO(), got))
+				assert.Len(t, got.Items, 2)
+			},
+		},
+
+		"creating certificate": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-nginx", Namespace: "default"},
+				Spec: v1beta1.NginxSpec{
+					TLS: []v1beta1.NginxTLS{
+						{SecretName: "example-net", Hosts: []string{"www.example.net"}, IssuerRef: issuer},
+					},
+				},
+			},
+			expectedCondition: "False/CertificatesPending",
+			expectedMessage:   "waiting for certificate(s) to be issued: other-nginx-example-net",
+			assert: func(t *testing.T, c client.Client) {
+				got := newCertificate()
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "other-nginx-example-net", Namespace: "default"}, got))
+				assert.Equal(t, map[string]any{
+					"secretName": "example-net",
+					"dnsNames":   []any{"www.example.net"},
+					"issuerRef":  map[string]any{"name": "letsencrypt", "kind": "ClusterIssuer", "group": "cert-manager.io"},
+				}, got.Object["spec"])
+			},
+		},
+
+		"waiting for certificates to be issued": {
+			nginx:             existing,
+			expectedCondition: "False/CertificatesPending",
+			expectedMessage:   "waiting for certificate(s) to be issued: my-nginx-example-org",
+		},
+
+		"updating and removing certificates": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+				Spec: v1beta1.NginxSpec{
+					TLS: []v1beta1.NginxTLS{
+						{SecretName: "example-com", Hosts: []string{"www.example.com", "example.com"}, IssuerRef: issuer},
+						{SecretName: "example-org", Hosts: []string{"www.example.org"}},
+					},
+				},
+			},
+			expectedCondition: "True/CertificatesIssued",
+			assert: func(t *testing.T, c client.Client) {
+				got := newCertificate()
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-example-com", Namespace: "default"}, got))
+				dnsNames, _, _ := unstructured.NestedStringSlice(got.Object, "spec", "dnsNames")
+				assert.Equal(t, []string{"www.example.com", "example.com"}, dnsNames)
+				assert.Equal(t, map[string]string{"cert-manager.io/issue-temporary-certificate": "true"}, got.GetAnnotations())
+
+				err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-example-org", Namespace: "default"}, got)
+				assert.True(t, errors.IsNotFound(err))
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewClientBuilder().
+				WithScheme(newScheme()).
+				WithRuntimeObjects(resources...).
+				Build()
+
+			nginx := tt.nginx.DeepCopy()
+
+			r := &NginxReconciler{Client: client}
+			require.NoError(t, r.reconcileCertificates(context.TODO(), nginx))
+
+			assert.Equal(t, map[string]string{v1beta1.ConditionCertificatesReady: tt.expectedCondition}, summarizeConditions(nginx.Status.Conditions))
+			assert.Equal(t, tt.expectedMessage, meta.FindStatusCondition(nginx.Status.Conditions, v1beta1.ConditionCertificatesReady).Message)
+
+			if tt.assert != nil {
+				tt.assert(t, client)
+			}
+		})
+	}
+}
+
+func newCertificate() *unstructured.Unstructured {
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(k8s.CertificateGVK)
+	return cert
+}
+
+func newCertificateList() *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(k8s.CertificateGVK.GroupVersion().WithKind("CertificateList"))
+	return list
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+const (
+	reasonReconcileFailed = "ReconcileFailed"
+	reasonReady           = "Ready"
+	reasonNotReady        = "NotReady"
+
+	reasonValidConfig   = "ValidConfig"
+	reasonInvalidConfig = "InvalidConfig"
+
+	reasonDeploymentFailed         = "DeploymentReconcileFailed"
+	reasonDeploymentNotFound       = "DeploymentNotFound"
+	reasonMinimumReplicasAvailable = "MinimumReplicasAvailable"
+	reasonMinimumReplicasUnavail   = "MinimumReplicasUnavailable"
+	reasonRolloutInProgress        = "RolloutInProgress"
+	reasonRolloutComplete          = "RolloutComplete"
+	reasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+
+	reasonServiceReconciled   = "ServiceReconciled"
+	reasonServiceFailed       = "ServiceReconcileFailed"
+	reasonServiceNotFound     = "ServiceNotFound"
+	reasonLoadBalancerPending = "LoadBalancerPending"
+
+	reasonIngressReconciled  = "IngressReconciled"
+	reasonIngressNotRequired = "IngressNotRequired"
+	reasonIngressFailed      = "IngressReconcileFailed"
+
+	reasonRouteAccepted          = "RouteAccepted"
+	reasonRouteNotAccepted       = "RouteNotAccepted"
+	reasonRoutePending           = "RoutePending"
+	reasonRouteNotRequired       = "RouteNotRequired"
+	reasonRouteFailed            = "RouteReconcileFailed"
+	reasonGatewayAPINotAvailable = "GatewayAPINotAvailable"
+
+	reasonCertificatesIssued      = "CertificatesIssued"
+	reasonCertificatesPending     = "CertificatesPending"
+	reasonCertificatesNotRequired = "CertificatesNotRequired"
+	reasonCertificatesFailed      = "CertificateReconcileFailed"
+	reasonCertManagerNotAvailable = "CertManagerNotAvailable"
+)
+
+// readinessConditions are the conditions which must be true for an Nginx to
+// be considered Ready.
+var readinessConditions = []string{
+	nginxv1beta1.ConditionConfigValid,
+	nginxv1beta1.ConditionDeploymentAvailable,
+	nginxv1beta1.ConditionServiceReady,
+	nginxv1beta1.ConditionIngressReady,
+	nginxv1beta1.ConditionRouteAccepted,
+	nginxv1beta1.ConditionCertificatesReady,
+}
+
+func setCondition(nginx *nginxv1beta1.Nginx, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&nginx.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: nginx.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// setDeploymentConditions sets the DeploymentAvailable and Progressing
+// conditions according to the observed state of the Nginx's Deployments.
+func setDeploymentConditions(nginx *nginxv1beta1.Nginx, deploys []appsv1.Deployment) {
+	if len(deploys) == 0 {
+		setCondition(nginx, nginxv1beta1.ConditionDeploymentAvailable, metav1.ConditionFalse, reasonDeploymentNotFound, "no Deployment found for this Nginx")
+		return
+	}
+
+	var unavailable, progressing, stuck []string
+	for _, d := range deploys {
+		if !isDeploymentAvailable(&d) {
+			unavailable = append(unavailable, d.Name)
+		}
+
+		if isDeploymentStuck(&d) {
+			stuck = append(stuck, d.Name)
+			continue
+		}
+
+		if isDeploymentProgressing(&d) {
+			progressing = append(progressing, d.Name)
+		}
+	}
+
+	if len(unavailable) > 0 {
+		setCondition(nginx, nginxv1beta1.ConditionDeploymentAvailable, metav1.ConditionFalse, reasonMinimumReplicasUnavail, fmt.Sprintf("Deployment(s) without minimum availability: %s", strings.Join(unavailable, ", ")))
+	} else {
+		setCondition(nginx, nginxv1beta1.ConditionDeploymentAvailable, metav1.ConditionTrue, reasonMinimumReplicasAvailable, "")
+	}
+
+	switch {
+	case len(stuck) > 0:
+		setCondition(nginx, nginxv1beta1.ConditionProgressing, metav1.ConditionFalse, reasonProgressDeadlineExceeded, fmt.Sprintf("Deployment(s) exceeded their progress deadline: %s", strings.Join(stuck, ", ")))
+	case len(progressing) > 0:
+		setCondition(nginx, nginxv1beta1.ConditionProgressing, metav1.ConditionTrue, reasonRolloutInProgress, fmt.Sprintf("Deployment(s) rolling out: %s", strings.Join(progressing, ", ")))
+	default:
+		setCondition(nginx, nginxv1beta1.ConditionProgressing, metav1.ConditionFalse, reasonRolloutComplete, "")
+	}
+}
+
+func isDeploymentAvailable(d *appsv1.Deployment) bool {
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentAvailable {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func isDeploymentStuck(d *appsv1.Deployment) bool {
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing {
+			// NOTE: same reason used by the Deployment controller when the
+			// rollout does not progress within progressDeadlineSeconds.
+			return c.Status == corev1.ConditionFalse && c.Reason == reasonProgressDeadlineExceeded
+		}
+	}
+	return false
+}
+
+func isDeploymentProgressing(d *appsv1.Deployment) bool {
+	if d.Generation > d.Status.ObservedGeneration {
+		return true
+	}
+
+	desired := int32(1)
+	if d.Spec.Replicas != nil {
+		desired = *d.Spec.Replicas
+	}
+
+	return d.Status.UpdatedReplicas < desired ||
+		d.Status.Replicas > d.Status.UpdatedReplicas ||
+		d.Status.AvailableReplicas < d.Status.UpdatedReplicas
+}
+
+// setServiceCondition sets the ServiceReady condition according to the
+// observed state of the Nginx's Services. A LoadBalancer Service is only
+// considered ready once it has been assigned an address.
+func setServiceCondition(nginx *nginxv1beta1.Nginx, services []nginxv1beta1.ServiceStatus) {
+	if len(services) == 0 {
+		setCondition(nginx, nginxv1beta1.ConditionServiceReady, metav1.ConditionFalse, reasonServiceNotFound, "no Service found for this Nginx")
+		return
+	}
+
+	loadBalancers := make(map[string]struct{})
+	for _, svc := range k8s.NewServices(nginx) {
+		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			loadBalancers[svc.Name] = struct{}{}
+		}
+	}
+
+	var pending []string
+	for _, svc := range services {
+		if _, isLB := loadBalancers[svc.Name]; isLB && len(svc.IPs) == 0 && len(svc.Hostnames) == 0 {
+			pending = append(pending, svc.Name)
+		}
+	}
+
+	if len(pending) > 0 {
+		setCondition(nginx, nginxv1beta1.ConditionServiceReady, metav1.ConditionFalse, reasonLoadBalancerPending, fmt.Sprintf("waiting for load balancer address on Service(s): %s", strings.Join(pending, ", ")))
+		return
+	}
+
+	setCondition(nginx, nginxv1beta1.ConditionServiceReady, metav1.ConditionTrue, reasonServiceReconciled, "")
+}
+
+// setReadyCondition summarizes the other conditions into the Ready one.
+func setReadyCondition(nginx *nginxv1beta1.Nginx) {
+	var notReady []string
+	for _, t := range readinessConditions {
+		if !meta.IsStatusConditionTrue(nginx.Status.Conditions, t) {
+			notReady = append(notReady, t)
+		}
+	}
+
+	// NOTE: a rollout in progress (or stuck) means that some pods may still be
+	// running a spec other than the desired one.
+	if c := meta.FindStatusCondition(nginx.Status.Conditions, nginxv1beta1.ConditionProgressing); c != nil &&
+		(c.Status == metav1.ConditionTrue || c.Reason == reasonProgressDeadlineExceeded) {
+		notReady = append(notReady, nginxv1beta1.ConditionProgressing)
+	}
+
+	if len(notReady) > 0 {
+		setCondition(nginx, nginxv1beta1.ConditionReady, metav1.ConditionFalse, reasonNotReady, fmt.Sprintf("conditions not satisfied: %s", strings.Join(notReady, ", ")))
+		return
+	}
+
+	setCondition(nginx, nginxv1beta1.ConditionReady, metav1.ConditionTrue, reasonReady, "")
+}
@@ -0,0 +1,169 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+const (
+	reasonConfigTestRunning = "ConfigTestRunning"
+	reasonConfigTestPassed  = "ConfigTestPassed"
+	reasonConfigTestFailed  = "ConfigTestFailed"
+
+	// configTestReportedAnnotation marks the config test Jobs whose result
+	// was already recorded as an event on the Nginx.
+	configTestReportedAnnotation = "nginx.tsuru.io/config-test-reported"
+)
+
+// testConfig runs "nginx -t" against the pod template of the given Deployment
+// through a Job, reporting whether it passed. While the Job is running, it
+// reports false, the Nginx is reconciled again once it finishes.
+func (r *NginxReconciler) testConfig(ctx context.Context, nginx *nginxv1beta1.Nginx, dep *appsv1.Deployment) (bool, error) {
+	newJob, err := k8s.NewConfigTestJob(nginx, dep)
+	if err != nil {
+		return false, fmt.Errorf("failed to build config test Job: %w", err)
+	}
+
+	if err = r.cleanupConfigTests(ctx, nginx, newJob.Name); err != nil {
+		return false, err
+	}
+
+	var job batchv1.Job
+	err = r.Client.Get(ctx, types.NamespacedName{Name: newJob.Name, Namespace: newJob.Namespace}, &job)
+	if errors.IsNotFound(err) {
+		if err = r.Client.Create(ctx, newJob); err != nil {
+			return false, fmt.Errorf("failed to create config test Job: %w", err)
+		}
+
+		setCondition(nginx, nginxv1beta1.ConditionConfigValid, metav1.ConditionUnknown, reasonConfigTestRunning, fmt.Sprintf("waiting for config test Job %s", newJob.Name))
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve config test Job: %w", err)
+	}
+
+	passed, finished := jobResult(&job)
+	if !finished {
+		setCondition(nginx, nginxv1beta1.ConditionConfigValid, metav1.ConditionUnknown, reasonConfigTestRunning, fmt.Sprintf("waiting for config test Job %s", job.Name))
+		return false, nil
+	}
+
+	output, err := r.configTestOutput(ctx, &job)
+	if err != nil {
+		return false, err
+	}
+
+	if passed {
+		setCondition(nginx, nginxv1beta1.ConditionConfigValid, metav1.ConditionTrue, reasonConfigTestPassed, output)
+	} else {
+		setCondition(nginx, nginxv1beta1.ConditionConfigValid, metav1.ConditionFalse, reasonConfigTestFailed, output)
+	}
+
+	if job.Annotations[configTestReportedAnnotation] == "true" {
+		return passed, nil
+	}
+
+	if passed {
+		r.EventRecorder.Eventf(nginx, corev1.EventTypeNormal, reasonConfigTestPassed, "nginx config test passed: %s", output)
+	} else {
+		r.EventRecorder.Eventf(nginx, corev1.EventTypeWarning, reasonConfigTestFailed, "nginx config test failed: %s", output)
+	}
+
+	patch := client.MergeFrom(job.DeepCopy())
+	if job.Annotations == nil {
+		job.Annotations = make(map[string]string)
+	}
+	job.Annotations[configTestReportedAnnotation] = "true"
+	if err = r.Client.Patch(ctx, &job, patch); err != nil {
+		return false, fmt.Errorf("failed to update config test Job: %w", err)
+	}
+
+	return passed, nil
+}
+
+// cleanupConfigTests deletes the config test Jobs of the Nginx, except the one
+// with the given name. Failed tests are kept until the config changes, so they
+// aren't retried.
+func (r *NginxReconciler) cleanupConfigTests(ctx context.Context, nginx *nginxv1beta1.Nginx, except string) error {
+	var jobs batchv1.JobList
+	err := r.Client.List(ctx, &jobs, client.InNamespace(nginx.Namespace), client.HasLabels{k8s.ConfigTestLabel}, client.MatchingLabels(k8s.LabelsForNginx(nginx.Name)))
+	if err != nil {
+		return fmt.Errorf("failed to list config test Jobs: %w", err)
+	}
+
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if job.Name == except || !metav1.IsControlledBy(job, nginx) {
+			continue
+		}
+
+		if err = r.Client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete config test Job: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func jobResult(job *batchv1.Job) (passed, finished bool) {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch c.Type {
+		case batchv1.JobComplete:
+			return true, true
+
+		case batchv1.JobFailed:
+			return false, true
+		}
+	}
+
+	return false, false
+}
+
+// configTestOutput returns the output of "nginx -t", kept as the termination
+// message of the test container.
+func (r *NginxReconciler) configTestOutput(ctx context.Context, job *batchv1.Job) (string, error) {
+	var pods corev1.PodList
+	err := r.Client.List(ctx, &pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name})
+	if err != nil {
+		return "", fmt.Errorf("failed to list config test pods: %w", err)
+	}
+
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated != nil && status.State.Terminated.Message != "" {
+				return strings.TrimSpace(status.State.Terminated.Message), nil
+			}
+		}
+	}
+
+	// NOTE: e.g. the Job exceeded its deadline, as the image couldn't be
+	// pulled.
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return fmt.Sprintf("config test Job %s failed: %s", job.Name, c.Message), nil
+		}
+	}
+
+	return fmt.Sprintf("config test Job %s finished without output", job.Name), nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+func TestNginxReconciler_reconcileDeployment_configTest(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default", UID: "nginx-uid"},
+		Spec:       v1beta1.NginxSpec{Image: "nginx:1.24"},
+	}
+
+	c := withServerSideApply(fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		Build())
+
+	er := record.NewFakeRecorder(10)
+	r := &NginxReconciler{Client: c, EventRecorder: er, ConfigTest: true}
+
+	// creating the Deployment isn't gated by the config test.
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	assert.Equal(t, "nginx:1.24", getDeploymentImage(t, c))
+	assert.Empty(t, listConfigTestJobs(t, c))
+
+	nginx.Spec.Image = "nginx:1.25"
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	assert.Equal(t, "nginx:1.24", getDeploymentImage(t, c))
+
+	jobs := listConfigTestJobs(t, c)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "nginx:1.25", jobs[0].Spec.Template.Spec.Containers[0].Image)
+	assertConfigValid(t, nginx, metav1.ConditionUnknown, reasonConfigTestRunning)
+
+	// still running
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	assert.Equal(t, "nginx:1.24", getDeploymentImage(t, c))
+	require.Len(t, listConfigTestJobs(t, c), 1)
+
+	finishConfigTest(t, c, &jobs[0], batchv1.JobComplete, "nginx: configuration file /etc/nginx/nginx.conf test is successful\n")
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	assert.Equal(t, "nginx:1.25", getDeploymentImage(t, c))
+	assert.Empty(t, listConfigTestJobs(t, c))
+	assertConfigValid(t, nginx, metav1.ConditionTrue, reasonConfigTestPassed)
+
+	nginx.Spec.Image = "nginx:broken"
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	jobs = listConfigTestJobs(t, c)
+	require.Len(t, jobs, 1)
+
+	finishConfigTest(t, c, &jobs[0], batchv1.JobFailed, `nginx: [emerg] unknown directive "lsten" in /etc/nginx/nginx.conf:3`)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	}
+	assert.Equal(t, "nginx:1.25", getDeploymentImage(t, c))
+	require.Len(t, listConfigTestJobs(t, c), 1)
+
+	cond := assertConfigValid(t, nginx, metav1.ConditionFalse, reasonConfigTestFailed)
+	assert.Equal(t, `nginx: [emerg] unknown directive "lsten" in /etc/nginx/nginx.conf:3`, cond.Message)
+
+	close(er.Events)
+	var events []string
+	for event := range er.Events {
+		events = append(events, event)
+	}
+	assert.Equal(t, []string{
+		"Normal ConfigTestPassed nginx config test passed: nginx: configuration file /etc/nginx/nginx.conf test is successful",
+		`Warning ConfigTestFailed nginx config test failed: nginx: [emerg] unknown directive "lsten" in /etc/nginx/nginx.conf:3`,
+	}, events)
+
+	// a new change replaces the failed test
+	nginx.Spec.Image = "nginx:1.26"
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	jobs = listConfigTestJobs(t, c)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "nginx:1.26", jobs[0].Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestNginxReconciler_testConfig_jobFailedWithoutOutput(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default", UID: "nginx-uid"},
+		Spec:       v1beta1.NginxSpec{Image: "nginx:unknown"},
+	}
+
+	dep, err := k8s.NewDeployment(nginx)
+	require.NoError(t, err)
+
+	job, err := k8s.NewConfigTestJob(nginx, dep)
+	require.NoError(t, err)
+	job.Status.Conditions = []batchv1.JobCondition{
+		{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "DeadlineExceeded", Message: "Job was active longer than specified deadline"},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		WithObjects(job).
+		Build()
+
+	r := &NginxReconciler{Client: c, EventRecorder: record.NewFakeRecorder(10)}
+	passed, err := r.testConfig(context.TODO(), nginx, dep)
+	require.NoError(t, err)
+	assert.False(t, passed)
+
+	cond := assertConfigValid(t, nginx, metav1.ConditionFalse, reasonConfigTestFailed)
+	assert.Equal(t, "config test Job "+job.Name+" failed: Job was active longer than specified deadline", cond.Message)
+}
+
+func getDeploymentImage(t *testing.T, c client.Client) string {
+	var dep appsv1.Deployment
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &dep))
+	return dep.Spec.Template.Spec.Containers[0].Image
+}
+
+func listConfigTestJobs(t *testing.T, c client.Client) []batchv1.Job {
+	var jobs batchv1.JobList
+	require.NoError(t, c.List(context.TODO(), &jobs, client.HasLabels{k8s.ConfigTestLabel}))
+	return jobs.Items
+}
+
+func finishConfigTest(t *testing.T, c client.Client, job *batchv1.Job, condition batchv1.JobConditionType, output string) {
+	job.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: corev1.ConditionTrue}}
+	require.NoError(t, c.Update(context.TODO(), job))
+
+	require.NoError(t, c.Create(context.TODO(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      job.Name + "-abcde",
+			Namespace: job.Namespace,
+			Labels:    map[string]string{"job-name": job.Name},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "nginx", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: output}}},
+			},
+		},
+	}))
+}
+
+func assertConfigValid(t *testing.T, nginx *v1beta1.Nginx, status metav1.ConditionStatus, reason string) *metav1.Condition {
+	cond := meta.FindStatusCondition(nginx.Status.Conditions, v1beta1.ConditionConfigValid)
+	require.NotNil(t, cond)
+	assert.Equal(t, status, cond.Status)
+	assert.Equal(t, reason, cond.Reason)
+	return cond
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+// ignoreDriftAnnotation, when set to "true" on the Nginx, stops the operator
+// from reverting manual changes made on its Deployment (e.g. emergency
+// hotfixes). Changes on the Nginx spec are still rolled out.
+const ignoreDriftAnnotation = "nginx.tsuru.io/ignore-drift"
+
+func shouldCorrectDrift(nginx *nginxv1beta1.Nginx) bool {
+	return nginx.Annotations[ignoreDriftAnnotation] != "true"
+}
+
+// templateDrift returns the fields of the desired pod template which differ
+// in the live one. Fields only found in the live template (e.g. defaulted by
+// the API server) aren't considered a drift.
+func templateDrift(desired, live *corev1.PodTemplateSpec) ([]string, error) {
+	d, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert desired pod template: %w", err)
+	}
+
+	l, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert live pod template: %w", err)
+	}
+
+	var fields []string
+	diffFields("spec.template", d, l, &fields)
+	sort.Strings(fields)
+	return fields, nil
+}
+
+func diffFields(path string, desired, live any, fields *[]string) {
+	switch d := desired.(type) {
+	case map[string]any:
+		l, ok := live.(map[string]any)
+		if !ok {
+			*fields = append(*fields, path)
+			return
+		}
+
+		for key, value := range d {
+			diffFields(path+"."+key, value, l[key], fields)
+		}
+
+	case []any:
+		l, ok := live.([]any)
+		if !ok || len(d) != len(l) {
+			*fields = append(*fields, path)
+			return
+		}
+
+		for i := range d {
+			diffFields(fmt.Sprintf("%s[%d]", path, i), d[i], l[i], fields)
+		}
+
+	default:
+		if !reflect.DeepEqual(desired, live) {
+			*fields = append(*fields, path)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+func TestNginxReconciler_reconcileDeployment_drift(t *testing.T) {
+	tests := map[string]struct {
+		annotations    map[string]string
+		edit           func(dep *appsv1.Deployment)
+		expectedEvents []string
+		assert         func(t *testing.T, dep *appsv1.Deployment)
+	}{
+		"without manual changes": {
+			edit: func(dep *appsv1.Deployment) {},
+			assert: func(t *testing.T, dep *appsv1.Deployment) {
+				assert.Equal(t, "nginx:stable", dep.Spec.Template.Spec.Containers[0].Image)
+			},
+		},
+
+		"fields defaulted by the API server": {
+			edit: func(dep *appsv1.Deployment) {
+				dep.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+				dep.Spec.Template.Spec.Containers[0].TerminationMessagePath = corev1.TerminationMessagePathDefault
+				dep.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirst
+			},
+			assert: func(t *testing.T, dep *appsv1.Deployment) {
+				assert.Equal(t, corev1.PullIfNotPresent, dep.Spec.Template.Spec.Containers[0].ImagePullPolicy)
+			},
+		},
+
+		"image manually changed": {
+			edit: func(dep *appsv1.Deployment) {
+				dep.Spec.Template.Spec.Containers[0].Image = "nginx:hotfix"
+			},
+			expectedEvents: []string{
+				"Normal DriftCorrected Deployment fields manually changed were restored: spec.template.spec.containers[0].image",
+			},
+			assert: func(t *testing.T, dep *appsv1.Deployment) {
+				assert.Equal(t, "nginx:stable", dep.Spec.Template.Spec.Containers[0].Image)
+			},
+		},
+
+		"volume manually removed": {
+			edit: func(dep *appsv1.Deployment) {
+				dep.Spec.Template.Spec.Volumes = nil
+				dep.Spec.Template.Spec.Containers[0].VolumeMounts = nil
+			},
+			expectedEvents: []string{
+				"Normal DriftCorrected Deployment fields manually changed were restored: spec.template.spec.containers[0].volumeMounts, spec.template.spec.volumes",
+			},
+			assert: func(t *testing.T, dep *appsv1.Deployment) {
+				require.Len(t, dep.Spec.Template.Spec.Volumes, 1)
+				assert.Equal(t, "cache", dep.Spec.Template.Spec.Volumes[0].Name)
+				require.Len(t, dep.Spec.Template.Spec.Containers[0].VolumeMounts, 1)
+				assert.Equal(t, "cache", dep.Spec.Template.Spec.Containers[0].VolumeMounts[0].Name)
+			},
+		},
+
+		"drift correction disabled": {
+			annotations: map[string]string{ignoreDriftAnnotation: "true"},
+			edit: func(dep *appsv1.Deployment) {
+				dep.Spec.Template.Spec.Containers[0].Image = "nginx:hotfix"
+			},
+			assert: func(t *testing.T, dep *appsv1.Deployment) {
+				assert.Equal(t, "nginx:hotfix", dep.Spec.Template.Spec.Containers[0].Image)
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nginx := &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default", Annotations: tt.annotations},
+				Spec: v1beta1.NginxSpec{
+					Image: "nginx:stable",
+					PodTemplate: v1beta1.NginxPodTemplateSpec{
+						Volumes: []corev1.Volume{
+							{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+						},
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "cache", MountPath: "/var/cache/nginx"},
+						},
+					},
+				},
+			}
+
+			c := withServerSideApply(fake.NewClientBuilder().
+				WithScheme(newScheme()).
+				Build())
+
+			er := record.NewFakeRecorder(10)
+			r := &NginxReconciler{Client: c, EventRecorder: er}
+			require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+
+			var dep appsv1.Deployment
+			require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &dep))
+			tt.edit(&dep)
+			require.NoError(t, c.Update(context.TODO(), &dep))
+
+			corrections := testutil.ToFloat64(driftCorrectionsTotal.WithLabelValues("default"))
+			require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+			assert.Equal(t, corrections+float64(len(tt.expectedEvents)), testutil.ToFloat64(driftCorrectionsTotal.WithLabelValues("default")))
+
+			close(er.Events)
+			var events []string
+			for event := range er.Events {
+				events = append(events, event)
+			}
+			assert.Equal(t, tt.expectedEvents, events)
+
+			require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &dep))
+			tt.assert(t, &dep)
+		})
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+
+func (r *NginxReconciler) reconcileGateway(ctx context.Context, nginx *nginxv1beta1.Nginx) error {
+	newRoute := k8s.NewHTTPRoute(nginx)
+
+	var currentRoute gatewayv1beta1.HTTPRoute
+	err := r.Client.Get(ctx, types.NamespacedName{Name: newRoute.Name, Namespace: newRoute.Namespace}, &currentRoute)
+	if meta.IsNoMatchError(err) {
+		if nginx.Spec.Gateway == nil {
+			return nil
+		}
+
+		setCondition(nginx, nginxv1beta1.ConditionRouteAccepted, metav1.ConditionFalse, reasonGatewayAPINotAvailable, "HTTPRoute kind is not available in the cluster")
+		return fmt.Errorf("failed to retrieve HTTPRoute resource: %w", err)
+	}
+
+	if errors.IsNotFound(err) {
+		if nginx.Spec.Gateway == nil {
+			return nil
+		}
+
+		if err = r.apply(ctx, newRoute, nil); err != nil {
+			setCondition(nginx, nginxv1beta1.ConditionRouteAccepted, metav1.ConditionFalse, reasonRouteFailed, err.Error())
+			return fmt.Errorf("failed to create HTTPRoute resource: %w", err)
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		setCondition(nginx, nginxv1beta1.ConditionRouteAccepted, metav1.ConditionFalse, reasonRouteFailed, err.Error())
+		return fmt.Errorf("failed to retrieve HTTPRoute resource: %w", err)
+	}
+
+	if nginx.Spec.Gateway == nil {
+		if err = r.Client.Delete(ctx, &currentRoute); client.IgnoreNotFound(err) != nil {
+			setCondition(nginx, nginxv1beta1.ConditionRouteAccepted, metav1.ConditionFalse, reasonRouteFailed, err.Error())
+			return fmt.Errorf("failed to delete HTTPRoute resource: %w", err)
+		}
+
+		return nil
+	}
+
+	// NOTE: annotations set by others (e.g. the Gateway controllers) are
+	// kept, as they aren't applied.
+	if err = r.apply(ctx, newRoute, &currentRoute); err != nil {
+		setCondition(nginx, nginxv1beta1.ConditionRouteAccepted, metav1.ConditionFalse, reasonRouteFailed, err.Error())
+		return fmt.Errorf("failed to apply HTTPRoute resource: %w", err)
+	}
+
+	return nil
+}
+
+// listRoutes returns the status of the HTTPRoutes of the given nginx sorted by
+// name. No route is returned when the Gateway API is not installed.
+func listRoutes(ctx context.Context, c client.Client, nginx *nginxv1beta1.Nginx) ([]nginxv1beta1.RouteStatus, error) {
+	var routeList gatewayv1beta1.HTTPRouteList
+	err := c.List(ctx, &routeList, &client.ListOptions{
+		Namespace:     nginx.Namespace,
+		LabelSelector: labels.SelectorFromSet(k8s.LabelsForNginx(nginx.Name)),
+	})
+	if meta.IsNoMatchError(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []nginxv1beta1.RouteStatus
+	for _, route := range routeList.Items {
+		rs := nginxv1beta1.RouteStatus{Name: route.Name}
+
+		for _, parent := range route.Status.Parents {
+			ps := nginxv1beta1.RouteParentStatus{
+				Name:      string(parent.ParentRef.Name),
+				Namespace: route.Namespace,
+			}
+
+			if parent.ParentRef.Namespace != nil {
+				ps.Namespace = string(*parent.ParentRef.Namespace)
+			}
+
+			if parent.ParentRef.SectionName != nil {
+				ps.SectionName = string(*parent.ParentRef.SectionName)
+			}
+
+			if cond := meta.FindStatusCondition(parent.Conditions, string(gatewayv1beta1.RouteConditionAccepted)); cond != nil {
+				ps.Accepted = cond.Status == metav1.ConditionTrue
+				ps.Reason = cond.Reason
+				ps.Message = cond.Message
+			}
+
+			rs.Parents = append(rs.Parents, ps)
+		}
+
+		sort.Slice(rs.Parents, func(i, j int) bool {
+			a, b := rs.Parents[i], rs.Parents[j]
+			if a.Namespace != b.Namespace {
+				return a.Namespace < b.Namespace
+			}
+
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+
+			return a.SectionName < b.SectionName
+		})
+
+		routes = append(routes, rs)
+	}
+
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Name < routes[j].Name })
+
+	return routes, nil
+}
+
+// setRouteCondition sets the RouteAccepted condition according to the status
+// reported by the Gateways on the Nginx's HTTPRoute.
+func setRouteCondition(nginx *nginxv1beta1.Nginx, routes []nginxv1beta1.RouteStatus) {
+	if nginx.Spec.Gateway == nil {
+		setCondition(nginx, nginxv1beta1.ConditionRouteAccepted, metav1.ConditionTrue, reasonRouteNotRequired, "")
+		return
+	}
+
+	var parents []nginxv1beta1.RouteParentStatus
+	for _, route := range routes {
+		if route.Name == nginx.Name {
+			parents = route.Parents
+		}
+	}
+
+	var notAccepted, pending []string
+	for _, ref := range nginx.Spec.Gateway.ParentRefs {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = nginx.Namespace
+		}
+
+		parent := findRouteParent(parents, namespace, ref.Name, ref.SectionName)
+		switch {
+		case parent == nil || parent.Reason == "":
+			pending = append(pending, namespace+"/"+ref.Name)
+		case !parent.Accepted:
+			notAccepted = append(notAccepted, fmt.Sprintf("%s/%s (%s: %s)", namespace, ref.Name, parent.Reason, parent.Message))
+		}
+	}
+
+	switch {
+	case len(notAccepted) > 0:
+		setCondition(nginx, nginxv1beta1.ConditionRouteAccepted, metav1.ConditionFalse, reasonRouteNotAccepted, fmt.Sprintf("HTTPRoute not accepted by Gateway(s): %s", strings.Join(notAccepted, ", ")))
+	case len(pending) > 0:
+		setCondition(nginx, nginxv1beta1.ConditionRouteAccepted, metav1.ConditionFalse, reasonRoutePending, fmt.Sprintf("waiting for Gateway(s) to accept the HTTPRoute: %s", strings.Join(pending, ", ")))
+	default:
+		setCondition(nginx, nginxv1beta1.ConditionRouteAccepted, metav1.ConditionTrue, reasonRouteAccepted, "")
+	}
+}
+
+func findRouteParent(parents []nginxv1beta1.RouteParentStatus, namespace, name, sectionName string) *nginxv1beta1.RouteParentStatus {
+	for i := range parents {
+		if p := &parents[i]; p.Namespace == namespace && p.Name == name && p.SectionName == sectionName {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+func TestNginxReconciler_reconcileGateway(t *testing.T) {
+	existing := func() *v1beta1.Nginx {
+		return &v1beta1.Nginx{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-1", Namespace: "default"},
+			Spec: v1beta1.NginxSpec{
+				Gateway: &v1beta1.NginxGateway{
+					ParentRefs: []v1beta1.NginxGatewayParentRef{{Name: "my-gateway"}},
+				},
+			},
+		}
+	}
+
+	applied := k8s.NewHTTPRoute(existing())
+
+	route := applied.DeepCopy()
+	route.Annotations = map[string]string{"gateway.example.com/status": "this annotation was created by the gateway controller"}
+
+	resources := []runtime.Object{route}
+
+	tests := map[string]struct {
+		nginx  *v1beta1.Nginx
+		assert func(t *testing.T, c client.Client)
+	}{
+		"without gateway spec and no route": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-2", Namespace: "default"},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var got gatewayv1beta1.HTTPRoute
+				err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-2", Namespace: "default"}, &got)
+				assert.True(t, errors.IsNotFound(err))
+			},
+		},
+
+		"creating route": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-2", Namespace: "default"},
+				Spec: v1beta1.NginxSpec{
+					TLS: []v1beta1.NginxTLS{{SecretName: "my-secret", Hosts: []string{"www.example.com"}}},
+					Gateway: &v1beta1.NginxGateway{
+						ParentRefs: []v1beta1.NginxGatewayParentRef{{Name: "my-gateway", Namespace: "gateways"}},
+					},
+				},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var got gatewayv1beta1.HTTPRoute
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-2", Namespace: "default"}, &got))
+				assert.Equal(t, []gatewayv1beta1.Hostname{"www.example.com"}, got.Spec.Hostnames)
+				require.Len(t, got.Spec.ParentRefs, 1)
+				assert.Equal(t, gatewayv1beta1.ObjectName("my-gateway"), got.Spec.ParentRefs[0].Name)
+				assert.Equal(t, gatewayv1beta1.Namespace("gateways"), *got.Spec.ParentRefs[0].Namespace)
+				assert.Equal(t, gatewayv1beta1.ObjectName("my-nginx-2-service"), got.Spec.Rules[0].BackendRefs[0].Name)
+			},
+		},
+
+		"updating route keeps annotations from other controllers": {
+			nginx: func() *v1beta1.Nginx {
+				n := existing()
+				n.Spec.Gateway.ParentRefs = append(n.Spec.Gateway.ParentRefs, v1beta1.NginxGatewayParentRef{Name: "other-gateway", SectionName: "http"})
+				n.Spec.Gateway.Annotations = map[string]string{"custom.example.com/key": "value"}
+				return n
+			}(),
+			assert: func(t *testing.T, c client.Client) {
+				var got gatewayv1beta1.HTTPRoute
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-1", Namespace: "default"}, &got))
+				assert.Equal(t, map[string]string{
+					"custom.example.com/key":     "value",
+					"gateway.example.com/status": "this annotation was created by the gateway controller",
+				}, got.Annotations)
+				require.Len(t, got.Spec.ParentRefs, 2)
+				assert.Equal(t, gatewayv1beta1.ObjectName("other-gateway"), got.Spec.ParentRefs[1].Name)
+				assert.Equal(t, gatewayv1beta1.SectionName("http"), *got.Spec.ParentRefs[1].SectionName)
+			},
+		},
+
+		"removing gateway spec deletes the route": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-1", Namespace: "default"},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var got gatewayv1beta1.HTTPRoute
+				err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-1", Namespace: "default"}, &got)
+				assert.True(t, errors.IsNotFound(err))
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := withServerSideApply(fake.NewClientBuilder().
+				WithScheme(newScheme()).
+				WithRuntimeObjects(resources...).
+				Build(), applied)
+
+			nginx := tt.nginx.DeepCopy()
+			r := &NginxReconciler{Client: client}
+			require.NoError(t, r.reconcileGateway(context.TODO(), nginx))
+			assert.Equal(t, tt.nginx.Spec, nginx.Spec)
+			tt.assert(t, client)
+		})
+	}
+}
+
+func TestListRoutes(t *testing.T) {
+	nginx := &v1beta1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"}}
+
+	resources := []runtime.Object{
+		&gatewayv1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-nginx",
+				Namespace: "default",
+				Labels: map[string]string{
+					"nginx.tsuru.io/app":           "nginx",
+					"nginx.tsuru.io/resource-name": "my-nginx",
+				},
+			},
+			Status: gatewayv1beta1.HTTPRouteStatus{
+				RouteStatus: gatewayv1beta1.RouteStatus{
+					Parents: []gatewayv1beta1.RouteParentStatus{
+						{
+							ParentRef: gatewayv1beta1.ParentReference{
+								Name:      "shared-gateway",
+								Namespace: func(ns gatewayv1beta1.Namespace) *gatewayv1beta1.Namespace { return &ns }("gateways"),
+							},
+							Conditions: []metav1.Condition{
+								{Type: "Accepted", Status: metav1.ConditionFalse, Reason: "NotAllowedByListeners", Message: "namespace not allowed"},
+							},
+						},
+						{
+							ParentRef: gatewayv1beta1.ParentReference{
+								Name:        "local-gateway",
+								SectionName: func(s gatewayv1beta1.SectionName) *gatewayv1beta1.SectionName { return &s }("https"),
+							},
+							Conditions: []metav1.Condition{
+								{Type: "Accepted", Status: metav1.ConditionTrue, Reason: "Accepted"},
+							},
+						},
+					},
+				},
+			},
+		},
+		&gatewayv1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "another-nginx",
+				Namespace: "default",
+				Labels: map[string]string{
+					"nginx.tsuru.io/app":           "nginx",
+					"nginx.tsuru.io/resource-name": "another-nginx",
+				},
+			},
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		WithRuntimeObjects(resources...).
+		Build()
+
+	routes, err := listRoutes(context.Background(), client, nginx)
+	assert.NoError(t, err)
+	assert.Equal(t, []v1beta1.RouteStatus{
+		{
+			Name: "my-nginx",
+			Parents: []v1beta1.RouteParentStatus{
+				{Name: "local-gateway", Namespace: "default", SectionName: "https", Accepted: true, Reason: "Accepted"},
+				{Name: "shared-gateway", Namespace: "gateways", Reason: "NotAllowedByListeners", Message: "namespace not allowed"},
+			},
+		},
+	}, routes)
+}
+
+func TestSetRouteCondition(t *testing.T) {
+	gateway := &v1beta1.NginxGateway{
+		ParentRefs: []v1beta1.NginxGatewayParentRef{
+			{Name: "local-gateway"},
+			{Name: "shared-gateway", Namespace: "gateways"},
+		},
+	}
+
+	tests := map[string]struct {
+		gateway         *v1beta1.NginxGateway
+		routes          []v1beta1.RouteStatus
+		expected        string
+		expectedMessage string
+	}{
+		"without gateway": {
+			expected: "True/RouteNotRequired",
+		},
+
+		"without route status": {
+			gateway:         gateway,
+			expected:        "False/RoutePending",
+			expectedMessage: "waiting for Gateway(s) to accept the HTTPRoute: default/local-gateway, gateways/shared-gateway",
+		},
+
+		"accepted by some gateways": {
+			gateway: gateway,
+			routes: []v1beta1.RouteStatus{
+				{
+					Name: "my-nginx",
+					Parents: []v1beta1.RouteParentStatus{
+						{Name: "local-gateway", Namespace: "default", Accepted: true, Reason: "Accepted"},
+					},
+				},
+			},
+			expected:        "False/RoutePending",
+			expectedMessage: "waiting for Gateway(s) to accept the HTTPRoute: gateways/shared-gateway",
+		},
+
+		"rejected by a gateway": {
+			gateway: gateway,
+			routes: []v1beta1.RouteStatus{
+				{
+					Name: "my-nginx",
+					Parents: []v1beta1.RouteParentStatus{
+						{Name: "local-gateway", Namespace: "default", Accepted: true, Reason: "Accepted"},
+						{Name: "shared-gateway", Namespace: "gateways", Reason: "NotAllowedByListeners", Message: "namespace not allowed"},
+					},
+				},
+			},
+			expected:        "False/RouteNotAccepted",
+			expectedMessage: "HTTPRoute not accepted by Gateway(s): gateways/shared-gateway (NotAllowedByListeners: namespace not allowed)",
+		},
+
+		"accepted by all gateways": {
+			gateway: gateway,
+			routes: []v1beta1.RouteStatus{
+				{
+					Name: "my-nginx",
+					Parents: []v1beta1.RouteParentStatus{
+						{Name: "local-gateway", Namespace: "default", Accepted: true, Reason: "Accepted"},
+						{Name: "shared-gateway", Namespace: "gateways", Accepted: true, Reason: "Accepted"},
+					},
+				},
+			},
+			expected: "True/RouteAccepted",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nginx := &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+				Spec:       v1beta1.NginxSpec{Gateway: tt.gateway},
+			}
+
+			setRouteCondition(nginx, tt.routes)
+			assert.Equal(t, map[string]string{v1beta1.ConditionRouteAccepted: tt.expected}, summarizeConditions(nginx.Status.Conditions))
+			assert.Equal(t, tt.expectedMessage, nginx.Status.Conditions[0].Message)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+// ipv6IngressDeletionInterval is the interval between checks of whether the
+// IPv6 ingress has been removed, while finalizing an Nginx.
+const ipv6IngressDeletionInterval = 10 * time.Second
+
+func ipv6IngressName(nginx *nginxv1beta1.Nginx) string {
+	return fmt.Sprintf("%s-ipv6", nginx.Name)
+}
+
+// finalizeNginx releases the external resources of a deleted Nginx, then
+// removes its finalizers.
+//
+// NOTE: the IPv6 address can only be released once the load balancer stops
+// using it, so the IPv6 ingress is explicitly deleted (rather than left to the
+// garbage collector) and the address is released after it's gone.
+func (r *NginxReconciler) finalizeNginx(ctx context.Context, nginx *nginxv1beta1.Nginx) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(nginx, ipv6AddressFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var ingress networkingv1.Ingress
+	err := r.Client.Get(ctx, types.NamespacedName{Name: ipv6IngressName(nginx), Namespace: nginx.Namespace}, &ingress)
+	if err == nil {
+		if ingress.DeletionTimestamp.IsZero() {
+			if err = r.Client.Delete(ctx, &ingress); client.IgnoreNotFound(err) != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to delete Ingress resource: %w", err)
+			}
+		}
+
+		return ctrl.Result{RequeueAfter: ipv6IngressDeletionInterval}, nil
+	}
+
+	if !errors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to retrieve Ingress resource: %w", err)
+	}
+
+	if err = r.releaseIPv6Address(ctx, nginx); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// releaseIPv6Address releases the GCP IPv6 address reserved to the Nginx, if
+// any, and removes the finalizer which protects it.
+func (r *NginxReconciler) releaseIPv6Address(ctx context.Context, nginx *nginxv1beta1.Nginx) error {
+	if !controllerutil.ContainsFinalizer(nginx, ipv6AddressFinalizer) {
+		return nil
+	}
+
+	name := ipv6IngressName(nginx)
+	if err := r.GcpClient.ReleaseIPV6(ctx, name); err != nil {
+		r.EventRecorder.Eventf(nginx, corev1.EventTypeWarning, "IPv6AddressReleaseFailed", "failed to release IPv6 address %s: %s", name, err)
+		return fmt.Errorf("failed to release IPv6 address: %w", err)
+	}
+
+	r.EventRecorder.Eventf(nginx, corev1.EventTypeNormal, "IPv6AddressReleased", "IPv6 address %s released successfully", name)
+
+	return r.removeFinalizer(ctx, nginx, ipv6AddressFinalizer)
+}
+
+// addFinalizer adds the finalizer to the Nginx, keeping the in-memory status
+// (which might be updated later in the reconciliation) untouched.
+func (r *NginxReconciler) addFinalizer(ctx context.Context, nginx *nginxv1beta1.Nginx, finalizer string) error {
+	if controllerutil.ContainsFinalizer(nginx, finalizer) {
+		return nil
+	}
+
+	updated := nginx.DeepCopy()
+	controllerutil.AddFinalizer(updated, finalizer)
+	if err := r.Client.Patch(ctx, updated, client.MergeFromWithOptions(nginx, client.MergeFromWithOptimisticLock{})); err != nil {
+		return fmt.Errorf("failed to add finalizer to Nginx resource: %w", err)
+	}
+
+	nginx.Finalizers = updated.Finalizers
+	nginx.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
+func (r *NginxReconciler) removeFinalizer(ctx context.Context, nginx *nginxv1beta1.Nginx, finalizer string) error {
+	updated := nginx.DeepCopy()
+	controllerutil.RemoveFinalizer(updated, finalizer)
+	if err := r.Client.Patch(ctx, updated, client.MergeFromWithOptions(nginx, client.MergeFromWithOptimisticLock{})); err != nil {
+		return fmt.Errorf("failed to remove finalizer from Nginx resource: %w", err)
+	}
+
+	nginx.Finalizers = updated.Finalizers
+	nginx.ResourceVersion = updated.ResourceVersion
+	return nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/gcp"
+)
+
+func TestNginxReconciler_finalizeNginx(t *testing.T) {
+	deletedNginx := func(name string, finalizers ...string) *v1beta1.Nginx {
+		return &v1beta1.Nginx{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				Finalizers:        finalizers,
+				DeletionTimestamp: &metav1.Time{Time: time.Now()},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		nginx          *v1beta1.Nginx
+		resources      []runtime.Object
+		gcpClient      *gcp.MockGcpClient
+		expectedResult ctrl.Result
+		expectedError  string
+		expectedEvents []string
+		assert         func(t *testing.T, c client.Client, gcpClient *gcp.MockGcpClient)
+	}{
+		"without the ipv6 finalizer": {
+			nginx:     deletedNginx("my-nginx", "test/finalizer"),
+			gcpClient: &gcp.MockGcpClient{Adresses: []string{"my-nginx-ipv6"}},
+			assert: func(t *testing.T, c client.Client, gcpClient *gcp.MockGcpClient) {
+				assert.Equal(t, []string{"my-nginx-ipv6"}, gcpClient.Adresses)
+			},
+		},
+
+		"deletes the ipv6 ingress before releasing the address": {
+			nginx: deletedNginx("my-nginx", ipv6AddressFinalizer),
+			resources: []runtime.Object{
+				&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-ipv6", Namespace: "default", Finalizers: []string{"networking.gke.io/ingress-finalizer-V2"}},
+				},
+			},
+			gcpClient:      &gcp.MockGcpClient{Adresses: []string{"my-nginx-ipv6"}},
+			expectedResult: ctrl.Result{RequeueAfter: ipv6IngressDeletionInterval},
+			assert: func(t *testing.T, c client.Client, gcpClient *gcp.MockGcpClient) {
+				var got networkingv1.Ingress
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-ipv6", Namespace: "default"}, &got))
+				assert.NotNil(t, got.DeletionTimestamp)
+
+				assert.Equal(t, []string{"my-nginx-ipv6"}, gcpClient.Adresses)
+
+				var nginx v1beta1.Nginx
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &nginx))
+				assert.Equal(t, []string{ipv6AddressFinalizer}, nginx.Finalizers)
+			},
+		},
+
+		"releases the address after the ipv6 ingress is gone": {
+			nginx:          deletedNginx("my-nginx", ipv6AddressFinalizer, "test/finalizer"),
+			gcpClient:      &gcp.MockGcpClient{Adresses: []string{"my-nginx-ipv6", "other-nginx-ipv6"}},
+			expectedEvents: []string{"Normal IPv6AddressReleased IPv6 address my-nginx-ipv6 released successfully"},
+			assert: func(t *testing.T, c client.Client, gcpClient *gcp.MockGcpClient) {
+				assert.Equal(t, []string{"other-nginx-ipv6"}, gcpClient.Adresses)
+
+				var nginx v1beta1.Nginx
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &nginx))
+				assert.Equal(t, []string{"test/finalizer"}, nginx.Finalizers)
+			},
+		},
+
+		"when address release fails": {
+			nginx:          deletedNginx("my-nginx", ipv6AddressFinalizer),
+			gcpClient:      &gcp.MockGcpClient{Adresses: []string{"my-nginx-ipv6"}, ReleaseErr: fmt.Errorf("address in use")},
+			expectedError:  "failed to release IPv6 address: address in use",
+			expectedEvents: []string{"Warning IPv6AddressReleaseFailed failed to release IPv6 address my-nginx-ipv6: address in use"},
+			assert: func(t *testing.T, c client.Client, gcpClient *gcp.MockGcpClient) {
+				var nginx v1beta1.Nginx
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &nginx))
+				assert.Equal(t, []string{ipv6AddressFinalizer}, nginx.Finalizers)
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewClientBuilder().
+				WithScheme(newScheme()).
+				WithRuntimeObjects(append(tt.resources, tt.nginx)...).
+				Build()
+
+			er := record.NewFakeRecorder(10)
+			r := &NginxReconciler{Client: client, EventRecorder: er, GcpClient: tt.gcpClient}
+
+			result, err := r.finalizeNginx(context.TODO(), tt.nginx.DeepCopy())
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedResult, result)
+
+			close(er.Events)
+			var events []string
+			for event := range er.Events {
+				events = append(events, event)
+			}
+			assert.Equal(t, tt.expectedEvents, events)
+
+			tt.assert(t, client, tt.gcpClient)
+		})
+	}
+}
+
+func TestNginxReconciler_reconcileIngress_releasesIPv6Address(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+		Spec: v1beta1.NginxSpec{
+			Ingress: &v1beta1.NginxIngress{
+				Annotations: map[string]string{nginxIpv6GcpAnnotation: "true"},
+			},
+		},
+	}
+
+	client := withServerSideApply(fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		WithRuntimeObjects(nginx.DeepCopy()).
+		Build())
+
+	require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, nginx))
+
+	er := record.NewFakeRecorder(10)
+	gcpClient := &gcp.MockGcpClient{}
+	r := &NginxReconciler{Client: client, EventRecorder: er, GcpClient: gcpClient}
+
+	require.NoError(t, r.reconcileIngress(context.TODO(), nginx))
+	assert.Equal(t, []string{"my-nginx-ipv6"}, gcpClient.Adresses)
+	assert.Equal(t, []string{ipv6AddressFinalizer}, nginx.Finalizers)
+
+	nginx.Spec.Ingress.Annotations = nil
+
+	// first reconciliation deletes the ipv6 ingress, the next one releases
+	// the address as the ingress is gone.
+	require.NoError(t, r.reconcileIngress(context.TODO(), nginx))
+	var ingress networkingv1.Ingress
+	err := client.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-ipv6", Namespace: "default"}, &ingress)
+	assert.True(t, errors.IsNotFound(err))
+
+	require.NoError(t, r.reconcileIngress(context.TODO(), nginx))
+	assert.Empty(t, gcpClient.Adresses)
+	assert.Empty(t, nginx.Finalizers)
+
+	var got v1beta1.Nginx
+	require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &got))
+	assert.Empty(t, got.Finalizers)
+
+	close(er.Events)
+	var events []string
+	for event := range er.Events {
+		events = append(events, event)
+	}
+	assert.Equal(t, []string{"Normal IPv6AddressReleased IPv6 address my-nginx-ipv6 released successfully"}, events)
+}
+
+func TestNginxReconciler_addFinalizer_conflict(t *testing.T) {
+	nginx := &v1beta1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"}}
+
+	client := fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		WithRuntimeObjects(nginx).
+		Build()
+
+	var stale v1beta1.Nginx
+	require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &stale))
+
+	var got v1beta1.Nginx
+	require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &got))
+	got.Finalizers = []string{"test/finalizer"}
+	require.NoError(t, client.Update(context.TODO(), &got))
+
+	r := &NginxReconciler{Client: client}
+	err := r.addFinalizer(context.TODO(), &stale, ipv6AddressFinalizer)
+	assert.True(t, errors.IsConflict(err), err)
+
+	require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &got))
+	assert.Equal(t, []string{"test/finalizer"}, got.Finalizers)
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+const (
+	stepDeployment  = "deployment"
+	stepService     = "service"
+	stepIngress     = "ingress"
+	stepIPv6Ingress = "ipv6_ingress"
+	stepStatus      = "status"
+
+	resultSuccess = "success"
+	resultError   = "error"
+
+	metricsListTimeout = 10 * time.Second
+)
+
+var (
+	reconcileStepTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nginx_operator_reconcile_step_total",
+		Help: "Number of reconciliations of each sub-step of the Nginx, by result.",
+	}, []string{"step", "result"})
+
+	serviceQuotaExceededTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nginx_operator_service_quota_exceeded_total",
+		Help: "Number of Services which couldn't be created as the namespace quota was exceeded.",
+	}, []string{"namespace"})
+
+	driftCorrectionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nginx_operator_drift_corrections_total",
+		Help: "Number of Deployments restored after being manually changed.",
+	}, []string{"namespace"})
+
+	nginxesDesc = prometheus.NewDesc(
+		"nginx_operator_nginxes",
+		"Number of Nginx objects managed by the operator.",
+		[]string{"namespace"}, nil,
+	)
+
+	nginxes = &nginxCollector{}
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		reconcileStepTotal,
+		serviceQuotaExceededTotal,
+		driftCorrectionsTotal,
+		nginxes,
+	)
+}
+
+// observeReconcileStep counts the outcome of a reconciliation sub-step,
+// returning its error as is.
+func observeReconcileStep(step string, err error) error {
+	result := resultSuccess
+	if err != nil {
+		result = resultError
+	}
+
+	reconcileStepTotal.WithLabelValues(step, result).Inc()
+	return err
+}
+
+// nginxCollector reports the number of Nginx objects per namespace, read from
+// the manager's cache on every scrape. It's registered once, the reconciler
+// whose client lists the Nginx objects is set up along with the controller.
+type nginxCollector struct {
+	mu sync.RWMutex
+	r  *NginxReconciler
+}
+
+func (c *nginxCollector) setReconciler(r *NginxReconciler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.r = r
+}
+
+func (c *nginxCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- nginxesDesc
+}
+
+func (c *nginxCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	r := c.r
+	c.mu.RUnlock()
+
+	if r == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), metricsListTimeout)
+	defer cancel()
+
+	var nginxList nginxv1beta1.NginxList
+	if err := r.Client.List(ctx, &nginxList); err != nil {
+		ch <- prometheus.NewInvalidMetric(nginxesDesc, err)
+		return
+	}
+
+	counts := make(map[string]int)
+	for i := range nginxList.Items {
+		if r.shouldManageNginx(&nginxList.Items[i]) {
+			counts[nginxList.Items[i].Namespace]++
+		}
+	}
+
+	for namespace, count := range counts {
+		ch <- prometheus.MustNewConstMetric(nginxesDesc, prometheus.GaugeValue, float64(count), namespace)
+	}
+}
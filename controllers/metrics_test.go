@@ -0,0 +1,104 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+func TestObserveReconcileStep(t *testing.T) {
+	success := testutil.ToFloat64(reconcileStepTotal.WithLabelValues(stepService, resultSuccess))
+	failure := testutil.ToFloat64(reconcileStepTotal.WithLabelValues(stepService, resultError))
+
+	assert.NoError(t, observeReconcileStep(stepService, nil))
+	assert.EqualError(t, observeReconcileStep(stepService, errors.New("some error")), "some error")
+	assert.EqualError(t, observeReconcileStep(stepService, errors.New("some error")), "some error")
+
+	assert.Equal(t, success+1, testutil.ToFloat64(reconcileStepTotal.WithLabelValues(stepService, resultSuccess)))
+	assert.Equal(t, failure+2, testutil.ToFloat64(reconcileStepTotal.WithLabelValues(stepService, resultError)))
+}
+
+func TestNginxCollector(t *testing.T) {
+	newNginx := func(name, namespace string, annotations map[string]string) *v1beta1.Nginx {
+		return &v1beta1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Annotations: annotations}}
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		WithObjects(
+			newNginx("nginx-1", "default", map[string]string{"tsuru.io/pool": "pool-a"}),
+			newNginx("nginx-2", "default", map[string]string{"tsuru.io/pool": "pool-a"}),
+			newNginx("nginx-3", "default", map[string]string{"tsuru.io/pool": "pool-b"}),
+			newNginx("nginx-1", "tsuru", map[string]string{"tsuru.io/pool": "pool-a"}),
+			newNginx("nginx-1", "other", nil),
+		).
+		Build()
+
+	tests := map[string]struct {
+		filter   string
+		expected string
+	}{
+		"without filter": {
+			expected: `
+# HELP nginx_operator_nginxes Number of Nginx objects managed by the operator.
+# TYPE nginx_operator_nginxes gauge
+nginx_operator_nginxes{namespace="default"} 3
+nginx_operator_nginxes{namespace="other"} 1
+nginx_operator_nginxes{namespace="tsuru"} 1
+`,
+		},
+
+		"with annotation filter": {
+			filter: "tsuru.io/pool=pool-a",
+			expected: `
+# HELP nginx_operator_nginxes Number of Nginx objects managed by the operator.
+# TYPE nginx_operator_nginxes gauge
+nginx_operator_nginxes{namespace="default"} 2
+nginx_operator_nginxes{namespace="tsuru"} 1
+`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			filter, err := labels.Parse(tt.filter)
+			require.NoError(t, err)
+
+			collector := &nginxCollector{r: &NginxReconciler{Client: c, AnnotationFilter: filter}}
+			assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(tt.expected)))
+		})
+	}
+}
+
+func TestNginxCollector_setReconciler(t *testing.T) {
+	collector := &nginxCollector{}
+	assert.Equal(t, 0, testutil.CollectAndCount(collector))
+
+	c := fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		WithObjects(&v1beta1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"}}).
+		Build()
+
+	// NOTE: setting the controller up again replaces the reconciler, instead
+	// of registering another collector.
+	collector.setReconciler(&NginxReconciler{Client: fake.NewClientBuilder().WithScheme(newScheme()).Build(), AnnotationFilter: labels.Everything()})
+	collector.setReconciler(&NginxReconciler{Client: c, AnnotationFilter: labels.Everything()})
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP nginx_operator_nginxes Number of Nginx objects managed by the operator.
+# TYPE nginx_operator_nginxes gauge
+nginx_operator_nginxes{namespace="default"} 1
+`)))
+}
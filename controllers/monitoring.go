@@ -0,0 +1,56 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors;servicemonitors,verbs=get;list;watch;create;update;patch;delete
+
+// reconcileMonitor applies the PodMonitor or ServiceMonitor of the Nginx.
+// Monitors of the other kind, or no longer desired, are deleted by
+// pruneOrphans.
+func (r *NginxReconciler) reconcileMonitor(ctx context.Context, nginx *nginxv1beta1.Nginx) error {
+	desired := k8s.NewMonitor(nginx)
+	if desired == nil {
+		return nil
+	}
+
+	kind := desired.GetKind()
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(desired.GroupVersionKind())
+	err := r.Client.Get(ctx, types.NamespacedName{Name: desired.GetName(), Namespace: desired.GetNamespace()}, current)
+	if meta.IsNoMatchError(err) {
+		// NOTE: the Prometheus Operator is optional, the exporter sidecar
+		// can still be scraped by other means.
+		return nil
+	}
+
+	var live client.Object
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return fmt.Errorf("failed to retrieve %s resource: %w", kind, err)
+	default:
+		live = current
+	}
+
+	if err = r.apply(ctx, desired, live); err != nil {
+		return fmt.Errorf("failed to apply %s resource: %w", kind, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+func TestNginxReconciler_reconcileMonitor(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+		Spec: v1beta1.NginxSpec{
+			Monitoring: &v1beta1.NginxMonitoring{Labels: map[string]string{"release": "prometheus"}},
+		},
+	}
+
+	c := withServerSideApply(fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		Build())
+
+	er := record.NewFakeRecorder(10)
+	r := &NginxReconciler{Client: c, EventRecorder: er}
+	require.NoError(t, r.reconcileMonitor(context.TODO(), nginx))
+
+	podMonitor := getMonitor(t, c, k8s.PodMonitorGVK)
+	assert.Equal(t, "prometheus", podMonitor.GetLabels()["release"])
+	assert.True(t, metav1.IsControlledBy(podMonitor, nginx))
+
+	nginx.Spec.Monitoring.Interval = "15s"
+	require.NoError(t, r.reconcileMonitor(context.TODO(), nginx))
+
+	podMonitor = getMonitor(t, c, k8s.PodMonitorGVK)
+	endpoints, _, _ := unstructured.NestedSlice(podMonitor.Object, "spec", "podMetricsEndpoints")
+	assert.Equal(t, []any{map[string]any{"port": "metrics", "interval": "15s"}}, endpoints)
+
+	nginx.Spec.Monitoring.Monitor = v1beta1.NginxMonitorServiceMonitor
+	require.NoError(t, r.reconcileMonitor(context.TODO(), nginx))
+	require.NoError(t, r.pruneOrphans(context.TODO(), nginx))
+
+	getMonitor(t, c, k8s.ServiceMonitorGVK)
+	err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, newMonitor(k8s.PodMonitorGVK))
+	assert.True(t, errors.IsNotFound(err))
+
+	nginx.Spec.Monitoring = nil
+	require.NoError(t, r.reconcileMonitor(context.TODO(), nginx))
+	require.NoError(t, r.pruneOrphans(context.TODO(), nginx))
+
+	err = c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, newMonitor(k8s.ServiceMonitorGVK))
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestNginxReconciler_reconcileMonitor_withoutPrometheusOperator(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+		Spec:       v1beta1.NginxSpec{Monitoring: &v1beta1.NginxMonitoring{}},
+	}
+
+	c := &noMonitorsClient{Client: fake.NewClientBuilder().WithScheme(newScheme()).Build()}
+	r := &NginxReconciler{Client: c, EventRecorder: record.NewFakeRecorder(10)}
+	assert.NoError(t, r.reconcileMonitor(context.TODO(), nginx))
+}
+
+// noMonitorsClient behaves as if the Prometheus Operator CRDs weren't
+// installed in the cluster.
+type noMonitorsClient struct {
+	client.Client
+}
+
+func (c *noMonitorsClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if gvk := obj.GetObjectKind().GroupVersionKind(); gvk.Group == k8s.PodMonitorGVK.Group {
+		return &meta.NoKindMatchError{GroupKind: gvk.GroupKind(), SearchedVersions: []string{gvk.Version}}
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func newMonitor(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	monitor := &unstructured.Unstructured{}
+	monitor.SetGroupVersionKind(gvk)
+	return monitor
+}
+
+func getMonitor(t *testing.T, c client.Client, gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	monitor := newMonitor(gvk)
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, monitor))
+	return monitor
+}
@@ -15,19 +15,28 @@ import (
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
-	nginxv1alpha1 "github.com/tsuru/nginx-operator/api/v1alpha1"
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
 	"github.com/tsuru/nginx-operator/pkg/gcp"
 	"github.com/tsuru/nginx-operator/pkg/k8s"
 )
@@ -36,9 +45,9 @@ const (
 	gcpNetworkTierAnnotationKey = "cloud.google.com/network-tier"
 	ociLoadBalancerTLSSecret    = "service.beta.kubernetes.io/oci-load-balancer-tls-secret"
 	ociLoadBalancerSSLPorts     = "service.beta.kubernetes.io/oci-load-balancer-ssl-ports"
-	useHTTPSOverHTTPAnnotation  = "nginx.tsuru.io/https-over-http"
 	nginxIpv6GcpAnnotation      = "nginx.tsuru.io/allocate-gcp-ipv6"
 	ingressStaticIPAnnotation   = "kubernetes.io/ingress.global-static-ip-name"
+	ipv6AddressFinalizer        = "nginx.tsuru.io/gcp-ipv6-address"
 )
 
 // NginxReconciler reconciles a Nginx object
@@ -49,28 +58,88 @@ type NginxReconciler struct {
 	Scheme           *runtime.Scheme
 	Log              logr.Logger
 	GcpClient        gcp.GcpClient
+	// PruneDryRun makes the pruning of orphaned resources only report (via
+	// events) what would be deleted.
+	PruneDryRun bool
+	// ConfigTest gates the Deployment updates on "nginx -t" passing in a
+	// Job, with the new image, config and mounted files.
+	ConfigTest bool
+	// PodExecutor runs "nginx -s reload" in the nginx pods, when their TLS
+	// certificates are reloaded in place.
+	PodExecutor k8s.PodExecutor
 }
 
 // +kubebuilder:rbac:groups=nginx.tsuru.io,resources=nginxes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=nginx.tsuru.io,resources=nginxes/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;delete
-// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 
 func (r *NginxReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&nginxv1alpha1.Nginx{}).
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &nginxv1beta1.Nginx{}, configMapsIndexKey, indexNginxByConfigMaps); err != nil {
+		return err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &nginxv1beta1.Nginx{}, tlsSecretsIndexKey, indexNginxByTLSSecrets); err != nil {
+		return err
+	}
+
+	nginxes.setReconciler(r)
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&nginxv1beta1.Nginx{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&networkingv1.Ingress{}).
 		Owns(&corev1.Service{}).
-		Complete(r)
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
+		Owns(&batchv1.Job{}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.nginxesReferencing(configMapsIndexKey))).
+		// NOTE: only the metadata of the Secrets is cached, as they're
+		// watched across the whole cluster (or namespace) to be told of the
+		// TLS certificate changes. Their data is read straight from the API
+		// server, see ClientDisableCacheFor in main.go.
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.nginxesReferencing(tlsSecretsIndexKey)), builder.OnlyMetadata)
+
+	// NOTE: the Gateway API, cert-manager and the Prometheus Operator are
+	// optional add-ons, their resources are only watched when the CRDs are
+	// installed.
+	if hasKind(mgr, gatewayv1beta1.SchemeGroupVersion.WithKind("HTTPRoute")) {
+		b = b.Owns(&gatewayv1beta1.HTTPRoute{})
+	}
+
+	if hasKind(mgr, k8s.CertificateGVK) {
+		cert := &unstructured.Unstructured{}
+		cert.SetGroupVersionKind(k8s.CertificateGVK)
+		b = b.Owns(cert)
+	}
+
+	for _, gvk := range []schema.GroupVersionKind{k8s.PodMonitorGVK, k8s.ServiceMonitorGVK} {
+		if hasKind(mgr, gvk) {
+			monitor := &unstructured.Unstructured{}
+			monitor.SetGroupVersionKind(gvk)
+			b = b.Owns(monitor)
+		}
+	}
+
+	return b.Complete(r)
+}
+
+func hasKind(mgr ctrl.Manager, gvk schema.GroupVersionKind) bool {
+	_, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	return err == nil
 }
 
 func (r *NginxReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithValues("nginx", req.NamespacedName)
 
-	var instance nginxv1alpha1.Nginx
+	var instance nginxv1beta1.Nginx
 	err := r.Client.Get(ctx, req.NamespacedName, &instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -87,45 +156,147 @@ func (r *NginxReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{Requeue: true, RequeueAfter: 5 * time.Minute}, nil
 	}
 
+	if !instance.DeletionTimestamp.IsZero() {
+		return r.finalizeNginx(ctx, &instance)
+	}
+
+	if updated, err := r.rollbackToRevision(ctx, &instance); err != nil || updated {
+		return ctrl.Result{}, err
+	}
+
+	status := instance.Status.DeepCopy()
+
 	if err := r.reconcileNginx(ctx, &instance); err != nil {
 		log.Error(err, "Fail to reconcile")
+
+		setCondition(&instance, nginxv1beta1.ConditionReady, metav1.ConditionFalse, reasonReconcileFailed, err.Error())
+		if statusErr := r.updateStatus(ctx, &instance, *status); statusErr != nil {
+			log.Error(statusErr, "Fail to update status subresource")
+		}
+
 		return ctrl.Result{}, err
 	}
 
-	if err := r.refreshStatus(ctx, &instance); err != nil {
+	if err := observeReconcileStep(stepStatus, r.refreshStatus(ctx, &instance, *status)); err != nil {
 		log.Error(err, "Fail to refresh status subresource")
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	retryTLSReload, err := r.reconcileTLSReload(ctx, &instance)
+	if err != nil {
+		log.Error(err, "Fail to reload TLS certificates")
+		return ctrl.Result{}, err
+	}
+
+	// NOTE: nothing changes on the Deployments while the canary is baking or
+	// the previous color is kept, so the next step has to be scheduled.
+	result := ctrl.Result{RequeueAfter: requeueAfter(&instance, time.Now())}
+	if retryTLSReload && (result.RequeueAfter == 0 || result.RequeueAfter > tlsReloadRetryInterval) {
+		result.RequeueAfter = tlsReloadRetryInterval
+	}
+
+	return result, nil
 }
 
-func (r *NginxReconciler) reconcileNginx(ctx context.Context, nginx *nginxv1alpha1.Nginx) error {
-	if err := r.reconcileDeployment(ctx, nginx); err != nil {
+func (r *NginxReconciler) reconcileNginx(ctx context.Context, nginx *nginxv1beta1.Nginx) error {
+	if err := observeReconcileStep(stepDeployment, r.reconcileDeployment(ctx, nginx)); err != nil {
 		return err
 	}
-	if err := r.reconcileService(ctx, nginx); err != nil {
+	if err := observeReconcileStep(stepService, r.reconcileService(ctx, nginx)); err != nil {
 		return err
 	}
 	if err := r.reconcileIngress(ctx, nginx); err != nil {
 		return err
 	}
+	if err := r.reconcileGateway(ctx, nginx); err != nil {
+		return err
+	}
+	if err := r.reconcileDisruptionBudget(ctx, nginx); err != nil {
+		return err
+	}
+	if err := r.reconcileAutoscaler(ctx, nginx); err != nil {
+		return err
+	}
+	if err := r.reconcileCertificates(ctx, nginx); err != nil {
+		return err
+	}
+	if err := r.reconcileMonitor(ctx, nginx); err != nil {
+		return err
+	}
+	if err := r.pruneOrphans(ctx, nginx); err != nil {
+		return err
+	}
+	// NOTE: a spec held back by the config test was never rolled out, thus
+	// it's no revision to roll back to.
+	if !meta.IsStatusConditionTrue(nginx.Status.Conditions, nginxv1beta1.ConditionConfigValid) {
+		return nil
+	}
+	if err := r.reconcileRevisions(ctx, nginx); err != nil {
+		return err
+	}
 	return nil
 }
 
-func (r *NginxReconciler) reconcileDeployment(ctx context.Context, nginx *nginxv1alpha1.Nginx) error {
+func (r *NginxReconciler) reconcileDeployment(ctx context.Context, nginx *nginxv1beta1.Nginx) error {
 	newDeploy, err := k8s.NewDeployment(nginx)
 	if err != nil {
+		// NOTE: the current Deployment is kept as is, so a broken config
+		// (e.g. a template failing to render) never reaches the nginx pods.
+		r.EventRecorder.Eventf(nginx, corev1.EventTypeWarning, reasonInvalidConfig, "failed to build Deployment: %s", err)
+		setCondition(nginx, nginxv1beta1.ConditionConfigValid, metav1.ConditionFalse, reasonInvalidConfig, err.Error())
 		return fmt.Errorf("failed to build Deployment from Nginx: %w", err)
 	}
 
+	configMaps, err := r.referencedConfigMaps(ctx, nginx)
+	if err != nil {
+		return err
+	}
+
+	if err = k8s.ValidateConfig(nginx, configMaps); err != nil {
+		r.EventRecorder.Eventf(nginx, corev1.EventTypeWarning, reasonInvalidConfig, "invalid nginx config: %s", err)
+		setCondition(nginx, nginxv1beta1.ConditionConfigValid, metav1.ConditionFalse, reasonInvalidConfig, err.Error())
+		return fmt.Errorf("invalid nginx config: %w", err)
+	}
+
+	setCondition(nginx, nginxv1beta1.ConditionConfigValid, metav1.ConditionTrue, reasonValidConfig, "")
+
+	if err = k8s.SetConfigMapsHash(newDeploy, configMaps); err != nil {
+		return fmt.Errorf("failed to hash the referenced ConfigMaps: %w", err)
+	}
+
+	secrets, err := r.referencedTLSSecrets(ctx, nginx)
+	if err != nil {
+		return err
+	}
+
+	if err = k8s.SetTLSSecretsHash(nginx, newDeploy, secrets); err != nil {
+		return fmt.Errorf("failed to hash the TLS Secrets: %w", err)
+	}
+
+	// NOTE: neither drift correction nor automatic rollbacks apply to the
+	// blue/green Deployments, the previous color is kept for switching back.
+	if isBlueGreenStrategy(nginx) {
+		if err = r.reconcileBlueGreen(ctx, nginx, newDeploy); err != nil {
+			setCondition(nginx, nginxv1beta1.ConditionProgressing, metav1.ConditionFalse, reasonDeploymentFailed, err.Error())
+			return err
+		}
+
+		return nil
+	}
+
 	var currentDeploy appsv1.Deployment
 	err = r.Client.Get(ctx, types.NamespacedName{Name: newDeploy.Name, Namespace: newDeploy.Namespace}, &currentDeploy)
 	if errors.IsNotFound(err) {
-		return r.Client.Create(ctx, newDeploy)
+		if err = r.apply(ctx, newDeploy, nil); err != nil {
+			setCondition(nginx, nginxv1beta1.ConditionProgressing, metav1.ConditionFalse, reasonDeploymentFailed, err.Error())
+			return err
+		}
+
+		return nil
 	}
 
 	if err != nil {
+		setCondition(nginx, nginxv1beta1.ConditionProgressing, metav1.ConditionFalse, reasonDeploymentFailed, err.Error())
 		return fmt.Errorf("failed to retrieve Deployment: %w", err)
 	}
 
@@ -134,49 +305,140 @@ func (r *NginxReconciler) reconcileDeployment(ctx context.Context, nginx *nginxv
 		return fmt.Errorf("failed to extract Nginx spec from Deployment annotations: %w", err)
 	}
 
-	if reflect.DeepEqual(nginx.Spec, existingNginxSpec) {
+	desiredNginxSpec, err := k8s.ExtractNginxSpec(newDeploy.ObjectMeta)
+	if err != nil {
+		return fmt.Errorf("failed to extract Nginx spec from new Deployment annotations: %w", err)
+	}
+
+	rolledBack, err := isRolledBack(newDeploy, &currentDeploy)
+	if err != nil {
+		return fmt.Errorf("failed to check Deployment rollback: %w", err)
+	}
+
+	if rolledBack {
 		return nil
 	}
 
-	replicas := currentDeploy.Spec.Replicas
+	// NOTE: the ConfigMaps are mounted with subPath, so their changes only
+	// reach the nginx pods through a rollout. So do the TLS certificates when
+	// they are reloaded through rolling restarts.
+	var drift []string
+	if reflect.DeepEqual(desiredNginxSpec, existingNginxSpec) &&
+		sameTemplateAnnotation(&currentDeploy, newDeploy, k8s.ConfigHashAnnotation) &&
+		sameTemplateAnnotation(&currentDeploy, newDeploy, k8s.TLSHashAnnotation) {
+		if isCanaryStrategy(nginx) {
+			if err = r.deleteCanary(ctx, nginx); err != nil {
+				return err
+			}
+		}
+
+		if rolledBack, err = r.rollback(ctx, nginx, newDeploy, &currentDeploy); err != nil || rolledBack {
+			return err
+		}
 
-	patch := client.StrategicMergeFrom(currentDeploy.DeepCopy())
-	currentDeploy.Spec = newDeploy.Spec
+		if !shouldCorrectDrift(nginx) {
+			return nil
+		}
 
-	if newDeploy.Spec.Replicas == nil {
-		// NOTE: replicas field is set to nil whenever it's managed by some
-		// autoscaler controller e.g HPA.
-		currentDeploy.Spec.Replicas = replicas
+		drift, err = templateDrift(&newDeploy.Spec.Template, &currentDeploy.Spec.Template)
+		if err != nil {
+			return fmt.Errorf("failed to detect Deployment drift: %w", err)
+		}
+
+		if len(drift) == 0 {
+			return nil
+		}
 	}
 
-	err = k8s.SetNginxSpec(&currentDeploy.ObjectMeta, nginx.Spec)
-	if err != nil {
-		return fmt.Errorf("failed to set Nginx spec in Deployment annotations: %w", err)
+	// NOTE: restoring a drifted Deployment rolls back to the config already
+	// running, thus there's nothing new to be tested.
+	if r.ConfigTest && len(drift) == 0 {
+		passed, err := r.testConfig(ctx, nginx, newDeploy)
+		if err != nil || !passed {
+			return err
+		}
 	}
 
-	err = r.Client.Patch(ctx, &currentDeploy, patch)
-	if err != nil {
-		return fmt.Errorf("failed to patch Deployment: %w", err)
+	canary := isCanaryStrategy(nginx) && len(drift) == 0 &&
+		podTemplateChanged(newDeploy, &currentDeploy, desiredNginxSpec, existingNginxSpec)
+	if canary {
+		promote, err := r.reconcileCanary(ctx, nginx, newDeploy, &currentDeploy)
+		if err != nil || !promote {
+			return err
+		}
+	}
+
+	setLastKnownGood(newDeploy, &currentDeploy)
+
+	// NOTE: replicas field is left unset (thus not owned by the operator)
+	// whenever it's managed by some autoscaler controller e.g HPA from
+	// spec.autoscaling, which scales the Nginx through its scale subresource.
+	if err = r.apply(ctx, newDeploy, &currentDeploy); err != nil {
+		setCondition(nginx, nginxv1beta1.ConditionProgressing, metav1.ConditionFalse, reasonDeploymentFailed, err.Error())
+		return fmt.Errorf("failed to apply Deployment: %w", err)
+	}
+
+	if r.ConfigTest {
+		if err = r.cleanupConfigTests(ctx, nginx, ""); err != nil {
+			return err
+		}
+	}
+
+	if canary {
+		if err = r.deleteCanary(ctx, nginx); err != nil {
+			return err
+		}
+
+		r.EventRecorder.Eventf(nginx, corev1.EventTypeNormal, "CanaryPromoted", "canary Deployment %s promoted", k8s.CanaryName(nginx))
+	}
+
+	clearRolledBack(nginx)
+
+	if len(drift) > 0 {
+		driftCorrectionsTotal.WithLabelValues(nginx.Namespace).Inc()
+		r.EventRecorder.Eventf(nginx, corev1.EventTypeNormal, "DriftCorrected", "Deployment fields manually changed were restored: %s", strings.Join(drift, ", "))
 	}
 
 	return nil
 }
 
-func (r *NginxReconciler) reconcileService(ctx context.Context, nginx *nginxv1alpha1.Nginx) error {
-	newService := k8s.NewService(nginx)
+func sameTemplateAnnotation(a, b *appsv1.Deployment, key string) bool {
+	return a.Spec.Template.Annotations[key] == b.Spec.Template.Annotations[key]
+}
+
+func (r *NginxReconciler) reconcileService(ctx context.Context, nginx *nginxv1beta1.Nginx) error {
+	newServices, err := r.desiredServices(ctx, nginx)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: Services no longer desired (e.g. removed from spec.services) are
+	// deleted by pruneOrphans.
+	for _, newService := range newServices {
+		if err := r.reconcileServiceObject(ctx, nginx, newService); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
+func (r *NginxReconciler) reconcileServiceObject(ctx context.Context, nginx *nginxv1beta1.Nginx, newService *corev1.Service) error {
 	var currentService corev1.Service
 	err := r.Client.Get(ctx, types.NamespacedName{Name: newService.Name, Namespace: newService.Namespace}, &currentService)
 
 	if errors.IsNotFound(err) {
-		err = r.Client.Create(ctx, newService)
+		err = r.apply(ctx, newService, nil)
 		if errors.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota") {
 			r.EventRecorder.Eventf(nginx, corev1.EventTypeWarning, "ServiceQuotaExceeded", "failed to create Service: %s", err)
+			serviceQuotaExceededTotal.WithLabelValues(nginx.Namespace).Inc()
+			setCondition(nginx, nginxv1beta1.ConditionServiceReady, metav1.ConditionFalse, "ServiceQuotaExceeded", err.Error())
 			return err
 		}
 
 		if err != nil {
 			r.EventRecorder.Eventf(nginx, corev1.EventTypeWarning, "ServiceCreationFailed", "failed to create Service: %s", err)
+			setCondition(nginx, nginxv1beta1.ConditionServiceReady, metav1.ConditionFalse, "ServiceCreationFailed", err.Error())
 			return err
 		}
 
@@ -185,23 +447,26 @@ func (r *NginxReconciler) reconcileService(ctx context.Context, nginx *nginxv1al
 	}
 
 	if err != nil {
+		setCondition(nginx, nginxv1beta1.ConditionServiceReady, metav1.ConditionFalse, reasonServiceFailed, err.Error())
 		return fmt.Errorf("failed to retrieve Service resource: %v", err)
 	}
 
+	// NOTE: the names of the Services in spec.services may collide with the
+	// ones of other Nginxes, e.g. "a" with the Service "b-admin" and "a-b"
+	// with the Service "admin". Applying over them would fail on the
+	// controller reference every reconcile.
+	if owner := metav1.GetControllerOf(&currentService); owner != nil && owner.UID != nginx.UID {
+		r.EventRecorder.Eventf(nginx, corev1.EventTypeWarning, "ServiceConflict", "Service %s is controlled by %s %s, leaving it untouched", currentService.Name, owner.Kind, owner.Name)
+		return nil
+	}
+
 	if newService.Annotations[gcpNetworkTierAnnotationKey] != currentService.Annotations[gcpNetworkTierAnnotationKey] {
 		// if you want to change network tier, please ask system administrator to manually change/delete the kubernetes service
 		r.EventRecorder.Event(nginx, corev1.EventTypeWarning, "GCPNetworkTierNoChange", "the GCP network tier of this service cannot be changed, because IP address may change and cause downtime")
-		newService.Annotations[gcpNetworkTierAnnotationKey] = currentService.Annotations[gcpNetworkTierAnnotationKey]
-	}
-
-	newService.ResourceVersion = currentService.ResourceVersion
-	newService.Spec.ClusterIP = currentService.Spec.ClusterIP
-	newService.Spec.HealthCheckNodePort = currentService.Spec.HealthCheckNodePort
-	newService.Finalizers = currentService.Finalizers
-
-	for annotation, value := range currentService.Annotations {
-		if newService.Annotations[annotation] == "" {
-			newService.Annotations[annotation] = value
+		if tier, found := currentService.Annotations[gcpNetworkTierAnnotationKey]; found {
+			newService.Annotations[gcpNetworkTierAnnotationKey] = tier
+		} else {
+			delete(newService.Annotations, gcpNetworkTierAnnotationKey)
 		}
 	}
 
@@ -212,20 +477,12 @@ func (r *NginxReconciler) reconcileService(ctx context.Context, nginx *nginxv1al
 		}
 	}
 
-	if newService.Spec.Type == corev1.ServiceTypeNodePort || newService.Spec.Type == corev1.ServiceTypeLoadBalancer {
-		// avoid nodeport reallocation preserving the current ones
-		for _, currentPort := range currentService.Spec.Ports {
-			for index, newPort := range newService.Spec.Ports {
-				if currentPort.Port == newPort.Port {
-					newService.Spec.Ports[index].NodePort = currentPort.NodePort
-				}
-			}
-		}
-	}
-
-	err = r.Client.Update(ctx, newService)
+	// NOTE: fields allocated by the API server (e.g. cluster IP and node
+	// ports) aren't set, so they're kept as is.
+	err = r.apply(ctx, newService, &currentService)
 	if err != nil {
 		r.EventRecorder.Eventf(nginx, corev1.EventTypeWarning, "ServiceUpdateFailed", "failed to update Service: %s", err)
+		setCondition(nginx, nginxv1beta1.ConditionServiceReady, metav1.ConditionFalse, "ServiceUpdateFailed", err.Error())
 		return err
 	}
 
@@ -233,7 +490,7 @@ func (r *NginxReconciler) reconcileService(ctx context.Context, nginx *nginxv1al
 	return nil
 }
 
-func (r *NginxReconciler) manageIngressLifecycle(ctx context.Context, newIngress *networkingv1.Ingress, nginx *nginxv1alpha1.Nginx) error {
+func (r *NginxReconciler) manageIngressLifecycle(ctx context.Context, newIngress *networkingv1.Ingress, nginx *nginxv1beta1.Nginx) error {
 	var currentIngress networkingv1.Ingress
 	err := r.Client.Get(ctx, types.NamespacedName{Name: newIngress.Name, Namespace: newIngress.Namespace}, &currentIngress)
 	if errors.IsNotFound(err) {
@@ -241,7 +498,7 @@ func (r *NginxReconciler) manageIngressLifecycle(ctx context.Context, newIngress
 			return nil
 		}
 
-		return r.Client.Create(ctx, newIngress)
+		return r.apply(ctx, newIngress, nil)
 	}
 
 	if err != nil {
@@ -252,24 +509,11 @@ func (r *NginxReconciler) manageIngressLifecycle(ctx context.Context, newIngress
 		return r.Client.Delete(ctx, &currentIngress)
 	}
 
-	if !shouldUpdateIngress(&currentIngress, newIngress) {
-		return nil
-	}
-
-	for key, value := range currentIngress.Annotations {
-		if newIngress.Annotations[key] == "" {
-			newIngress.Annotations[key] = value
-		}
-	}
-
-	newIngress.ResourceVersion = currentIngress.ResourceVersion
-	newIngress.Finalizers = currentIngress.Finalizers
-
-	return r.Client.Update(ctx, newIngress)
+	return r.apply(ctx, newIngress, &currentIngress)
 }
 
-func (r *NginxReconciler) manageIpv6IngressLifecycle(ctx context.Context, newIngress *networkingv1.Ingress, nginx *nginxv1alpha1.Nginx) error {
-	newIngress.Name = fmt.Sprintf("%s-ipv6", newIngress.Name)
+func (r *NginxReconciler) manageIpv6IngressLifecycle(ctx context.Context, newIngress *networkingv1.Ingress, nginx *nginxv1beta1.Nginx) error {
+	newIngress.Name = ipv6IngressName(nginx)
 	if newIngress.Annotations == nil {
 		newIngress.Annotations = make(map[string]string)
 	}
@@ -278,13 +522,17 @@ func (r *NginxReconciler) manageIpv6IngressLifecycle(ctx context.Context, newIng
 	err := r.Client.Get(ctx, types.NamespacedName{Name: newIngress.Name, Namespace: newIngress.Namespace}, &currentIngress)
 	if errors.IsNotFound(err) {
 		if shouldDeleteIpv6Ingress(nginx) {
-			return nil
+			return r.releaseIPv6Address(ctx, nginx)
+		}
+
+		if err = r.addFinalizer(ctx, nginx, ipv6AddressFinalizer); err != nil {
+			return err
 		}
 
 		if ensureIpErr := r.GcpClient.EnsureIPV6(ctx, newIngress.Name); ensureIpErr != nil {
 			return ensureIpErr
 		}
-		return r.Client.Create(ctx, newIngress)
+		return r.apply(ctx, newIngress, nil)
 	}
 
 	if err != nil {
@@ -292,27 +540,19 @@ func (r *NginxReconciler) manageIpv6IngressLifecycle(ctx context.Context, newIng
 	}
 
 	if shouldDeleteIpv6Ingress(nginx) {
-		// TODO: Release the static IP
+		// NOTE: the address is released on the next reconciliations, as soon
+		// as the ingress (and its load balancer) is gone.
 		return r.Client.Delete(ctx, &currentIngress)
 	}
 
-	if !shouldUpdateIngress(&currentIngress, newIngress) {
-		return nil
-	}
-
-	for key, value := range currentIngress.Annotations {
-		if newIngress.Annotations[key] == "" {
-			newIngress.Annotations[key] = value
-		}
+	if err = r.addFinalizer(ctx, nginx, ipv6AddressFinalizer); err != nil {
+		return err
 	}
 
-	newIngress.ResourceVersion = currentIngress.ResourceVersion
-	newIngress.Finalizers = currentIngress.Finalizers
-
-	return r.Client.Update(ctx, newIngress)
+	return r.apply(ctx, newIngress, &currentIngress)
 }
 
-func shouldDeleteIpv6Ingress(nginx *nginxv1alpha1.Nginx) bool {
+func shouldDeleteIpv6Ingress(nginx *nginxv1beta1.Nginx) bool {
 	if nginx.Spec.Ingress == nil {
 		return true
 	}
@@ -325,44 +565,144 @@ func shouldDeleteIpv6Ingress(nginx *nginxv1alpha1.Nginx) bool {
 	return false
 }
 
-func (r *NginxReconciler) reconcileIngress(ctx context.Context, nginx *nginxv1alpha1.Nginx) error {
+func (r *NginxReconciler) reconcileIngress(ctx context.Context, nginx *nginxv1beta1.Nginx) error {
 	if nginx == nil {
 		return fmt.Errorf("nginx cannot be nil")
 	}
 	newIngress := k8s.NewIngress(nginx)
-	if err := r.manageIngressLifecycle(ctx, newIngress, nginx); err != nil {
+	if err := observeReconcileStep(stepIngress, r.manageIngressLifecycle(ctx, newIngress, nginx)); err != nil {
+		setCondition(nginx, nginxv1beta1.ConditionIngressReady, metav1.ConditionFalse, reasonIngressFailed, err.Error())
 		return err
 	}
 	newIngress = k8s.NewIngress(nginx)
-	return r.manageIpv6IngressLifecycle(ctx, newIngress, nginx)
+	if err := observeReconcileStep(stepIPv6Ingress, r.manageIpv6IngressLifecycle(ctx, newIngress, nginx)); err != nil {
+		setCondition(nginx, nginxv1beta1.ConditionIngressReady, metav1.ConditionFalse, reasonIngressFailed, err.Error())
+		return err
+	}
+
+	if nginx.Spec.Ingress == nil {
+		setCondition(nginx, nginxv1beta1.ConditionIngressReady, metav1.ConditionTrue, reasonIngressNotRequired, "")
+		return nil
+	}
+
+	setCondition(nginx, nginxv1beta1.ConditionIngressReady, metav1.ConditionTrue, reasonIngressReconciled, "")
+	return nil
+}
+
+func (r *NginxReconciler) reconcileDisruptionBudget(ctx context.Context, nginx *nginxv1beta1.Nginx) error {
+	newPDB := k8s.NewPodDisruptionBudget(nginx)
+
+	var currentPDB policyv1.PodDisruptionBudget
+	err := r.Client.Get(ctx, types.NamespacedName{Name: newPDB.Name, Namespace: newPDB.Namespace}, &currentPDB)
+	if errors.IsNotFound(err) {
+		if nginx.Spec.DisruptionBudget == nil {
+			return nil
+		}
+
+		if err = r.apply(ctx, newPDB, nil); err != nil {
+			return fmt.Errorf("failed to create PodDisruptionBudget resource: %w", err)
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to retrieve PodDisruptionBudget resource: %w", err)
+	}
+
+	if !r.isControlledChild(nginx, &currentPDB, "PodDisruptionBudget") {
+		return nil
+	}
+
+	if nginx.Spec.DisruptionBudget == nil {
+		if err = r.Client.Delete(ctx, &currentPDB); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete PodDisruptionBudget resource: %w", err)
+		}
+
+		return nil
+	}
+
+	if err = r.apply(ctx, newPDB, &currentPDB); err != nil {
+		return fmt.Errorf("failed to update PodDisruptionBudget resource: %w", err)
+	}
+
+	return nil
 }
 
-func shouldUpdateIngress(currentIngress, newIngress *networkingv1.Ingress) bool {
-	if currentIngress == nil || newIngress == nil {
-		return false
+func (r *NginxReconciler) reconcileAutoscaler(ctx context.Context, nginx *nginxv1beta1.Nginx) error {
+	newHPA := k8s.NewHorizontalPodAutoscaler(nginx)
+
+	var currentHPA autoscalingv2.HorizontalPodAutoscaler
+	err := r.Client.Get(ctx, types.NamespacedName{Name: newHPA.Name, Namespace: newHPA.Namespace}, &currentHPA)
+	if errors.IsNotFound(err) {
+		if nginx.Spec.Autoscaling == nil {
+			return nil
+		}
+
+		if err = r.apply(ctx, newHPA, nil); err != nil {
+			return fmt.Errorf("failed to create HorizontalPodAutoscaler resource: %w", err)
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to retrieve HorizontalPodAutoscaler resource: %w", err)
+	}
+
+	if !r.isControlledChild(nginx, &currentHPA, "HorizontalPodAutoscaler") {
+		return nil
 	}
 
-	for key, value := range newIngress.Annotations {
-		if currentIngress.Annotations[key] != value {
-			return true
+	if nginx.Spec.Autoscaling == nil {
+		if err = r.Client.Delete(ctx, &currentHPA); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete HorizontalPodAutoscaler resource: %w", err)
 		}
+
+		return nil
+	}
+
+	// NOTE: fields defaulted by the API server (e.g. the CPU metric when no
+	// metric is set) aren't applied, so they don't make the HPA differ from
+	// the desired one.
+	if err = r.apply(ctx, newHPA, &currentHPA); err != nil {
+		return fmt.Errorf("failed to update HorizontalPodAutoscaler resource: %w", err)
 	}
 
-	return !reflect.DeepEqual(currentIngress.Labels, newIngress.Labels) ||
-		!reflect.DeepEqual(currentIngress.Spec, newIngress.Spec)
+	return nil
 }
 
-func (r *NginxReconciler) refreshStatus(ctx context.Context, nginx *nginxv1alpha1.Nginx) error {
+// isControlledChild reports whether the child found under the name of one of
+// the Nginx's children is controlled by it. Otherwise, it was created by
+// someone else (e.g. by hand, as formerly documented for the autoscaler), so
+// it's neither updated nor deleted, which is warned about.
+func (r *NginxReconciler) isControlledChild(nginx *nginxv1beta1.Nginx, child client.Object, kind string) bool {
+	if metav1.IsControlledBy(child, nginx) {
+		return true
+	}
+
+	r.EventRecorder.Eventf(nginx, corev1.EventTypeWarning, "ResourceNotControlled", "%s %s is not controlled by the Nginx, leaving it untouched", kind, child.GetName())
+	return false
+}
+
+func (r *NginxReconciler) refreshStatus(ctx context.Context, nginx *nginxv1beta1.Nginx, previous nginxv1beta1.NginxStatus) error {
 	deploys, err := listDeployments(ctx, r.Client, nginx)
 	if err != nil {
 		return err
 	}
 
-	var deployStatuses []nginxv1alpha1.DeploymentStatus
+	var activeColor string
+	if isBlueGreenStrategy(nginx) {
+		if activeColor, err = r.activeColor(ctx, nginx); err != nil {
+			return err
+		}
+	}
+
+	var deployStatuses []nginxv1beta1.DeploymentStatus
 	var replicas int32
 	for _, d := range deploys {
 		replicas += d.Status.Replicas
-		deployStatuses = append(deployStatuses, nginxv1alpha1.DeploymentStatus{Name: d.Name})
+		deployStatuses = append(deployStatuses, newDeploymentStatus(nginx, deploys, &d, activeColor))
 	}
 
 	services, err := listServices(ctx, r.Client, nginx)
@@ -375,6 +715,11 @@ func (r *NginxReconciler) refreshStatus(ctx context.Context, nginx *nginxv1alpha
 		return fmt.Errorf("failed to list ingresses for nginx: %w", err)
 	}
 
+	routes, err := listRoutes(ctx, r.Client, nginx)
+	if err != nil {
+		return fmt.Errorf("failed to list HTTPRoutes for nginx: %w", err)
+	}
+
 	sort.Slice(nginx.Status.Services, func(i, j int) bool {
 		return nginx.Status.Services[i].Name < nginx.Status.Services[j].Name
 	})
@@ -383,21 +728,93 @@ func (r *NginxReconciler) refreshStatus(ctx context.Context, nginx *nginxv1alpha
 		return nginx.Status.Ingresses[i].Name < nginx.Status.Ingresses[j].Name
 	})
 
-	status := nginxv1alpha1.NginxStatus{
-		CurrentReplicas: replicas,
-		PodSelector:     k8s.LabelsForNginxString(nginx.Name),
-		Deployments:     deployStatuses,
-		Services:        services,
-		Ingresses:       ingresses,
+	nginx.Status = nginxv1beta1.NginxStatus{
+		ObservedGeneration: nginx.Generation,
+		CurrentReplicas:    replicas,
+		PodSelector:        k8s.LabelsForNginxString(nginx.Name),
+		CurrentRevision:    nginx.Status.CurrentRevision,
+		PreviousRevision:   nginx.Status.PreviousRevision,
+		Deployments:        deployStatuses,
+		Services:           services,
+		Ingresses:          ingresses,
+		Routes:             routes,
+		Conditions:         nginx.Status.Conditions,
+	}
+
+	setDeploymentConditions(nginx, deploys)
+	setServiceCondition(nginx, services)
+	setRouteCondition(nginx, routes)
+	setReadyCondition(nginx)
+
+	return r.updateStatus(ctx, nginx, previous)
+}
+
+// newDeploymentStatus reports the Deployment along with its part on the
+// rollout strategy of the Nginx, e.g. whether it's a canary.
+func newDeploymentStatus(nginx *nginxv1beta1.Nginx, deploys []appsv1.Deployment, d *appsv1.Deployment, activeColor string) nginxv1beta1.DeploymentStatus {
+	status := nginxv1beta1.DeploymentStatus{Name: d.Name}
+	if d.Labels[k8s.TrackLabel] == k8s.TrackCanary {
+		canary, _ := evaluateCanary(d, canaryBakeTime(nginx), time.Now())
+		status.Canary = &canary
 	}
 
-	if reflect.DeepEqual(nginx.Status, status) {
-		return nil
+	status.Color = d.Labels[k8s.ColorLabel]
+	if status.Color == "" {
+		return status
 	}
 
-	nginx.Status = status
+	status.Active = status.Color == activeColor
 
-	err = r.Client.Status().Update(ctx, nginx)
+	// NOTE: only the previous color, which was promoted once, is scaled
+	// down on schedule.
+	if status.Active || replicasOf(d) == 0 || d.Annotations[k8s.PromotedAtAnnotation] == "" {
+		return status
+	}
+
+	for i := range deploys {
+		if deploys[i].Labels[k8s.ColorLabel] == activeColor {
+			status.ScaleDownAt = scaleDownAt(&deploys[i], keepOldTime(nginx))
+		}
+	}
+
+	return status
+}
+
+// requeueAfter returns when the next step of the rollout of the Nginx is
+// due, i.e. promoting its canary or scaling down the previous color.
+func requeueAfter(nginx *nginxv1beta1.Nginx, now time.Time) time.Duration {
+	var after time.Duration
+	for _, d := range nginx.Status.Deployments {
+		at := d.ScaleDownAt
+		if d.Canary != nil && d.Canary.Phase == nginxv1beta1.CanaryPhaseBaking {
+			at = d.Canary.PromoteAt
+		}
+
+		if at == nil {
+			continue
+		}
+
+		next := at.Sub(now)
+		if next <= 0 {
+			next = time.Second
+		}
+
+		if after == 0 || next < after {
+			after = next
+		}
+	}
+
+	return after
+}
+
+// updateStatus writes the Nginx status subresource whenever it differs from
+// the previous one.
+func (r *NginxReconciler) updateStatus(ctx context.Context, nginx *nginxv1beta1.Nginx, previous nginxv1beta1.NginxStatus) error {
+	if reflect.DeepEqual(previous, nginx.Status) {
+		return nil
+	}
+
+	err := r.Client.Status().Update(ctx, nginx)
 	if err != nil {
 		return fmt.Errorf("failed to update nginx status: %v", err)
 	}
@@ -405,7 +822,7 @@ func (r *NginxReconciler) refreshStatus(ctx context.Context, nginx *nginxv1alpha
 	return nil
 }
 
-func listDeployments(ctx context.Context, c client.Client, nginx *nginxv1alpha1.Nginx) ([]appsv1.Deployment, error) {
+func listDeployments(ctx context.Context, c client.Client, nginx *nginxv1beta1.Nginx) ([]appsv1.Deployment, error) {
 	var deployList appsv1.DeploymentList
 
 	err := c.List(ctx, &deployList, &client.ListOptions{
@@ -426,17 +843,11 @@ func listDeployments(ctx context.Context, c client.Client, nginx *nginxv1alpha1.
 			return nil, err
 		}
 
-		desired := *metav1.NewControllerRef(nginx, schema.GroupVersionKind{
-			Group:   nginxv1alpha1.GroupVersion.Group,
-			Version: nginxv1alpha1.GroupVersion.Version,
-			Kind:    "Nginx",
-		})
-
-		for _, deploy := range deployList.Items {
-			for _, owner := range deploy.OwnerReferences {
-				if reflect.DeepEqual(owner, desired) {
-					deploys = append(deploys, deploy)
-				}
+		// NOTE: owner references may point to any served version of the
+		// Nginx (e.g. v1alpha1), so they're matched by UID.
+		for i := range deployList.Items {
+			if metav1.IsControlledBy(&deployList.Items[i], nginx) {
+				deploys = append(deploys, deployList.Items[i])
 			}
 		}
 	}
@@ -447,7 +858,7 @@ func listDeployments(ctx context.Context, c client.Client, nginx *nginxv1alpha1.
 }
 
 // listServices return all the services for the given nginx sorted by name
-func listServices(ctx context.Context, c client.Client, nginx *nginxv1alpha1.Nginx) ([]nginxv1alpha1.ServiceStatus, error) {
+func listServices(ctx context.Context, c client.Client, nginx *nginxv1beta1.Nginx) ([]nginxv1beta1.ServiceStatus, error) {
 	serviceList := &corev1.ServiceList{}
 	labelSelector := labels.SelectorFromSet(k8s.LabelsForNginx(nginx.Name))
 	listOps := &client.ListOptions{Namespace: nginx.Namespace, LabelSelector: labelSelector}
@@ -456,9 +867,9 @@ func listServices(ctx context.Context, c client.Client, nginx *nginxv1alpha1.Ngi
 		return nil, err
 	}
 
-	var services []nginxv1alpha1.ServiceStatus
+	var services []nginxv1beta1.ServiceStatus
 	for _, s := range serviceList.Items {
-		svc := nginxv1alpha1.ServiceStatus{
+		svc := nginxv1beta1.ServiceStatus{
 			Name: s.Name,
 		}
 
@@ -485,7 +896,7 @@ func listServices(ctx context.Context, c client.Client, nginx *nginxv1alpha1.Ngi
 	return services, nil
 }
 
-func listIngresses(ctx context.Context, c client.Client, nginx *nginxv1alpha1.Nginx) ([]nginxv1alpha1.IngressStatus, error) {
+func listIngresses(ctx context.Context, c client.Client, nginx *nginxv1beta1.Nginx) ([]nginxv1beta1.IngressStatus, error) {
 	var ingressList networkingv1.IngressList
 
 	options := &client.ListOptions{
@@ -496,9 +907,9 @@ func listIngresses(ctx context.Context, c client.Client, nginx *nginxv1alpha1.Ng
 		return nil, err
 	}
 
-	var ingresses []nginxv1alpha1.IngressStatus
+	var ingresses []nginxv1beta1.IngressStatus
 	for _, i := range ingressList.Items {
-		ing := nginxv1alpha1.IngressStatus{Name: i.Name}
+		ing := nginxv1beta1.IngressStatus{Name: i.Name}
 
 		for _, ingStatus := range i.Status.LoadBalancer.Ingress {
 			if ingStatus.IP != "" {
@@ -523,7 +934,7 @@ func listIngresses(ctx context.Context, c client.Client, nginx *nginxv1alpha1.Ng
 	return ingresses, nil
 }
 
-func (r *NginxReconciler) shouldManageNginx(nginx *nginxv1alpha1.Nginx) bool {
+func (r *NginxReconciler) shouldManageNginx(nginx *nginxv1beta1.Nginx) bool {
 	// empty filter matches all resources
 	if r.AnnotationFilter == nil || r.AnnotationFilter.Empty() {
 		return true
@@ -532,7 +943,7 @@ func (r *NginxReconciler) shouldManageNginx(nginx *nginxv1alpha1.Nginx) bool {
 	return r.AnnotationFilter.Matches(labels.Set(nginx.Annotations))
 }
 
-func getFirstTLSSortedBySecretName(tls []nginxv1alpha1.NginxTLS) string {
+func getFirstTLSSortedBySecretName(tls []nginxv1beta1.NginxTLS) string {
 	if len(tls) == 0 {
 		return ""
 	}
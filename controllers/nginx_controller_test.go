@@ -14,9 +14,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -29,9 +32,11 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
-	"github.com/tsuru/nginx-operator/api/v1alpha1"
+	"github.com/tsuru/nginx-operator/api/v1beta1"
 	"github.com/tsuru/nginx-operator/pkg/gcp"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
 )
 
 func TestNginxReconciler_reconcileDeployment(t *testing.T) {
@@ -58,19 +63,40 @@ func TestNginxReconciler_reconcileDeployment(t *testing.T) {
 				},
 			},
 		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nginx-2",
+				Namespace: "default",
+				Annotations: map[string]string{
+					"nginx.tsuru.io/generated-from": `{"image":"nginx:stable","podTemplate":{"ports":[{"name":"http","containerPort":8080,"protocol":"TCP"},{"name":"https","containerPort":8443,"protocol":"TCP"}],"toleration":[{"key":"dedicated","operator":"Exists"}]},"resources":{},"cache":{"path":""}}`,
+				},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name:  "nginx",
+								Image: "nginx:stable",
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	tests := map[string]struct {
-		nginx  *v1alpha1.Nginx
+		nginx  *v1beta1.Nginx
 		assert func(t *testing.T, c client.Client)
 	}{
 		"creating first deployment": {
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "nginx-0",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.NginxSpec{
+				Spec: v1beta1.NginxSpec{
 					Replicas: func(n int32) *int32 { return &n }(int32(2)),
 					Image:    "nginx:alpine",
 				},
@@ -83,14 +109,14 @@ func TestNginxReconciler_reconcileDeployment(t *testing.T) {
 				specFromAnnotation := dep.Annotations["nginx.tsuru.io/generated-from"]
 				require.NotEmpty(t, specFromAnnotation)
 
-				var nginxSpec v1alpha1.NginxSpec
+				var nginxSpec v1beta1.NginxSpec
 				err = json.Unmarshal([]byte(specFromAnnotation), &nginxSpec)
 				require.NoError(t, err)
 
-				expected := v1alpha1.NginxSpec{
+				expected := v1beta1.NginxSpec{
 					Image:    "nginx:alpine",
 					Replicas: func(n int32) *int32 { return &n }(int32(2)),
-					PodTemplate: v1alpha1.NginxPodTemplateSpec{
+					PodTemplate: v1beta1.NginxPodTemplateSpec{
 						Ports: []corev1.ContainerPort{
 							{Name: "http", ContainerPort: int32(8080), Protocol: corev1.ProtocolTCP},
 							{Name: "https", ContainerPort: int32(8443), Protocol: corev1.ProtocolTCP},
@@ -102,12 +128,12 @@ func TestNginxReconciler_reconcileDeployment(t *testing.T) {
 		},
 
 		"any update with replicas unset, should not change the replicas number": {
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "nginx-1",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.NginxSpec{
+				Spec: v1beta1.NginxSpec{
 					Image: "nginx:1.22.0",
 				},
 			},
@@ -123,13 +149,13 @@ func TestNginxReconciler_reconcileDeployment(t *testing.T) {
 				specFromAnnotation := dep.Annotations["nginx.tsuru.io/generated-from"]
 				require.NotEmpty(t, specFromAnnotation)
 
-				var nginxSpec v1alpha1.NginxSpec
+				var nginxSpec v1beta1.NginxSpec
 				err = json.Unmarshal([]byte(specFromAnnotation), &nginxSpec)
 				require.NoError(t, err)
 
-				expected := v1alpha1.NginxSpec{
+				expected := v1beta1.NginxSpec{
 					Image: "nginx:1.22.0",
-					PodTemplate: v1alpha1.NginxPodTemplateSpec{
+					PodTemplate: v1beta1.NginxPodTemplateSpec{
 						Ports: []corev1.ContainerPort{
 							{Name: "http", ContainerPort: int32(8080), Protocol: corev1.ProtocolTCP},
 							{Name: "https", ContainerPort: int32(8443), Protocol: corev1.ProtocolTCP},
@@ -139,16 +165,84 @@ func TestNginxReconciler_reconcileDeployment(t *testing.T) {
 				assert.Equal(t, expected, nginxSpec)
 			},
 		},
+
+		"update with autoscaling enabled, should not change the replicas number": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "nginx-1",
+					Namespace: "default",
+				},
+				Spec: v1beta1.NginxSpec{
+					Image:       "nginx:1.22.0",
+					Autoscaling: &v1beta1.NginxAutoscaling{MaxReplicas: 10},
+				},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var dep appsv1.Deployment
+				err := c.Get(context.TODO(), types.NamespacedName{Name: "nginx-1", Namespace: "default"}, &dep)
+				require.NoError(t, err)
+
+				require.NotNil(t, dep.Spec.Replicas)
+				assert.Equal(t, int32(5), *dep.Spec.Replicas)
+				assert.Equal(t, "nginx:1.22.0", dep.Spec.Template.Spec.Containers[0].Image)
+			},
+		},
+
+		"deployment generated from a v1alpha1 spec, should not be rolled out when unchanged": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "nginx-2",
+					Namespace:   "default",
+					Annotations: map[string]string{"nginx.tsuru.io/ignore-drift": "true"},
+				},
+				Spec: v1beta1.NginxSpec{
+					Image: "nginx:stable",
+					PodTemplate: v1beta1.NginxPodTemplateSpec{
+						Tolerations: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+					},
+				},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var dep appsv1.Deployment
+				err := c.Get(context.TODO(), types.NamespacedName{Name: "nginx-2", Namespace: "default"}, &dep)
+				require.NoError(t, err)
+
+				assert.NotContains(t, dep.Annotations, k8s.GeneratedFromVersionAnnotation)
+				assert.Empty(t, dep.Spec.Template.Spec.Containers[0].Command)
+			},
+		},
+
+		"autoscaler scaling the nginx, should change the replicas number": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "nginx-1",
+					Namespace: "default",
+				},
+				Spec: v1beta1.NginxSpec{
+					Replicas:    func(n int32) *int32 { return &n }(int32(8)),
+					Image:       "nginx:stable",
+					Autoscaling: &v1beta1.NginxAutoscaling{MaxReplicas: 10},
+				},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var dep appsv1.Deployment
+				err := c.Get(context.TODO(), types.NamespacedName{Name: "nginx-1", Namespace: "default"}, &dep)
+				require.NoError(t, err)
+
+				require.NotNil(t, dep.Spec.Replicas)
+				assert.Equal(t, int32(8), *dep.Spec.Replicas)
+			},
+		},
 	}
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
 			require.NotNil(t, tt.assert, "you must provide an assert function")
 
-			client := fake.NewClientBuilder().
+			client := withServerSideApply(fake.NewClientBuilder().
 				WithScheme(newScheme()).
 				WithRuntimeObjects(resources...).
-				Build()
+				Build())
 
 			r := &NginxReconciler{
 				Client: client,
@@ -166,24 +260,24 @@ func TestNginxReconciler_reconcileDeployment(t *testing.T) {
 func TestNginxReconciler_reconcileService(t *testing.T) {
 	tests := []struct {
 		name           string
-		nginx          *v1alpha1.Nginx
+		nginx          *v1beta1.Nginx
 		service        *corev1.Service
 		assertion      func(t *testing.T, err error, got *corev1.Service)
 		expectedEvents []string
 	}{
 		{
 			name: "when service doesn't exist yet, should create that and use an auto-allocated nodeport (tcp/0)",
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: "extensions.tsuru.io/v1alpha1",
+					APIVersion: "extensions.tsuru.io/v1beta1",
 					Kind:       "Nginx",
 				},
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "my-nginx",
 					Namespace: "default",
 				},
-				Spec:   v1alpha1.NginxSpec{},
-				Status: v1alpha1.NginxStatus{},
+				Spec:   v1beta1.NginxSpec{},
+				Status: v1beta1.NginxStatus{},
 			},
 			assertion: func(t *testing.T, err error, got *corev1.Service) {
 				assert.NoError(t, err)
@@ -212,22 +306,22 @@ func TestNginxReconciler_reconcileService(t *testing.T) {
 		},
 		{
 			name: "when setup an https port to the service, should use an auto-allocated nodeport on https and preserve its http nodeport",
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: "extensions.tsuru.io/v1alpha1",
+					APIVersion: "extensions.tsuru.io/v1beta1",
 					Kind:       "Nginx",
 				},
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "my-nginx",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.NginxSpec{
-					TLS: []v1alpha1.NginxTLS{{SecretName: "my-secret"}},
-					Service: &v1alpha1.NginxService{
+				Spec: v1beta1.NginxSpec{
+					TLS: []v1beta1.NginxTLS{{SecretName: "my-secret"}},
+					Service: &v1beta1.NginxService{
 						Type: corev1.ServiceTypeNodePort,
 					},
 				},
-				Status: v1alpha1.NginxStatus{},
+				Status: v1beta1.NginxStatus{},
 			},
 			service: &corev1.Service{
 				TypeMeta: metav1.TypeMeta{
@@ -285,17 +379,17 @@ func TestNginxReconciler_reconcileService(t *testing.T) {
 		},
 		{
 			name: "when updating the nginx service field, should update the service resource as well",
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: "extensions.tsuru.io/v1alpha1",
+					APIVersion: "extensions.tsuru.io/v1beta1",
 					Kind:       "Nginx",
 				},
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "my-nginx",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.NginxSpec{
-					Service: &v1alpha1.NginxService{
+				Spec: v1beta1.NginxSpec{
+					Service: &v1beta1.NginxService{
 						Type: corev1.ServiceTypeLoadBalancer,
 						Annotations: map[string]string{
 							"nginx.tsuru.io/new-annotation": "v1",
@@ -304,7 +398,7 @@ func TestNginxReconciler_reconcileService(t *testing.T) {
 							"nginx.tsuru.io/new-label": "v1",
 						},
 					},
-					TLS: []v1alpha1.NginxTLS{{SecretName: "my-secret"}},
+					TLS: []v1beta1.NginxTLS{{SecretName: "my-secret"}},
 				},
 			},
 			service: &corev1.Service{
@@ -370,6 +464,7 @@ func TestNginxReconciler_reconcileService(t *testing.T) {
 					"nginx.tsuru.io/app":           "nginx",
 					"nginx.tsuru.io/resource-name": "my-nginx",
 					"nginx.tsuru.io/new-label":     "v1",
+					"old-service-label":            "v1",
 				}, got.Labels)
 				assert.Equal(t, map[string]string{"nginx.tsuru.io/new-annotation": "v1", "old-service-annotation": "v1"}, got.Annotations)
 			},
@@ -379,20 +474,20 @@ func TestNginxReconciler_reconcileService(t *testing.T) {
 		},
 		{
 			name: "when updating the nginx service type, should discard nodeports when new service is clusterIP",
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: "extensions.tsuru.io/v1alpha1",
+					APIVersion: "extensions.tsuru.io/v1beta1",
 					Kind:       "Nginx",
 				},
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "my-nginx",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.NginxSpec{
-					Service: &v1alpha1.NginxService{
+				Spec: v1beta1.NginxSpec{
+					Service: &v1beta1.NginxService{
 						Type: corev1.ServiceTypeClusterIP,
 					},
-					TLS: []v1alpha1.NginxTLS{{SecretName: "my-secret"}},
+					TLS: []v1beta1.NginxTLS{{SecretName: "my-secret"}},
 				},
 			},
 			service: &corev1.Service{
@@ -435,7 +530,7 @@ func TestNginxReconciler_reconcileService(t *testing.T) {
 				assert.Equal(t, got.Spec.ClusterIP, "10.1.1.10")
 				assert.Equal(t, got.Spec.Type, corev1.ServiceTypeClusterIP)
 				assert.Equal(t, got.Spec.ExternalTrafficPolicy, corev1.ServiceExternalTrafficPolicyType(""))
-				assert.Equal(t, got.Spec.HealthCheckNodePort, int32(43123))
+				assert.Equal(t, got.Spec.HealthCheckNodePort, int32(0))
 				expectedPorts := []corev1.ServicePort{
 					{
 						Name:       "http",
@@ -458,17 +553,17 @@ func TestNginxReconciler_reconcileService(t *testing.T) {
 		},
 		{
 			name: "when updating the nginx service, should maintain other controller's annotations",
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: "extensions.tsuru.io/v1alpha1",
+					APIVersion: "extensions.tsuru.io/v1beta1",
 					Kind:       "Nginx",
 				},
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "my-nginx",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.NginxSpec{
-					Service: &v1alpha1.NginxService{
+				Spec: v1beta1.NginxSpec{
+					Service: &v1beta1.NginxService{
 						Type: corev1.ServiceTypeClusterIP,
 						Annotations: map[string]string{
 							"annotation-from-this-controller": "updated",
@@ -527,17 +622,17 @@ func TestNginxReconciler_reconcileService(t *testing.T) {
 		},
 		{
 			name: "when updating the nginx service, must not change the GCP network-tier annotation",
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: "extensions.tsuru.io/v1alpha1",
+					APIVersion: "extensions.tsuru.io/v1beta1",
 					Kind:       "Nginx",
 				},
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "my-nginx",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.NginxSpec{
-					Service: &v1alpha1.NginxService{
+				Spec: v1beta1.NginxSpec{
+					Service: &v1beta1.NginxService{
 						Type: corev1.ServiceTypeClusterIP,
 						Annotations: map[string]string{
 							"cloud.google.com/network-tier": "Standard",
@@ -593,23 +688,23 @@ func TestNginxReconciler_reconcileService(t *testing.T) {
 		},
 		{
 			name: "when setting OCI load balancer type with TLS annotations, should set the OCI load balancer with proper secret annotation",
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: "extensions.tsuru.io/v1alpha1",
+					APIVersion: "extensions.tsuru.io/v1beta1",
 					Kind:       "Nginx",
 				},
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "my-nginx",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.NginxSpec{
-					Service: &v1alpha1.NginxService{
+				Spec: v1beta1.NginxSpec{
+					Service: &v1beta1.NginxService{
 						Type: corev1.ServiceTypeClusterIP,
 						Annotations: map[string]string{
 							ociLoadBalancerSSLPorts: "443",
 						},
 					},
-					TLS: []v1alpha1.NginxTLS{{SecretName: "my-secret-2"}, {SecretName: "my-secret-1"}},
+					TLS: []v1beta1.NginxTLS{{SecretName: "my-secret-2"}, {SecretName: "my-secret-1"}},
 				},
 			},
 			service: &corev1.Service{
@@ -657,17 +752,17 @@ func TestNginxReconciler_reconcileService(t *testing.T) {
 		},
 		{
 			name: "when setting OCI load balancer type with TLS annotations do nothing if no secret is provided",
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: "extensions.tsuru.io/v1alpha1",
+					APIVersion: "extensions.tsuru.io/v1beta1",
 					Kind:       "Nginx",
 				},
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "my-nginx",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.NginxSpec{
-					Service: &v1alpha1.NginxService{
+				Spec: v1beta1.NginxSpec{
+					Service: &v1beta1.NginxService{
 						Type: corev1.ServiceTypeClusterIP,
 						Annotations: map[string]string{
 							ociLoadBalancerSSLPorts: "443",
@@ -719,22 +814,20 @@ func TestNginxReconciler_reconcileService(t *testing.T) {
 			},
 		},
 		{
-			name: "when using annotation for HTTPS port over HTTP target port",
-			nginx: &v1alpha1.Nginx{
+			name: "when using HTTPS port over HTTP target port",
+			nginx: &v1beta1.Nginx{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: "extensions.tsuru.io/v1alpha1",
+					APIVersion: "extensions.tsuru.io/v1beta1",
 					Kind:       "Nginx",
 				},
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "my-nginx",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.NginxSpec{
-					Service: &v1alpha1.NginxService{
-						Type: corev1.ServiceTypeClusterIP,
-						Annotations: map[string]string{
-							useHTTPSOverHTTPAnnotation: "true",
-						},
+				Spec: v1beta1.NginxSpec{
+					Service: &v1beta1.NginxService{
+						Type:          corev1.ServiceTypeClusterIP,
+						HTTPSOverHTTP: true,
 					},
 				},
 			},
@@ -797,17 +890,17 @@ func TestNginxReconciler_reconcileService(t *testing.T) {
 		},
 		{
 			name: "when updating then nginx service, should keep resource finalizers",
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				TypeMeta: metav1.TypeMeta{
-					APIVersion: "extensions.tsuru.io/v1alpha1",
+					APIVersion: "extensions.tsuru.io/v1beta1",
 					Kind:       "Nginx",
 				},
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "my-nginx",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.NginxSpec{
-					Service: &v1alpha1.NginxService{
+				Spec: v1beta1.NginxSpec{
+					Service: &v1beta1.NginxService{
 						Type: corev1.ServiceTypeClusterIP,
 					},
 				},
@@ -843,10 +936,10 @@ func TestNginxReconciler_reconcileService(t *testing.T) {
 				resources = append(resources, tt.service)
 			}
 
-			client := fake.NewClientBuilder().
+			client := withServerSideApply(fake.NewClientBuilder().
 				WithScheme(newScheme()).
 				WithRuntimeObjects(resources...).
-				Build()
+				Build())
 
 			var wg sync.WaitGroup
 			wg.Add(1)
@@ -880,8 +973,109 @@ func TestNginxReconciler_reconcileService(t *testing.T) {
 	}
 }
 
+func TestNginxReconciler_reconcileService_namedServices(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default", UID: "nginx-uid"},
+		Spec: v1beta1.NginxSpec{
+			Services: []v1beta1.NginxNamedService{
+				{
+					Name: "internal",
+					NginxService: v1beta1.NginxService{
+						Type:                  corev1.ServiceTypeLoadBalancer,
+						Annotations:           map[string]string{"networking.gke.io/load-balancer-type": "Internal"},
+						ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+					},
+				},
+				{
+					Name: "admin",
+					NginxService: v1beta1.NginxService{
+						Ports: []corev1.ServicePort{{Name: "admin", Port: 9000, TargetPort: intstr.FromInt(9000)}},
+					},
+				},
+				{Name: "shared"},
+			},
+		},
+	}
+
+	other := &v1beta1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: "my", Namespace: "default", UID: "other-uid"}}
+
+	ownerRef := metav1.NewControllerRef(nginx, v1beta1.GroupVersion.WithKind("Nginx"))
+	resources := []runtime.Object{
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "my-nginx-shared",
+				Namespace:       "default",
+				Labels:          k8s.LabelsForNginx("my"),
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(other, v1beta1.GroupVersion.WithKind("Nginx"))},
+			},
+			Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "my-nginx-public",
+				Namespace:       "default",
+				Labels:          map[string]string{"nginx.tsuru.io/app": "nginx", "nginx.tsuru.io/resource-name": "my-nginx"},
+				OwnerReferences: []metav1.OwnerReference{*ownerRef},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-nginx-not-owned",
+				Namespace: "default",
+				Labels:    map[string]string{"nginx.tsuru.io/app": "nginx", "nginx.tsuru.io/resource-name": "my-nginx"},
+			},
+		},
+	}
+
+	client := withServerSideApply(fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		WithRuntimeObjects(resources...).
+		Build())
+
+	er := record.NewFakeRecorder(10)
+	r := &NginxReconciler{
+		Client:        client,
+		EventRecorder: er,
+		Log:           ctrl.Log.WithName("test"),
+	}
+
+	require.NoError(t, r.reconcileService(context.TODO(), nginx))
+	require.NoError(t, r.pruneOrphans(context.TODO(), nginx))
+	close(er.Events)
+
+	var events []string
+	for event := range er.Events {
+		events = append(events, event)
+	}
+	assert.Equal(t, []string{
+		"Normal ServiceCreated service created successfully",
+		"Normal ServiceCreated service created successfully",
+		"Normal ServiceCreated service created successfully",
+		"Warning ServiceConflict Service my-nginx-shared is controlled by Nginx my, leaving it untouched",
+		"Normal OrphanDeleted orphaned Service my-nginx-public deleted successfully",
+	}, events)
+
+	var services corev1.ServiceList
+	require.NoError(t, client.List(context.TODO(), &services))
+	got := make(map[string]corev1.ServiceSpec)
+	for _, svc := range services.Items {
+		got[svc.Name] = svc.Spec
+	}
+	require.Len(t, got, 5)
+	assert.Equal(t, corev1.ServiceTypeNodePort, got["my-nginx-shared"].Type)
+	assert.Contains(t, got, "my-nginx-service")
+	assert.Contains(t, got, "my-nginx-not-owned")
+	assert.Equal(t, corev1.ServiceTypeLoadBalancer, got["my-nginx-internal"].Type)
+	assert.Equal(t, corev1.ServiceExternalTrafficPolicyTypeLocal, got["my-nginx-internal"].ExternalTrafficPolicy)
+	assert.Equal(t, []corev1.ServicePort{{Name: "admin", Port: 9000, TargetPort: intstr.FromInt(9000)}}, got["my-nginx-admin"].Ports)
+}
+
 func TestNginxReconciler_reconcileIngress(t *testing.T) {
 	resources := []runtime.Object{
+		&v1beta1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-1", Namespace: "default"}},
+		&v1beta1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-2", Namespace: "default"}},
+		&v1beta1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-3", Namespace: "default"}},
+		&v1beta1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-with-finalizer", Namespace: "default"}},
 		&networkingv1.Ingress{
 			TypeMeta: metav1.TypeMeta{
 				APIVersion: "networking.k8s.io/v1",
@@ -991,9 +1185,9 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 	}
 
 	tests := map[string]struct {
-		nginx         *v1alpha1.Nginx
+		nginx         *v1beta1.Nginx
 		gcpClient     *gcp.MockGcpClient
-		assert        func(t *testing.T, c client.Client, nginx *v1alpha1.Nginx, gcpClient *gcp.MockGcpClient)
+		assert        func(t *testing.T, c client.Client, nginx *v1beta1.Nginx, gcpClient *gcp.MockGcpClient)
 		expectedError string
 	}{
 		"when nginx is nil, should return expected error": {
@@ -1001,19 +1195,19 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 		},
 
 		"when ingress does not exist, should create one": {
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:            "my-nginx-2",
 					Namespace:       "default",
 					ResourceVersion: "666",
 				},
-				Spec: v1alpha1.NginxSpec{
-					Ingress: &v1alpha1.NginxIngress{
+				Spec: v1beta1.NginxSpec{
+					Ingress: &v1beta1.NginxIngress{
 						IngressClassName: func(s string) *string { return &s }("custom-class"),
 					},
 				},
 			},
-			assert: func(t *testing.T, c client.Client, nginx *v1alpha1.Nginx, gcpClient *gcp.MockGcpClient) {
+			assert: func(t *testing.T, c client.Client, nginx *v1beta1.Nginx, gcpClient *gcp.MockGcpClient) {
 				var got networkingv1.Ingress
 				err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-2", Namespace: "default"}, &got)
 				require.NoError(t, err)
@@ -1033,8 +1227,8 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 						},
 						OwnerReferences: []metav1.OwnerReference{
 							*metav1.NewControllerRef(nginx, schema.GroupVersionKind{
-								Group:   v1alpha1.GroupVersion.Group,
-								Version: v1alpha1.GroupVersion.Version,
+								Group:   v1beta1.GroupVersion.Group,
+								Version: v1beta1.GroupVersion.Version,
 								Kind:    "Nginx",
 							}),
 						},
@@ -1053,14 +1247,14 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 		},
 
 		"when ipv6 ingress does not exist, should create one when annotation is true": {
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:            "my-nginx-3",
 					Namespace:       "default",
 					ResourceVersion: "666",
 				},
-				Spec: v1alpha1.NginxSpec{
-					Ingress: &v1alpha1.NginxIngress{
+				Spec: v1beta1.NginxSpec{
+					Ingress: &v1beta1.NginxIngress{
 						Annotations: map[string]string{
 							nginxIpv6GcpAnnotation: "true",
 						},
@@ -1071,7 +1265,13 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 			gcpClient: &gcp.MockGcpClient{
 				Adresses: []string{},
 			},
-			assert: func(t *testing.T, c client.Client, nginx *v1alpha1.Nginx, gcpClient *gcp.MockGcpClient) {
+			assert: func(t *testing.T, c client.Client, nginx *v1beta1.Nginx, gcpClient *gcp.MockGcpClient) {
+				assert.Equal(t, []string{"my-nginx-3-ipv6"}, gcpClient.Adresses)
+
+				var gotNginx v1beta1.Nginx
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-3", Namespace: "default"}, &gotNginx))
+				assert.Equal(t, []string{"nginx.tsuru.io/gcp-ipv6-address"}, gotNginx.Finalizers)
+
 				var got networkingv1.Ingress
 				err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-3", Namespace: "default"}, &got)
 				require.NoError(t, err)
@@ -1094,8 +1294,8 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 						},
 						OwnerReferences: []metav1.OwnerReference{
 							*metav1.NewControllerRef(nginx, schema.GroupVersionKind{
-								Group:   v1alpha1.GroupVersion.Group,
-								Version: v1alpha1.GroupVersion.Version,
+								Group:   v1beta1.GroupVersion.Group,
+								Version: v1beta1.GroupVersion.Version,
 								Kind:    "Nginx",
 							}),
 						},
@@ -1133,8 +1333,8 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 						},
 						OwnerReferences: []metav1.OwnerReference{
 							*metav1.NewControllerRef(nginx, schema.GroupVersionKind{
-								Group:   v1alpha1.GroupVersion.Group,
-								Version: v1alpha1.GroupVersion.Version,
+								Group:   v1beta1.GroupVersion.Group,
+								Version: v1beta1.GroupVersion.Version,
 								Kind:    "Nginx",
 							}),
 						},
@@ -1153,10 +1353,10 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 		},
 
 		"when nginx without finalizers removes ingress field, should remove the ingress resource": {
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-1", Namespace: "default"},
 			},
-			assert: func(t *testing.T, c client.Client, nginx *v1alpha1.Nginx, gcpClient *gcp.MockGcpClient) {
+			assert: func(t *testing.T, c client.Client, nginx *v1beta1.Nginx, gcpClient *gcp.MockGcpClient) {
 				var got networkingv1.Ingress
 				err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-1", Namespace: "default"}, &got)
 				assert.Error(t, err)
@@ -1169,10 +1369,10 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 		},
 
 		"when nginx with finalizers removes ingress field, should mark the ingress resource for deletion": {
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-with-finalizer", Namespace: "default"},
 			},
-			assert: func(t *testing.T, c client.Client, nginx *v1alpha1.Nginx, gcpClient *gcp.MockGcpClient) {
+			assert: func(t *testing.T, c client.Client, nginx *v1beta1.Nginx, gcpClient *gcp.MockGcpClient) {
 				var got networkingv1.Ingress
 				err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-with-finalizer", Namespace: "default"}, &got)
 				assert.NoError(t, err)
@@ -1185,19 +1385,19 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 		},
 
 		"when ingress already exists, updating the ingress field in nginx should update the target ingress resource": {
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "my-nginx-1",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.NginxSpec{
-					Ingress: &v1alpha1.NginxIngress{
+				Spec: v1beta1.NginxSpec{
+					Ingress: &v1beta1.NginxIngress{
 						Annotations: map[string]string{"custom.nginx.tsuru.io/foo": "bar", nginxIpv6GcpAnnotation: "true"},
 						Labels:      map[string]string{"custom.nginx.tsuru.io": "key1"},
 					},
 				},
 			},
-			assert: func(t *testing.T, c client.Client, nginx *v1alpha1.Nginx, gcpClient *gcp.MockGcpClient) {
+			assert: func(t *testing.T, c client.Client, nginx *v1beta1.Nginx, gcpClient *gcp.MockGcpClient) {
 				var got networkingv1.Ingress
 				err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-1", Namespace: "default"}, &got)
 				require.NoError(t, err)
@@ -1247,19 +1447,19 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 		},
 
 		"when ingress already exists, removing the nginxIpv6GcpAnnotation should delete ipv6 ingress": {
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "my-nginx-1",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.NginxSpec{
-					Ingress: &v1alpha1.NginxIngress{
+				Spec: v1beta1.NginxSpec{
+					Ingress: &v1beta1.NginxIngress{
 						Annotations: map[string]string{"custom.nginx.tsuru.io/foo": "bar"},
 						Labels:      map[string]string{"custom.nginx.tsuru.io": "key1"},
 					},
 				},
 			},
-			assert: func(t *testing.T, c client.Client, nginx *v1alpha1.Nginx, gcpClient *gcp.MockGcpClient) {
+			assert: func(t *testing.T, c client.Client, nginx *v1beta1.Nginx, gcpClient *gcp.MockGcpClient) {
 				var got networkingv1.Ingress
 				err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-1", Namespace: "default"}, &got)
 				require.NoError(t, err)
@@ -1289,19 +1489,19 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 		},
 
 		"when ingress is updated, should keep resource finalizers": {
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "my-nginx-with-finalizer",
 					Namespace: "default",
 				},
-				Spec: v1alpha1.NginxSpec{
-					Ingress: &v1alpha1.NginxIngress{
+				Spec: v1beta1.NginxSpec{
+					Ingress: &v1beta1.NginxIngress{
 						Annotations: map[string]string{"custom.nginx.tsuru.io/foo": "bar", nginxIpv6GcpAnnotation: "true"},
 						Labels:      map[string]string{"custom.nginx.tsuru.io": "key1"},
 					},
 				},
 			},
-			assert: func(t *testing.T, c client.Client, nginx *v1alpha1.Nginx, gcpClient *gcp.MockGcpClient) {
+			assert: func(t *testing.T, c client.Client, nginx *v1beta1.Nginx, gcpClient *gcp.MockGcpClient) {
 				var got networkingv1.Ingress
 				err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-with-finalizer", Namespace: "default"}, &got)
 				require.NoError(t, err)
@@ -1316,19 +1516,19 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 		},
 
 		"when TLS is set, should not set the default backend": {
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:            "my-nginx-2",
 					Namespace:       "default",
 					ResourceVersion: "666",
 				},
-				Spec: v1alpha1.NginxSpec{
-					Ingress: &v1alpha1.NginxIngress{
+				Spec: v1beta1.NginxSpec{
+					Ingress: &v1beta1.NginxIngress{
 						Annotations: map[string]string{
 							nginxIpv6GcpAnnotation: "true",
 						},
 					},
-					TLS: []v1alpha1.NginxTLS{
+					TLS: []v1beta1.NginxTLS{
 						{SecretName: "example-com-certs", Hosts: []string{"www.example.com"}},
 					},
 				},
@@ -1336,7 +1536,7 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 			gcpClient: &gcp.MockGcpClient{
 				Adresses: []string{},
 			},
-			assert: func(t *testing.T, c client.Client, nginx *v1alpha1.Nginx, gcpClient *gcp.MockGcpClient) {
+			assert: func(t *testing.T, c client.Client, nginx *v1beta1.Nginx, gcpClient *gcp.MockGcpClient) {
 				var got networkingv1.Ingress
 				err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-2", Namespace: "default"}, &got)
 				require.NoError(t, err)
@@ -1359,8 +1559,8 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 						},
 						OwnerReferences: []metav1.OwnerReference{
 							*metav1.NewControllerRef(nginx, schema.GroupVersionKind{
-								Group:   v1alpha1.GroupVersion.Group,
-								Version: v1alpha1.GroupVersion.Version,
+								Group:   v1beta1.GroupVersion.Group,
+								Version: v1beta1.GroupVersion.Version,
 								Kind:    "Nginx",
 							}),
 						},
@@ -1423,8 +1623,8 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 						},
 						OwnerReferences: []metav1.OwnerReference{
 							*metav1.NewControllerRef(nginx, schema.GroupVersionKind{
-								Group:   v1alpha1.GroupVersion.Group,
-								Version: v1alpha1.GroupVersion.Version,
+								Group:   v1beta1.GroupVersion.Group,
+								Version: v1beta1.GroupVersion.Version,
 								Kind:    "Nginx",
 							}),
 						},
@@ -1468,12 +1668,27 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 		},
 	}
 
+	// NOTE: the ingresses of my-nginx-1 were applied by the operator, except
+	// for the annotations which were set by the ingress controller.
+	var applied []client.Object
+	for _, obj := range resources[4:6] {
+		ing := obj.(*networkingv1.Ingress).DeepCopy()
+		ing.Annotations = nil
+		applied = append(applied, ing)
+	}
+
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			client := fake.NewClientBuilder().
+			client := withServerSideApply(fake.NewClientBuilder().
 				WithScheme(newScheme()).
 				WithRuntimeObjects(resources...).
-				Build()
+				Build(), applied...)
+
+			// NOTE: the finalizers are patched with optimistic locking.
+			var stored v1beta1.Nginx
+			if tt.nginx != nil && client.Get(context.TODO(), types.NamespacedName{Name: tt.nginx.Name, Namespace: tt.nginx.Namespace}, &stored) == nil {
+				tt.nginx.ResourceVersion = stored.ResourceVersion
+			}
 
 			r := &NginxReconciler{Client: client, GcpClient: tt.gcpClient}
 			err := r.reconcileIngress(context.TODO(), tt.nginx)
@@ -1490,8 +1705,309 @@ func TestNginxReconciler_reconcileIngress(t *testing.T) {
 	}
 }
 
+func TestNginxReconciler_reconcileDisruptionBudget(t *testing.T) {
+	existing := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-1", Namespace: "default"},
+		Spec: v1beta1.NginxSpec{
+			DisruptionBudget: &v1beta1.NginxDisruptionBudget{MaxUnavailable: ptr.To(intstr.FromInt(1))},
+		},
+	}
+
+	applied := k8s.NewPodDisruptionBudget(existing)
+
+	pdb := applied.DeepCopy()
+	pdb.Annotations = map[string]string{"custom.example.com/key": "value"}
+
+	manual := k8s.NewPodDisruptionBudget(&v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-3", Namespace: "default"},
+		Spec: v1beta1.NginxSpec{
+			DisruptionBudget: &v1beta1.NginxDisruptionBudget{MaxUnavailable: ptr.To(intstr.FromInt(1))},
+		},
+	})
+	manual.OwnerReferences = nil
+
+	resources := []runtime.Object{pdb, manual}
+
+	tests := map[string]struct {
+		nginx          *v1beta1.Nginx
+		assert         func(t *testing.T, c client.Client)
+		expectedEvents []string
+	}{
+		"without disruption budget": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-2", Namespace: "default"},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var got policyv1.PodDisruptionBudget
+				err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-2", Namespace: "default"}, &got)
+				assert.True(t, errors.IsNotFound(err))
+			},
+		},
+
+		"creating disruption budget": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-2", Namespace: "default"},
+				Spec: v1beta1.NginxSpec{
+					DisruptionBudget: &v1beta1.NginxDisruptionBudget{MinAvailable: ptr.To(intstr.FromString("50%"))},
+				},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var got policyv1.PodDisruptionBudget
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-2", Namespace: "default"}, &got))
+				assert.Equal(t, ptr.To(intstr.FromString("50%")), got.Spec.MinAvailable)
+				assert.Nil(t, got.Spec.MaxUnavailable)
+				assert.Equal(t, &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"nginx.tsuru.io/app":           "nginx",
+						"nginx.tsuru.io/resource-name": "my-nginx-2",
+					},
+				}, got.Spec.Selector)
+				require.Len(t, got.OwnerReferences, 1)
+				assert.Equal(t, "my-nginx-2", got.OwnerReferences[0].Name)
+			},
+		},
+
+		"updating disruption budget": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-1", Namespace: "default"},
+				Spec: v1beta1.NginxSpec{
+					DisruptionBudget: &v1beta1.NginxDisruptionBudget{MinAvailable: ptr.To(intstr.FromInt(2))},
+				},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var got policyv1.PodDisruptionBudget
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-1", Namespace: "default"}, &got))
+				assert.Equal(t, ptr.To(intstr.FromInt(2)), got.Spec.MinAvailable)
+				assert.Nil(t, got.Spec.MaxUnavailable)
+				assert.Equal(t, map[string]string{"custom.example.com/key": "value"}, got.Annotations)
+			},
+		},
+
+		"removing disruption budget": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-1", Namespace: "default"},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var got policyv1.PodDisruptionBudget
+				err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-1", Namespace: "default"}, &got)
+				assert.True(t, errors.IsNotFound(err))
+			},
+		},
+
+		"unowned disruption budget, should be left untouched": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-3", Namespace: "default", UID: "nginx-3-uid"},
+				Spec: v1beta1.NginxSpec{
+					DisruptionBudget: &v1beta1.NginxDisruptionBudget{MinAvailable: ptr.To(intstr.FromInt(2))},
+				},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var got policyv1.PodDisruptionBudget
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-3", Namespace: "default"}, &got))
+				assert.Equal(t, manual.Spec, got.Spec)
+				assert.Empty(t, got.OwnerReferences)
+			},
+			expectedEvents: []string{
+				"Warning ResourceNotControlled PodDisruptionBudget my-nginx-3 is not controlled by the Nginx, leaving it untouched",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := withServerSideApply(fake.NewClientBuilder().
+				WithScheme(newScheme()).
+				WithRuntimeObjects(resources...).
+				Build(), applied)
+
+			er := record.NewFakeRecorder(10)
+			r := &NginxReconciler{Client: client, EventRecorder: er}
+			require.NoError(t, r.reconcileDisruptionBudget(context.TODO(), tt.nginx))
+			tt.assert(t, client)
+
+			close(er.Events)
+			var events []string
+			for event := range er.Events {
+				events = append(events, event)
+			}
+			assert.Equal(t, tt.expectedEvents, events)
+		})
+	}
+}
+
+func TestNginxReconciler_reconcileAutoscaler(t *testing.T) {
+	existing := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-1", Namespace: "default"},
+		Spec: v1beta1.NginxSpec{
+			Autoscaling: &v1beta1.NginxAutoscaling{MaxReplicas: 5},
+		},
+	}
+
+	applied := k8s.NewHorizontalPodAutoscaler(existing)
+
+	// NOTE: the API server defaults the CPU metric when no metric is set.
+	hpa := applied.DeepCopy()
+	hpa.Annotations = map[string]string{"custom.example.com/key": "value"}
+	hpa.Spec.Metrics = []autoscalingv2.MetricSpec{
+		{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name:   corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: ptr.To(int32(80))},
+			},
+		},
+	}
+
+	// NOTE: formerly, users were told to create the HPA by hand.
+	manual := k8s.NewHorizontalPodAutoscaler(&v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-3", Namespace: "default"},
+		Spec:       v1beta1.NginxSpec{Autoscaling: &v1beta1.NginxAutoscaling{MaxReplicas: 3}},
+	})
+	manual.OwnerReferences = nil
+
+	resources := []runtime.Object{hpa, manual}
+
+	tests := map[string]struct {
+		nginx          *v1beta1.Nginx
+		assert         func(t *testing.T, c client.Client)
+		expectedEvents []string
+	}{
+		"without autoscaling": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-2", Namespace: "default"},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var got autoscalingv2.HorizontalPodAutoscaler
+				err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-2", Namespace: "default"}, &got)
+				assert.True(t, errors.IsNotFound(err))
+			},
+		},
+
+		"creating autoscaler": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-2", Namespace: "default"},
+				Spec: v1beta1.NginxSpec{
+					Autoscaling: &v1beta1.NginxAutoscaling{
+						MinReplicas:                    ptr.To(int32(2)),
+						MaxReplicas:                    10,
+						TargetCPUUtilizationPercentage: ptr.To(int32(85)),
+					},
+				},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var got autoscalingv2.HorizontalPodAutoscaler
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-2", Namespace: "default"}, &got))
+				assert.Equal(t, autoscalingv2.CrossVersionObjectReference{
+					APIVersion: "nginx.tsuru.io/v1beta1",
+					Kind:       "Nginx",
+					Name:       "my-nginx-2",
+				}, got.Spec.ScaleTargetRef)
+				assert.Equal(t, ptr.To(int32(2)), got.Spec.MinReplicas)
+				assert.Equal(t, int32(10), got.Spec.MaxReplicas)
+				require.Len(t, got.Spec.Metrics, 1)
+				assert.Equal(t, corev1.ResourceCPU, got.Spec.Metrics[0].Resource.Name)
+				require.Len(t, got.OwnerReferences, 1)
+				assert.Equal(t, "my-nginx-2", got.OwnerReferences[0].Name)
+			},
+		},
+
+		"updating autoscaler": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-1", Namespace: "default"},
+				Spec: v1beta1.NginxSpec{
+					Autoscaling: &v1beta1.NginxAutoscaling{
+						MaxReplicas:                       20,
+						TargetMemoryUtilizationPercentage: ptr.To(int32(70)),
+					},
+				},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var got autoscalingv2.HorizontalPodAutoscaler
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-1", Namespace: "default"}, &got))
+				assert.Equal(t, int32(20), got.Spec.MaxReplicas)
+				require.Len(t, got.Spec.Metrics, 1)
+				assert.Equal(t, corev1.ResourceMemory, got.Spec.Metrics[0].Resource.Name)
+				assert.Equal(t, map[string]string{"custom.example.com/key": "value"}, got.Annotations)
+			},
+		},
+
+		"unchanged autoscaler, should keep the defaulted metric": {
+			nginx: existing,
+			assert: func(t *testing.T, c client.Client) {
+				var got autoscalingv2.HorizontalPodAutoscaler
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-1", Namespace: "default"}, &got))
+				assert.Equal(t, hpa.Spec, got.Spec)
+				assert.Equal(t, map[string]string{"custom.example.com/key": "value"}, got.Annotations)
+			},
+		},
+
+		"removing autoscaler": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-1", Namespace: "default"},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var got autoscalingv2.HorizontalPodAutoscaler
+				err := c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-1", Namespace: "default"}, &got)
+				assert.True(t, errors.IsNotFound(err))
+			},
+		},
+
+		"unowned autoscaler without autoscaling, should be left untouched": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-3", Namespace: "default", UID: "nginx-3-uid"},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var got autoscalingv2.HorizontalPodAutoscaler
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-3", Namespace: "default"}, &got))
+				assert.Equal(t, manual.Spec, got.Spec)
+				assert.Empty(t, got.OwnerReferences)
+			},
+			expectedEvents: []string{
+				"Warning ResourceNotControlled HorizontalPodAutoscaler my-nginx-3 is not controlled by the Nginx, leaving it untouched",
+			},
+		},
+
+		"unowned autoscaler with autoscaling, should be left untouched": {
+			nginx: &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-3", Namespace: "default", UID: "nginx-3-uid"},
+				Spec:       v1beta1.NginxSpec{Autoscaling: &v1beta1.NginxAutoscaling{MaxReplicas: 10}},
+			},
+			assert: func(t *testing.T, c client.Client) {
+				var got autoscalingv2.HorizontalPodAutoscaler
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx-3", Namespace: "default"}, &got))
+				assert.Equal(t, manual.Spec, got.Spec)
+				assert.Empty(t, got.OwnerReferences)
+			},
+			expectedEvents: []string{
+				"Warning ResourceNotControlled HorizontalPodAutoscaler my-nginx-3 is not controlled by the Nginx, leaving it untouched",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := withServerSideApply(fake.NewClientBuilder().
+				WithScheme(newScheme()).
+				WithRuntimeObjects(resources...).
+				Build(), applied)
+
+			er := record.NewFakeRecorder(10)
+			r := &NginxReconciler{Client: client, EventRecorder: er}
+			require.NoError(t, r.reconcileAutoscaler(context.TODO(), tt.nginx))
+			tt.assert(t, client)
+
+			close(er.Events)
+			var events []string
+			for event := range er.Events {
+				events = append(events, event)
+			}
+			assert.Equal(t, tt.expectedEvents, events)
+		})
+	}
+}
+
 func TestNginxReconciler_reconcileStatus(t *testing.T) {
-	nginx := v1alpha1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"}}
+	nginx := v1beta1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"}}
 
 	resources := []runtime.Object{
 		&appsv1.Deployment{
@@ -1536,35 +2052,257 @@ func TestNginxReconciler_reconcileStatus(t *testing.T) {
 		Build()
 
 	r := &NginxReconciler{Client: client}
-	assert.NoError(t, r.refreshStatus(context.TODO(), &nginx))
+	assert.NoError(t, r.refreshStatus(context.TODO(), &nginx, nginx.Status))
 
-	var got v1alpha1.Nginx
+	var got v1beta1.Nginx
 	err := client.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &got)
 	require.NoError(t, err)
-	assert.Equal(t, v1alpha1.NginxStatus{
+
+	conditions := got.Status.Conditions
+	got.Status.Conditions = nil
+	assert.Equal(t, v1beta1.NginxStatus{
 		CurrentReplicas: int32(3),
 		PodSelector:     "nginx.tsuru.io/app=nginx,nginx.tsuru.io/resource-name=my-nginx",
-		Deployments:     []v1alpha1.DeploymentStatus{{Name: "my-nginx"}},
-		Services:        []v1alpha1.ServiceStatus{{Name: "my-nginx-service"}},
-		Ingresses:       []v1alpha1.IngressStatus{{Name: "my-nginx"}},
+		Deployments:     []v1beta1.DeploymentStatus{{Name: "my-nginx"}},
+		Services:        []v1beta1.ServiceStatus{{Name: "my-nginx-service"}},
+		Ingresses:       []v1beta1.IngressStatus{{Name: "my-nginx"}},
 	}, got.Status)
+
+	assert.Equal(t, map[string]string{
+		v1beta1.ConditionDeploymentAvailable: "False/MinimumReplicasUnavailable",
+		v1beta1.ConditionProgressing:         "True/RolloutInProgress",
+		v1beta1.ConditionServiceReady:        "True/ServiceReconciled",
+		v1beta1.ConditionRouteAccepted:       "True/RouteNotRequired",
+		v1beta1.ConditionReady:               "False/NotReady",
+	}, summarizeConditions(conditions))
+}
+
+func TestListDeployments(t *testing.T) {
+	nginx := &v1beta1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default", UID: "nginx-uid"}}
+
+	controlledBy := func(apiVersion string, uid types.UID) []metav1.OwnerReference {
+		return []metav1.OwnerReference{
+			{APIVersion: apiVersion, Kind: "Nginx", Name: "my-nginx", UID: uid, Controller: func(b bool) *bool { return &b }(true)},
+		}
+	}
+
+	tests := map[string]struct {
+		deploy   *appsv1.Deployment
+		expected []string
+	}{
+		"with Nginx labels": {
+			deploy: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default", Labels: k8s.LabelsForNginx("my-nginx")},
+			},
+			expected: []string{"my-nginx"},
+		},
+		"without labels, controlled by the v1beta1 Nginx": {
+			deploy: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default", OwnerReferences: controlledBy("nginx.tsuru.io/v1beta1", "nginx-uid")},
+			},
+			expected: []string{"my-nginx"},
+		},
+		"without labels, controlled by the v1alpha1 Nginx": {
+			deploy: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default", OwnerReferences: controlledBy("nginx.tsuru.io/v1alpha1", "nginx-uid")},
+			},
+			expected: []string{"my-nginx"},
+		},
+		"without labels, controlled by another Nginx": {
+			deploy: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default", OwnerReferences: controlledBy("nginx.tsuru.io/v1alpha1", "another-uid")},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := fake.NewClientBuilder().
+				WithScheme(newScheme()).
+				WithRuntimeObjects(tt.deploy).
+				Build()
+
+			deploys, err := listDeployments(context.TODO(), c, nginx)
+			require.NoError(t, err)
+
+			var names []string
+			for _, d := range deploys {
+				names = append(names, d.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestNginxReconciler_Reconcile_conditions(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default", Generation: 3},
+		Spec: v1beta1.NginxSpec{
+			Ingress: &v1beta1.NginxIngress{},
+		},
+	}
+
+	client := withServerSideApply(fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		WithRuntimeObjects(nginx).
+		Build())
+
+	r := &NginxReconciler{
+		Client:        client,
+		EventRecorder: record.NewFakeRecorder(10),
+		Log:           ctrl.Log.WithName("test"),
+	}
+
+	_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "my-nginx", Namespace: "default"}})
+	require.NoError(t, err)
+
+	var got v1beta1.Nginx
+	require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &got))
+	assert.Equal(t, int64(3), got.Status.ObservedGeneration)
+	assert.Equal(t, map[string]string{
+		v1beta1.ConditionConfigValid:         "True/ValidConfig",
+		v1beta1.ConditionDeploymentAvailable: "False/MinimumReplicasUnavailable",
+		v1beta1.ConditionProgressing:         "True/RolloutInProgress",
+		v1beta1.ConditionServiceReady:        "True/ServiceReconciled",
+		v1beta1.ConditionIngressReady:        "True/IngressReconciled",
+		v1beta1.ConditionRouteAccepted:       "True/RouteNotRequired",
+		v1beta1.ConditionCertificatesReady:   "True/CertificatesNotRequired",
+		v1beta1.ConditionReady:               "False/NotReady",
+	}, summarizeConditions(got.Status.Conditions))
+
+	for _, c := range got.Status.Conditions {
+		assert.Equal(t, int64(3), c.ObservedGeneration)
+	}
+
+	var deploy appsv1.Deployment
+	require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &deploy))
+	deploy.Generation = 1
+	deploy.Status = appsv1.DeploymentStatus{
+		ObservedGeneration: 1,
+		Replicas:           1,
+		UpdatedReplicas:    1,
+		AvailableReplicas:  1,
+		Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+		},
+	}
+	require.NoError(t, client.Update(context.TODO(), &deploy))
+
+	_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "my-nginx", Namespace: "default"}})
+	require.NoError(t, err)
+
+	require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &got))
+	assert.Equal(t, map[string]string{
+		v1beta1.ConditionConfigValid:         "True/ValidConfig",
+		v1beta1.ConditionDeploymentAvailable: "True/MinimumReplicasAvailable",
+		v1beta1.ConditionProgressing:         "False/RolloutComplete",
+		v1beta1.ConditionServiceReady:        "True/ServiceReconciled",
+		v1beta1.ConditionIngressReady:        "True/IngressReconciled",
+		v1beta1.ConditionRouteAccepted:       "True/RouteNotRequired",
+		v1beta1.ConditionCertificatesReady:   "True/CertificatesNotRequired",
+		v1beta1.ConditionReady:               "True/Ready",
+	}, summarizeConditions(got.Status.Conditions))
+}
+
+func TestSetDeploymentConditions(t *testing.T) {
+	tests := map[string]struct {
+		deploys  []appsv1.Deployment
+		expected map[string]string
+	}{
+		"without deployments": {
+			expected: map[string]string{
+				v1beta1.ConditionDeploymentAvailable: "False/DeploymentNotFound",
+			},
+		},
+		"when rollout has exceeded its progress deadline": {
+			deploys: []appsv1.Deployment{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-nginx"},
+					Status: appsv1.DeploymentStatus{
+						Conditions: []appsv1.DeploymentCondition{
+							{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+							{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"},
+						},
+					},
+				},
+			},
+			expected: map[string]string{
+				v1beta1.ConditionDeploymentAvailable: "True/MinimumReplicasAvailable",
+				v1beta1.ConditionProgressing:         "False/ProgressDeadlineExceeded",
+			},
+		},
+		"when old replicas are still running": {
+			deploys: []appsv1.Deployment{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-nginx"},
+					Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(2))},
+					Status: appsv1.DeploymentStatus{
+						Replicas:          3,
+						UpdatedReplicas:   2,
+						AvailableReplicas: 2,
+						Conditions: []appsv1.DeploymentCondition{
+							{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+						},
+					},
+				},
+			},
+			expected: map[string]string{
+				v1beta1.ConditionDeploymentAvailable: "True/MinimumReplicasAvailable",
+				v1beta1.ConditionProgressing:         "True/RolloutInProgress",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nginx := &v1beta1.Nginx{}
+			setDeploymentConditions(nginx, tt.deploys)
+			assert.Equal(t, tt.expected, summarizeConditions(nginx.Status.Conditions))
+		})
+	}
+}
+
+func TestSetReadyCondition(t *testing.T) {
+	nginx := &v1beta1.Nginx{}
+	setCondition(nginx, v1beta1.ConditionConfigValid, metav1.ConditionTrue, reasonValidConfig, "")
+	setCondition(nginx, v1beta1.ConditionDeploymentAvailable, metav1.ConditionTrue, reasonMinimumReplicasAvailable, "")
+	setCondition(nginx, v1beta1.ConditionServiceReady, metav1.ConditionFalse, reasonLoadBalancerPending, "")
+	setCondition(nginx, v1beta1.ConditionIngressReady, metav1.ConditionTrue, reasonIngressNotRequired, "")
+	setCondition(nginx, v1beta1.ConditionRouteAccepted, metav1.ConditionTrue, reasonRouteNotRequired, "")
+	setCondition(nginx, v1beta1.ConditionCertificatesReady, metav1.ConditionTrue, reasonCertificatesNotRequired, "")
+	setCondition(nginx, v1beta1.ConditionProgressing, metav1.ConditionTrue, reasonRolloutInProgress, "")
+
+	setReadyCondition(nginx)
+
+	ready := meta.FindStatusCondition(nginx.Status.Conditions, v1beta1.ConditionReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, "conditions not satisfied: ServiceReady, Progressing", ready.Message)
+}
+
+func summarizeConditions(conditions []metav1.Condition) map[string]string {
+	summary := make(map[string]string)
+	for _, c := range conditions {
+		summary[c.Type] = fmt.Sprintf("%s/%s", c.Status, c.Reason)
+	}
+	return summary
 }
 
 func TestNginxReconciler_shouldManageNginx(t *testing.T) {
 	tests := []struct {
-		nginx            *v1alpha1.Nginx
+		nginx            *v1beta1.Nginx
 		annotationFilter func() labels.Selector
 		expected         bool
 	}{
 		{
-			nginx: &v1alpha1.Nginx{},
+			nginx: &v1beta1.Nginx{},
 			annotationFilter: func() labels.Selector {
 				return nil
 			},
 			expected: true,
 		},
 		{
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
 						"custom.annotation":  "foo",
@@ -1582,7 +2320,7 @@ func TestNginxReconciler_shouldManageNginx(t *testing.T) {
 			expected: true,
 		},
 		{
-			nginx: &v1alpha1.Nginx{
+			nginx: &v1beta1.Nginx{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
 						"my.custom.annotation/skip": "true",
@@ -1608,149 +2346,8 @@ func TestNginxReconciler_shouldManageNginx(t *testing.T) {
 	}
 }
 
-func TestShouldUpdateIngress(t *testing.T) {
-	tests := []struct {
-		current  *networkingv1.Ingress
-		new      *networkingv1.Ingress
-		expected bool
-	}{
-		{
-			current:  nil,
-			new:      &networkingv1.Ingress{},
-			expected: false,
-		},
-		{
-			current:  &networkingv1.Ingress{},
-			new:      nil,
-			expected: false,
-		},
-		{
-			current:  &networkingv1.Ingress{},
-			new:      &networkingv1.Ingress{},
-			expected: false,
-		},
-		{
-			current: &networkingv1.Ingress{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{
-						"a": "1",
-						"b": "2",
-						"c": "3",
-					},
-				},
-			},
-			new: &networkingv1.Ingress{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{
-						"a": "1",
-						"d": "4",
-					},
-				},
-			},
-			expected: true,
-		},
-		{
-			current: &networkingv1.Ingress{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{
-						"a": "1",
-						"b": "2",
-						"c": "3",
-					},
-				},
-			},
-			new: &networkingv1.Ingress{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{
-						"a": "1",
-						"b": "2",
-					},
-				},
-			},
-			expected: false,
-		},
-		{
-			current: &networkingv1.Ingress{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"a": "1",
-						"b": "2",
-						"c": "3",
-					},
-				},
-			},
-			new: &networkingv1.Ingress{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"a": "1",
-						"b": "2",
-					},
-				},
-			},
-			expected: true,
-		},
-		{
-			current: &networkingv1.Ingress{
-				Spec: networkingv1.IngressSpec{
-					IngressClassName: ptr.To("old"),
-				},
-			},
-			new: &networkingv1.Ingress{
-				Spec: networkingv1.IngressSpec{
-					IngressClassName: ptr.To("new"),
-				},
-			},
-			expected: true,
-		},
-		{
-			current: &networkingv1.Ingress{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"a": "1",
-						"b": "2",
-						"c": "3",
-					},
-					Annotations: map[string]string{
-						"a": "1",
-						"b": "2",
-						"c": "3",
-					},
-				},
-				Spec: networkingv1.IngressSpec{
-					IngressClassName: ptr.To("class"),
-				},
-			},
-			new: &networkingv1.Ingress{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"a": "1",
-						"b": "2",
-						"c": "3",
-					},
-					Annotations: map[string]string{
-						"a": "1",
-						"b": "2",
-						"c": "3",
-					},
-				},
-				Spec: networkingv1.IngressSpec{
-					IngressClassName: ptr.To("class"),
-				},
-			},
-			expected: false,
-		},
-	}
-
-	for index, test := range tests {
-		t.Run(fmt.Sprintf("case %d", index), func(t *testing.T) {
-			update := shouldUpdateIngress(test.current, test.new)
-			assert.Equal(t, test.expected, update)
-		})
-	}
-}
-
 func TestListServices(t *testing.T) {
-	nginx := &v1alpha1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"}}
+	nginx := &v1beta1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"}}
 
 	resources := []runtime.Object{
 		&corev1.Service{
@@ -1828,7 +2425,7 @@ func TestListServices(t *testing.T) {
 
 	svcs, err := listServices(context.Background(), client, nginx)
 	assert.NoError(t, err)
-	assert.Equal(t, []v1alpha1.ServiceStatus{
+	assert.Equal(t, []v1beta1.ServiceStatus{
 		{
 			Name: "my-nginx-service",
 			IPs:  []string{"1.1.1.1", "1.1.1.2"},
@@ -1845,7 +2442,7 @@ func TestListServices(t *testing.T) {
 }
 
 func TestListIngress(t *testing.T) {
-	nginx := &v1alpha1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"}}
+	nginx := &v1beta1.Nginx{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"}}
 
 	resources := []runtime.Object{
 		&networkingv1.Ingress{
@@ -1923,7 +2520,7 @@ func TestListIngress(t *testing.T) {
 
 	svcs, err := listIngresses(context.Background(), client, nginx)
 	assert.NoError(t, err)
-	assert.Equal(t, []v1alpha1.IngressStatus{
+	assert.Equal(t, []v1beta1.IngressStatus{
 		{
 			Name: "my-nginx-ing",
 			IPs:  []string{"1.1.1.1", "1.1.1.2"},
@@ -1942,6 +2539,7 @@ func TestListIngress(t *testing.T) {
 func newScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	clientgoscheme.AddToScheme(scheme)
-	v1alpha1.AddToScheme(scheme)
+	v1beta1.AddToScheme(scheme)
+	gatewayv1beta1.AddToScheme(scheme)
 	return scheme
 }
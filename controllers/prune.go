@@ -0,0 +1,175 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+// childResources holds the names of the desired resources of a kind, along
+// with the list used to fetch the current ones.
+type childResources struct {
+	kind    string
+	list    client.ObjectList
+	desired []string
+}
+
+// desiredChildren returns every kind of resource managed by the Nginx along
+// with the names it should have, given the color selected by its Services.
+func desiredChildren(nginx *nginxv1beta1.Nginx, activeColor string) []childResources {
+	deployments := []string{nginx.Name}
+	switch {
+	case isCanaryStrategy(nginx):
+		deployments = append(deployments, k8s.CanaryName(nginx))
+
+	case isBlueGreenStrategy(nginx):
+		// NOTE: the Nginx's Deployment is kept until the Services are
+		// switched over to a color for the first time.
+		if activeColor != "" {
+			deployments = nil
+		}
+		deployments = append(deployments, k8s.ColorDeploymentName(nginx, k8s.ColorBlue), k8s.ColorDeploymentName(nginx, k8s.ColorGreen))
+	}
+
+	var services []string
+	for _, svc := range k8s.NewServices(nginx) {
+		services = append(services, svc.Name)
+	}
+
+	var ingresses []string
+	if nginx.Spec.Ingress != nil {
+		ingresses = append(ingresses, k8s.NewIngress(nginx).Name)
+	}
+
+	if !shouldDeleteIpv6Ingress(nginx) {
+		ingresses = append(ingresses, ipv6IngressName(nginx))
+	}
+
+	var routes []string
+	if nginx.Spec.Gateway != nil {
+		routes = append(routes, k8s.NewHTTPRoute(nginx).Name)
+	}
+
+	var disruptionBudgets []string
+	if nginx.Spec.DisruptionBudget != nil {
+		disruptionBudgets = append(disruptionBudgets, k8s.NewPodDisruptionBudget(nginx).Name)
+	}
+
+	var autoscalers []string
+	if nginx.Spec.Autoscaling != nil {
+		autoscalers = append(autoscalers, k8s.NewHorizontalPodAutoscaler(nginx).Name)
+	}
+
+	var certificates []string
+	for _, cert := range k8s.NewCertificates(nginx) {
+		certificates = append(certificates, cert.GetName())
+	}
+
+	var podMonitors, serviceMonitors []string
+	switch k8s.MonitorKind(nginx) {
+	case nginxv1beta1.NginxMonitorPodMonitor:
+		podMonitors = append(podMonitors, k8s.NewMonitor(nginx).GetName())
+	case nginxv1beta1.NginxMonitorServiceMonitor:
+		serviceMonitors = append(serviceMonitors, k8s.NewMonitor(nginx).GetName())
+	}
+
+	certList := &unstructured.UnstructuredList{}
+	certList.SetGroupVersionKind(k8s.CertificateGVK.GroupVersion().WithKind(k8s.CertificateGVK.Kind + "List"))
+
+	return []childResources{
+		{kind: "Deployment", list: &appsv1.DeploymentList{}, desired: deployments},
+		{kind: "Service", list: &corev1.ServiceList{}, desired: services},
+		{kind: "Ingress", list: &networkingv1.IngressList{}, desired: ingresses},
+		{kind: "HTTPRoute", list: &gatewayv1beta1.HTTPRouteList{}, desired: routes},
+		{kind: "PodDisruptionBudget", list: &policyv1.PodDisruptionBudgetList{}, desired: disruptionBudgets},
+		{kind: "HorizontalPodAutoscaler", list: &autoscalingv2.HorizontalPodAutoscalerList{}, desired: autoscalers},
+		{kind: "Certificate", list: certList, desired: certificates},
+		{kind: "PodMonitor", list: newUnstructuredList(k8s.PodMonitorGVK), desired: podMonitors},
+		{kind: "ServiceMonitor", list: newUnstructuredList(k8s.ServiceMonitorGVK), desired: serviceMonitors},
+	}
+}
+
+func newUnstructuredList(gvk schema.GroupVersionKind) *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	return list
+}
+
+// pruneOrphans deletes the resources labeled and controlled by the Nginx which
+// are no longer desired, e.g. left behind by renamed children or older
+// layouts. When PruneDryRun is set, they're only reported.
+func (r *NginxReconciler) pruneOrphans(ctx context.Context, nginx *nginxv1beta1.Nginx) error {
+	var activeColor string
+	if isBlueGreenStrategy(nginx) {
+		var err error
+		if activeColor, err = r.activeColor(ctx, nginx); err != nil {
+			return err
+		}
+	}
+
+	for _, children := range desiredChildren(nginx, activeColor) {
+		err := r.Client.List(ctx, children.list, client.InNamespace(nginx.Namespace), client.MatchingLabels(k8s.LabelsForNginx(nginx.Name)))
+		if meta.IsNoMatchError(err) {
+			// NOTE: optional kinds (e.g. HTTPRoute) might not be installed in
+			// the cluster, thus there's nothing to prune.
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to list %s resources: %w", children.kind, err)
+		}
+
+		items, err := meta.ExtractList(children.list)
+		if err != nil {
+			return fmt.Errorf("failed to extract %s resources: %w", children.kind, err)
+		}
+
+		desired := make(map[string]struct{}, len(children.desired))
+		for _, name := range children.desired {
+			desired[name] = struct{}{}
+		}
+
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+
+			if _, found := desired[obj.GetName()]; found || !metav1.IsControlledBy(obj, nginx) || !obj.GetDeletionTimestamp().IsZero() {
+				continue
+			}
+
+			if r.PruneDryRun {
+				r.EventRecorder.Eventf(nginx, corev1.EventTypeNormal, "OrphanFound", "%s %s is orphaned and would be deleted (dry-run)", children.kind, obj.GetName())
+				continue
+			}
+
+			if err = r.Client.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+				r.EventRecorder.Eventf(nginx, corev1.EventTypeWarning, "OrphanDeletionFailed", "failed to delete orphaned %s %s: %s", children.kind, obj.GetName(), err)
+				return fmt.Errorf("failed to delete orphaned %s resource: %w", children.kind, err)
+			}
+
+			r.EventRecorder.Eventf(nginx, corev1.EventTypeNormal, "OrphanDeleted", "orphaned %s %s deleted successfully", children.kind, obj.GetName())
+		}
+	}
+
+	return nil
+}
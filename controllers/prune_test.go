@@ -0,0 +1,216 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+func TestNginxReconciler_pruneOrphans(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default", UID: "nginx-uid"},
+		Spec: v1beta1.NginxSpec{
+			Ingress: &v1beta1.NginxIngress{},
+		},
+	}
+
+	owned := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			Labels:          k8s.LabelsForNginx("my-nginx"),
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(nginx, v1beta1.GroupVersion.WithKind("Nginx"))},
+		}
+	}
+
+	orphanCert := newCertificate()
+	orphanCert.SetName("my-nginx-old-secret")
+	orphanCert.SetNamespace("default")
+	orphanCert.SetLabels(k8s.LabelsForNginx("my-nginx"))
+	orphanCert.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(nginx, v1beta1.GroupVersion.WithKind("Nginx"))})
+
+	resources := func() []runtime.Object {
+		return []runtime.Object{
+			&appsv1.Deployment{ObjectMeta: owned("my-nginx")},
+			&appsv1.Deployment{ObjectMeta: owned("my-nginx-old")},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "my-nginx-manual", Namespace: "default", Labels: k8s.LabelsForNginx("my-nginx")}},
+			&networkingv1.Ingress{ObjectMeta: owned("my-nginx")},
+			&networkingv1.Ingress{ObjectMeta: owned("my-nginx-legacy")},
+			&autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: owned("my-nginx")},
+			orphanCert.DeepCopy(),
+		}
+	}
+
+	tests := map[string]struct {
+		dryRun         bool
+		expectedEvents []string
+		expectedLeft   map[string][]string
+	}{
+		"deleting orphans": {
+			expectedEvents: []string{
+				"Normal OrphanDeleted orphaned Deployment my-nginx-old deleted successfully",
+				"Normal OrphanDeleted orphaned Ingress my-nginx-legacy deleted successfully",
+				"Normal OrphanDeleted orphaned HorizontalPodAutoscaler my-nginx deleted successfully",
+				"Normal OrphanDeleted orphaned Certificate my-nginx-old-secret deleted successfully",
+			},
+			expectedLeft: map[string][]string{
+				"Deployment":              {"my-nginx", "my-nginx-manual"},
+				"Ingress":                 {"my-nginx"},
+				"HorizontalPodAutoscaler": nil,
+				"Certificate":             nil,
+			},
+		},
+
+		"dry-run only reports orphans": {
+			dryRun: true,
+			expectedEvents: []string{
+				"Normal OrphanFound Deployment my-nginx-old is orphaned and would be deleted (dry-run)",
+				"Normal OrphanFound Ingress my-nginx-legacy is orphaned and would be deleted (dry-run)",
+				"Normal OrphanFound HorizontalPodAutoscaler my-nginx is orphaned and would be deleted (dry-run)",
+				"Normal OrphanFound Certificate my-nginx-old-secret is orphaned and would be deleted (dry-run)",
+			},
+			expectedLeft: map[string][]string{
+				"Deployment":              {"my-nginx", "my-nginx-manual", "my-nginx-old"},
+				"Ingress":                 {"my-nginx", "my-nginx-legacy"},
+				"HorizontalPodAutoscaler": {"my-nginx"},
+				"Certificate":             {"my-nginx-old-secret"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := fake.NewClientBuilder().
+				WithScheme(newScheme()).
+				WithRuntimeObjects(resources()...).
+				Build()
+
+			er := record.NewFakeRecorder(10)
+			r := &NginxReconciler{Client: c, EventRecorder: er, PruneDryRun: tt.dryRun}
+			require.NoError(t, r.pruneOrphans(context.TODO(), nginx))
+
+			close(er.Events)
+			var events []string
+			for event := range er.Events {
+				events = append(events, event)
+			}
+			assert.ElementsMatch(t, tt.expectedEvents, events)
+
+			lists := map[string]client.ObjectList{
+				"Deployment":              &appsv1.DeploymentList{},
+				"Ingress":                 &networkingv1.IngressList{},
+				"HorizontalPodAutoscaler": &autoscalingv2.HorizontalPodAutoscalerList{},
+				"Certificate":             newCertificateList(),
+			}
+
+			for kind, list := range lists {
+				require.NoError(t, c.List(context.TODO(), list))
+
+				items, err := meta.ExtractList(list)
+				require.NoError(t, err)
+
+				var names []string
+				for _, item := range items {
+					names = append(names, item.(client.Object).GetName())
+				}
+
+				assert.ElementsMatch(t, tt.expectedLeft[kind], names, kind)
+			}
+		})
+	}
+}
+
+func TestNginxReconciler_pruneOrphans_blueGreen(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default", UID: "nginx-uid"},
+		Spec: v1beta1.NginxSpec{
+			Strategy: &v1beta1.NginxStrategy{Type: v1beta1.NginxStrategyBlueGreen},
+		},
+	}
+
+	owned := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			Labels:          k8s.LabelsForNginx("my-nginx"),
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(nginx, v1beta1.GroupVersion.WithKind("Nginx"))},
+		}
+	}
+
+	tests := map[string]struct {
+		selector       map[string]string
+		expectedEvents []string
+		expectedLeft   []string
+	}{
+		"services not switched over to a color yet": {
+			selector:     k8s.LabelsForNginx("my-nginx"),
+			expectedLeft: []string{"my-nginx", "my-nginx-blue"},
+		},
+
+		"services selecting a color": {
+			selector: func() map[string]string {
+				selector := k8s.LabelsForNginx("my-nginx")
+				selector[k8s.ColorLabel] = k8s.ColorBlue
+				return selector
+			}(),
+			expectedEvents: []string{
+				"Normal OrphanDeleted orphaned Deployment my-nginx deleted successfully",
+			},
+			expectedLeft: []string{"my-nginx-blue"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc := k8s.NewService(nginx)
+			svc.Spec.Selector = tt.selector
+
+			c := fake.NewClientBuilder().
+				WithScheme(newScheme()).
+				WithRuntimeObjects(
+					&appsv1.Deployment{ObjectMeta: owned("my-nginx")},
+					&appsv1.Deployment{ObjectMeta: owned("my-nginx-blue")},
+					svc,
+				).
+				Build()
+
+			er := record.NewFakeRecorder(10)
+			r := &NginxReconciler{Client: c, EventRecorder: er}
+			require.NoError(t, r.pruneOrphans(context.TODO(), nginx))
+
+			close(er.Events)
+			var events []string
+			for event := range er.Events {
+				events = append(events, event)
+			}
+			assert.ElementsMatch(t, tt.expectedEvents, events)
+
+			var deployments appsv1.DeploymentList
+			require.NoError(t, c.List(context.TODO(), &deployments))
+
+			var names []string
+			for _, dep := range deployments.Items {
+				names = append(names, dep.Name)
+			}
+			assert.ElementsMatch(t, tt.expectedLeft, names)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+const (
+	// configMapsIndexKey indexes the Nginx resources by the names of the
+	// ConfigMaps they reference.
+	configMapsIndexKey = ".spec.configMaps"
+
+	// tlsSecretsIndexKey indexes the Nginx resources by the names of their
+	// TLS Secrets.
+	tlsSecretsIndexKey = ".spec.tls.secretNames"
+)
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+func indexNginxByConfigMaps(obj client.Object) []string {
+	nginx, ok := obj.(*nginxv1beta1.Nginx)
+	if !ok {
+		return nil
+	}
+
+	return k8s.ConfigMapNames(nginx.Spec)
+}
+
+func indexNginxByTLSSecrets(obj client.Object) []string {
+	nginx, ok := obj.(*nginxv1beta1.Nginx)
+	if !ok {
+		return nil
+	}
+
+	return k8s.TLSSecretNames(nginx.Spec)
+}
+
+// nginxesReferencing returns a function which maps an object to the reconcile
+// requests of the Nginx resources referencing it through the given index.
+func (r *NginxReconciler) nginxesReferencing(indexKey string) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		var nginxList nginxv1beta1.NginxList
+		err := r.Client.List(context.Background(), &nginxList,
+			client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{indexKey: obj.GetName()},
+		)
+		if err != nil {
+			r.Log.Error(err, "Unable to list Nginx resources referencing object", "object", client.ObjectKeyFromObject(obj), "index", indexKey)
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, nginx := range nginxList.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: nginx.Name, Namespace: nginx.Namespace},
+			})
+		}
+
+		return requests
+	}
+}
+
+// referencedConfigMaps returns the existing ConfigMaps referenced by the
+// nginx. Missing ones are skipped, the nginx pods can't start without them
+// anyway.
+func (r *NginxReconciler) referencedConfigMaps(ctx context.Context, nginx *nginxv1beta1.Nginx) ([]corev1.ConfigMap, error) {
+	var configMaps []corev1.ConfigMap
+	for _, name := range k8s.ConfigMapNames(nginx.Spec) {
+		var cm corev1.ConfigMap
+		err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: nginx.Namespace}, &cm)
+		if errors.IsNotFound(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve ConfigMap %q: %w", name, err)
+		}
+
+		configMaps = append(configMaps, cm)
+	}
+
+	return configMaps, nil
+}
+
+// referencedTLSSecrets returns the existing TLS Secrets of the nginx, only
+// when they are needed by its TLS reload strategy.
+func (r *NginxReconciler) referencedTLSSecrets(ctx context.Context, nginx *nginxv1beta1.Nginx) ([]corev1.Secret, error) {
+	if nginx.Spec.TLSReloadStrategy != nginxv1beta1.TLSReloadStrategyRollingRestart &&
+		nginx.Spec.TLSReloadStrategy != nginxv1beta1.TLSReloadStrategyReload {
+		return nil, nil
+	}
+
+	var secrets []corev1.Secret
+	for _, name := range k8s.TLSSecretNames(nginx.Spec) {
+		var secret corev1.Secret
+		err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: nginx.Namespace}, &secret)
+		if errors.IsNotFound(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve Secret %q: %w", name, err)
+		}
+
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+func TestIndexNginxByConfigMaps(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		Spec: v1beta1.NginxSpec{
+			Config:     &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindConfigMap, Name: "my-config"},
+			ExtraFiles: &v1beta1.FilesRef{Name: "my-files"},
+		},
+	}
+
+	assert.Equal(t, []string{"my-config", "my-files"}, indexNginxByConfigMaps(nginx))
+	assert.Nil(t, indexNginxByConfigMaps(&corev1.ConfigMap{}))
+}
+
+func TestIndexNginxByTLSSecrets(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		Spec: v1beta1.NginxSpec{
+			TLS: []v1beta1.NginxTLS{
+				{SecretName: "example-com"},
+				{SecretName: "example-org"},
+				{SecretName: "example-com"},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{"example-com", "example-org"}, indexNginxByTLSSecrets(nginx))
+	assert.Nil(t, indexNginxByTLSSecrets(&corev1.Secret{}))
+}
+
+func TestNginxReconciler_reconcileDeployment_configMapChanges(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+		Spec: v1beta1.NginxSpec{
+			Config:     &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindConfigMap, Name: "my-config"},
+			ExtraFiles: &v1beta1.FilesRef{Name: "my-files"},
+		},
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-config", Namespace: "default"},
+		Data:       map[string]string{"nginx.conf": "events {}"},
+	}
+
+	client := withServerSideApply(fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		WithObjects(configMap).
+		Build())
+
+	r := &NginxReconciler{Client: client, Log: ctrl.Log.WithName("test")}
+
+	getHash := func() string {
+		var dep appsv1.Deployment
+		require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &dep))
+		return dep.Spec.Template.Annotations["nginx.tsuru.io/config-hash"]
+	}
+
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	original := getHash()
+	assert.NotEmpty(t, original)
+
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	assert.Equal(t, original, getHash())
+
+	configMap.Data["nginx.conf"] = "events {}\nhttp {}"
+	require.NoError(t, client.Update(context.TODO(), configMap))
+
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	updated := getHash()
+	assert.NotEqual(t, original, updated)
+
+	require.NoError(t, client.Create(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-files", Namespace: "default"},
+		Data:       map[string]string{"index.html": "Hello world"},
+	}))
+
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	assert.NotEqual(t, updated, getHash())
+}
+
+func TestNginxReconciler_reconcileDeployment_tlsSecretChanges(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-com", Namespace: "default"},
+		Data:       map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+	}
+
+	tests := map[string]struct {
+		strategy        v1beta1.TLSReloadStrategy
+		expectedRestart bool
+	}{
+		"without reload strategy": {},
+
+		"with reload strategy": {
+			strategy: v1beta1.TLSReloadStrategyReload,
+		},
+
+		"with rolling restart strategy": {
+			strategy:        v1beta1.TLSReloadStrategyRollingRestart,
+			expectedRestart: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nginx := &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+				Spec: v1beta1.NginxSpec{
+					TLS:               []v1beta1.NginxTLS{{SecretName: "example-com"}},
+					TLSReloadStrategy: tt.strategy,
+				},
+			}
+
+			client := withServerSideApply(fake.NewClientBuilder().
+				WithScheme(newScheme()).
+				WithObjects(secret.DeepCopy()).
+				Build())
+
+			r := &NginxReconciler{Client: client, Log: ctrl.Log.WithName("test")}
+
+			getHash := func() string {
+				var dep appsv1.Deployment
+				require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &dep))
+				return dep.Spec.Template.Annotations["nginx.tsuru.io/tls-hash"]
+			}
+
+			require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+			original := getHash()
+
+			renewed := secret.DeepCopy()
+			require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "example-com", Namespace: "default"}, renewed))
+			renewed.Data["tls.crt"] = []byte("renewed cert")
+			require.NoError(t, client.Update(context.TODO(), renewed))
+
+			require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+
+			if !tt.expectedRestart {
+				assert.Empty(t, original)
+				assert.Empty(t, getHash())
+				return
+			}
+
+			assert.NotEmpty(t, original)
+			assert.NotEqual(t, original, getHash())
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+const (
+	// rollbackToAnnotation, set to a revision number on the Nginx, restores
+	// the spec recorded by that revision. It's removed once handled.
+	rollbackToAnnotation = "nginx.tsuru.io/rollback-to"
+
+	defaultRevisionHistoryLimit = 10
+)
+
+// reconcileRevisions records the Nginx spec as a ControllerRevision, pruning
+// the previous ones beyond the history limit, and keeps the current and
+// previous revision numbers in the status.
+func (r *NginxReconciler) reconcileRevisions(ctx context.Context, nginx *nginxv1beta1.Nginx) error {
+	newRevision, err := k8s.NewControllerRevision(nginx, 0)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := listRevisions(ctx, r.Client, nginx)
+	if err != nil {
+		return err
+	}
+
+	var latest int64
+	if len(revisions) > 0 {
+		latest = revisions[len(revisions)-1].Revision
+	}
+
+	var current *appsv1.ControllerRevision
+	var history []appsv1.ControllerRevision
+	for i := range revisions {
+		if revisions[i].Name == newRevision.Name {
+			current = &revisions[i]
+			continue
+		}
+		history = append(history, revisions[i])
+	}
+
+	switch {
+	case current == nil:
+		newRevision.Revision = latest + 1
+		if err = r.Client.Create(ctx, newRevision); err != nil {
+			return fmt.Errorf("failed to create ControllerRevision: %w", err)
+		}
+		current = newRevision
+
+	// NOTE: a spec applied once again (e.g. restored from a previous
+	// revision) becomes the latest revision, as StatefulSets do.
+	case current.Revision != latest:
+		patch := client.MergeFrom(current.DeepCopy())
+		current.Revision = latest + 1
+		if err = r.Client.Patch(ctx, current, patch); err != nil {
+			return fmt.Errorf("failed to update ControllerRevision: %w", err)
+		}
+	}
+
+	nginx.Status.CurrentRevision = current.Revision
+	nginx.Status.PreviousRevision = 0
+	if len(history) > 0 {
+		nginx.Status.PreviousRevision = history[len(history)-1].Revision
+	}
+
+	limit := defaultRevisionHistoryLimit
+	if nginx.Spec.RevisionHistoryLimit != nil {
+		limit = int(*nginx.Spec.RevisionHistoryLimit)
+	}
+
+	for i := 0; i < len(history)-limit; i++ {
+		if err = r.Client.Delete(ctx, &history[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete ControllerRevision: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// rollbackToRevision restores the Nginx spec from the revision set on the
+// rollback-to annotation, removing it afterwards. It reports whether the
+// Nginx was updated, which triggers a new reconciliation.
+func (r *NginxReconciler) rollbackToRevision(ctx context.Context, nginx *nginxv1beta1.Nginx) (bool, error) {
+	value, ok := nginx.Annotations[rollbackToAnnotation]
+	if !ok {
+		return false, nil
+	}
+
+	revisions, err := listRevisions(ctx, r.Client, nginx)
+	if err != nil {
+		return false, err
+	}
+
+	var found *appsv1.ControllerRevision
+	if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+		for i := range revisions {
+			if revisions[i].Revision == number {
+				found = &revisions[i]
+				break
+			}
+		}
+	}
+
+	delete(nginx.Annotations, rollbackToAnnotation)
+
+	if found == nil {
+		r.EventRecorder.Eventf(nginx, corev1.EventTypeWarning, "RevisionNotFound", "revision %q not found to roll back to", value)
+	} else {
+		spec, err := k8s.ExtractRevisionSpec(found)
+		if err != nil {
+			return false, err
+		}
+
+		nginx.Spec = spec
+		r.EventRecorder.Eventf(nginx, corev1.EventTypeNormal, "RevisionRestored", "Nginx spec restored from revision %d", found.Revision)
+	}
+
+	if err = r.Client.Update(ctx, nginx); err != nil {
+		return false, fmt.Errorf("failed to update Nginx: %w", err)
+	}
+
+	return true, nil
+}
+
+// listRevisions returns the ControllerRevisions of the Nginx sorted by their
+// revision number.
+func listRevisions(ctx context.Context, c client.Client, nginx *nginxv1beta1.Nginx) ([]appsv1.ControllerRevision, error) {
+	var list appsv1.ControllerRevisionList
+	err := c.List(ctx, &list, client.InNamespace(nginx.Namespace), client.MatchingLabels(k8s.LabelsForNginx(nginx.Name)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ControllerRevisions: %w", err)
+	}
+
+	var revisions []appsv1.ControllerRevision
+	for _, rev := range list.Items {
+		if metav1.IsControlledBy(&rev, nginx) {
+			revisions = append(revisions, rev)
+		}
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+
+	return revisions, nil
+}
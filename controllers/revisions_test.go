@@ -0,0 +1,159 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+func TestNginxReconciler_reconcileRevisions(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+		Spec:       v1beta1.NginxSpec{Image: "nginx:1.24"},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		Build()
+
+	r := &NginxReconciler{Client: c, EventRecorder: record.NewFakeRecorder(10)}
+
+	require.NoError(t, r.reconcileRevisions(context.TODO(), nginx))
+	assert.Equal(t, int64(1), nginx.Status.CurrentRevision)
+	assert.Equal(t, int64(0), nginx.Status.PreviousRevision)
+
+	// the same spec isn't recorded twice
+	require.NoError(t, r.reconcileRevisions(context.TODO(), nginx))
+	assert.Equal(t, map[string]int64{"nginx:1.24": 1}, revisionImages(t, c, nginx))
+
+	nginx.Spec.Image = "nginx:1.25"
+	require.NoError(t, r.reconcileRevisions(context.TODO(), nginx))
+	assert.Equal(t, int64(2), nginx.Status.CurrentRevision)
+	assert.Equal(t, int64(1), nginx.Status.PreviousRevision)
+
+	nginx.Spec.Image = "nginx:1.24"
+	require.NoError(t, r.reconcileRevisions(context.TODO(), nginx))
+	assert.Equal(t, int64(3), nginx.Status.CurrentRevision)
+	assert.Equal(t, int64(2), nginx.Status.PreviousRevision)
+	assert.Equal(t, map[string]int64{"nginx:1.24": 3, "nginx:1.25": 2}, revisionImages(t, c, nginx))
+
+	nginx.Spec.Image = "nginx:1.26"
+	nginx.Spec.RevisionHistoryLimit = func(n int32) *int32 { return &n }(int32(1))
+	require.NoError(t, r.reconcileRevisions(context.TODO(), nginx))
+	assert.Equal(t, int64(4), nginx.Status.CurrentRevision)
+	assert.Equal(t, int64(3), nginx.Status.PreviousRevision)
+
+	revisions := revisionImages(t, c, nginx)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, int64(3), revisions["nginx:1.24"])
+}
+
+func TestNginxReconciler_reconcileNginx_revisionsHeldByConfigTest(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default", UID: "nginx-uid"},
+		Spec:       v1beta1.NginxSpec{Image: "nginx:1.24"},
+	}
+
+	c := withServerSideApply(fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		Build())
+
+	r := &NginxReconciler{Client: c, EventRecorder: record.NewFakeRecorder(10), ConfigTest: true}
+
+	require.NoError(t, r.reconcileNginx(context.TODO(), nginx))
+	assert.Equal(t, map[string]int64{"nginx:1.24": 1}, revisionImages(t, c, nginx))
+
+	nginx.Spec.Image = "nginx:1.25"
+	require.NoError(t, r.reconcileNginx(context.TODO(), nginx))
+	assert.Equal(t, map[string]int64{"nginx:1.24": 1}, revisionImages(t, c, nginx))
+	assert.Equal(t, int64(1), nginx.Status.CurrentRevision)
+
+	jobs := listConfigTestJobs(t, c)
+	require.Len(t, jobs, 1)
+	finishConfigTest(t, c, &jobs[0], batchv1.JobComplete, "nginx: configuration file /etc/nginx/nginx.conf test is successful\n")
+
+	require.NoError(t, r.reconcileNginx(context.TODO(), nginx))
+	assert.Equal(t, map[string]int64{"nginx:1.24": 1, "nginx:1.25": 2}, revisionImages(t, c, nginx))
+	assert.Equal(t, int64(2), nginx.Status.CurrentRevision)
+}
+
+func TestNginxReconciler_rollbackToRevision(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+		Spec:       v1beta1.NginxSpec{Image: "nginx:1.24", Replicas: func(n int32) *int32 { return &n }(int32(2))},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		WithObjects(nginx).
+		Build()
+
+	er := record.NewFakeRecorder(10)
+	r := &NginxReconciler{Client: c, EventRecorder: er}
+	require.NoError(t, r.reconcileRevisions(context.TODO(), nginx))
+
+	nginx.Spec.Image = "nginx:1.25"
+	nginx.Spec.Replicas = nil
+	require.NoError(t, r.reconcileRevisions(context.TODO(), nginx))
+
+	updated, err := r.rollbackToRevision(context.TODO(), nginx)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	tests := []struct {
+		revision      string
+		expectedImage string
+		expectedEvent string
+	}{
+		{revision: "1", expectedImage: "nginx:1.24", expectedEvent: "Normal RevisionRestored Nginx spec restored from revision 1"},
+		{revision: "42", expectedImage: "nginx:1.24", expectedEvent: `Warning RevisionNotFound revision "42" not found to roll back to`},
+		{revision: "2", expectedImage: "nginx:1.25", expectedEvent: "Normal RevisionRestored Nginx spec restored from revision 2"},
+	}
+
+	for _, tt := range tests {
+		var current v1beta1.Nginx
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &current))
+		current.Annotations = map[string]string{rollbackToAnnotation: tt.revision, "foo": "bar"}
+
+		updated, err = r.rollbackToRevision(context.TODO(), &current)
+		require.NoError(t, err)
+		assert.True(t, updated)
+
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &current))
+		assert.Equal(t, map[string]string{"foo": "bar"}, current.Annotations)
+		assert.Equal(t, tt.expectedImage, current.Spec.Image)
+		assert.Equal(t, tt.expectedEvent, <-er.Events)
+	}
+
+	var current v1beta1.Nginx
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &current))
+	assert.Nil(t, current.Spec.Replicas)
+}
+
+func revisionImages(t *testing.T, c client.Client, nginx *v1beta1.Nginx) map[string]int64 {
+	revisions, err := listRevisions(context.TODO(), c, nginx)
+	require.NoError(t, err)
+
+	images := make(map[string]int64)
+	for i := range revisions {
+		spec, err := k8s.ExtractRevisionSpec(&revisions[i])
+		require.NoError(t, err)
+		images[spec.Image] = revisions[i].Revision
+	}
+	return images
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+const (
+	reasonRolledBack  = "RolledBack"
+	reasonSpecChanged = "SpecChanged"
+)
+
+// setLastKnownGood keeps the last known good spec on the desired Deployment:
+// the spec of the current Deployment once its rollout completed, otherwise
+// the one it already keeps.
+func setLastKnownGood(desired, current *appsv1.Deployment) {
+	good := current.Annotations[k8s.LastKnownGoodAnnotation]
+	if isRolloutComplete(current) {
+		good = current.Annotations[k8s.GeneratedFromAnnotation]
+	}
+
+	if good == "" {
+		return
+	}
+
+	if desired.Annotations == nil {
+		desired.Annotations = make(map[string]string)
+	}
+	desired.Annotations[k8s.LastKnownGoodAnnotation] = good
+}
+
+func isRolloutComplete(d *appsv1.Deployment) bool {
+	return isDeploymentAvailable(d) && !isDeploymentStuck(d) && !isDeploymentProgressing(d)
+}
+
+// isRolledBack reports whether the desired Deployment is the one which was
+// rolled back, thus it must not be rolled out again until the Nginx spec (or
+// its configs) change.
+func isRolledBack(desired, current *appsv1.Deployment) (bool, error) {
+	from := current.Annotations[k8s.RolledBackFromAnnotation]
+	if from == "" {
+		return false, nil
+	}
+
+	hash, err := k8s.PodTemplateHash(&desired.Spec.Template)
+	if err != nil {
+		return false, err
+	}
+
+	return from == hash, nil
+}
+
+// rollback re-applies the last known good spec whenever the rollout of the
+// current Deployment exceeded its progress deadline, reporting whether it
+// was rolled back. The Nginx spec is left untouched.
+func (r *NginxReconciler) rollback(ctx context.Context, nginx *nginxv1beta1.Nginx, desired, current *appsv1.Deployment) (bool, error) {
+	good := current.Annotations[k8s.LastKnownGoodAnnotation]
+	if !isDeploymentStuck(current) || good == "" || good == current.Annotations[k8s.GeneratedFromAnnotation] {
+		return false, nil
+	}
+
+	var spec nginxv1beta1.NginxSpec
+	if err := json.Unmarshal([]byte(good), &spec); err != nil {
+		return false, fmt.Errorf("failed to unmarshal the last known good spec: %w", err)
+	}
+
+	goodDeploy, err := r.newKnownGoodDeployment(ctx, nginx, spec)
+	if err != nil {
+		return false, err
+	}
+
+	from, err := k8s.PodTemplateHash(&desired.Spec.Template)
+	if err != nil {
+		return false, err
+	}
+
+	goodDeploy.Annotations[k8s.LastKnownGoodAnnotation] = good
+	goodDeploy.Annotations[k8s.RolledBackFromAnnotation] = from
+
+	if err = r.apply(ctx, goodDeploy, current); err != nil {
+		setCondition(nginx, nginxv1beta1.ConditionProgressing, metav1.ConditionFalse, reasonDeploymentFailed, err.Error())
+		return false, fmt.Errorf("failed to roll back Deployment: %w", err)
+	}
+
+	r.EventRecorder.Eventf(nginx, corev1.EventTypeWarning, reasonRolledBack, "Deployment %s exceeded its progress deadline, rolled back to the last known good spec", current.Name)
+	setCondition(nginx, nginxv1beta1.ConditionRolledBack, metav1.ConditionTrue, reasonProgressDeadlineExceeded, "rollout of the current spec exceeded its progress deadline, running the last known good spec until it changes")
+	return true, nil
+}
+
+func (r *NginxReconciler) newKnownGoodDeployment(ctx context.Context, nginx *nginxv1beta1.Nginx, spec nginxv1beta1.NginxSpec) (*appsv1.Deployment, error) {
+	good := nginx.DeepCopy()
+	good.Spec = spec
+
+	dep, err := k8s.NewDeployment(good)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Deployment from the last known good spec: %w", err)
+	}
+
+	configMaps, err := r.referencedConfigMaps(ctx, good)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = k8s.SetConfigMapsHash(dep, configMaps); err != nil {
+		return nil, fmt.Errorf("failed to hash the referenced ConfigMaps: %w", err)
+	}
+
+	secrets, err := r.referencedTLSSecrets(ctx, good)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = k8s.SetTLSSecretsHash(good, dep, secrets); err != nil {
+		return nil, fmt.Errorf("failed to hash the TLS Secrets: %w", err)
+	}
+
+	return dep, nil
+}
+
+// clearRolledBack flags that the spec which was rolled back is no longer the
+// desired one.
+func clearRolledBack(nginx *nginxv1beta1.Nginx) {
+	if meta.IsStatusConditionTrue(nginx.Status.Conditions, nginxv1beta1.ConditionRolledBack) {
+		setCondition(nginx, nginxv1beta1.ConditionRolledBack, metav1.ConditionFalse, reasonSpecChanged, "")
+	}
+}
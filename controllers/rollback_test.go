@@ -0,0 +1,136 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+func TestNginxReconciler_reconcileDeployment_rollback(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+		Spec: v1beta1.NginxSpec{
+			Image:                   "nginx:1.24",
+			ProgressDeadlineSeconds: func(n int32) *int32 { return &n }(int32(300)),
+		},
+	}
+
+	c := withServerSideApply(fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		Build())
+
+	er := record.NewFakeRecorder(10)
+	r := &NginxReconciler{Client: c, EventRecorder: er}
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+
+	dep := getDeployment(t, c)
+	assert.Equal(t, int32(300), *dep.Spec.ProgressDeadlineSeconds)
+	setDeploymentRollout(t, c, dep, appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable"})
+
+	nginx.Spec.Image = "nginx:1.25"
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+
+	dep = getDeployment(t, c)
+	assert.Equal(t, "nginx:1.25", dep.Spec.Template.Spec.Containers[0].Image)
+	assert.Contains(t, dep.Annotations[k8s.LastKnownGoodAnnotation], `"image":"nginx:1.24"`)
+
+	// not stuck yet
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	assert.Equal(t, "nginx:1.25", getDeployment(t, c).Spec.Template.Spec.Containers[0].Image)
+
+	setDeploymentRollout(t, c, dep, appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"})
+	for i := 0; i < 2; i++ {
+		require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+	}
+
+	dep = getDeployment(t, c)
+	assert.Equal(t, "nginx:1.24", dep.Spec.Template.Spec.Containers[0].Image)
+	assert.Contains(t, dep.Annotations[k8s.GeneratedFromAnnotation], `"image":"nginx:1.24"`)
+	assert.Contains(t, dep.Annotations[k8s.LastKnownGoodAnnotation], `"image":"nginx:1.24"`)
+	assert.NotEmpty(t, dep.Annotations[k8s.RolledBackFromAnnotation])
+	assert.Equal(t, "nginx:1.25", nginx.Spec.Image)
+
+	cond := meta.FindStatusCondition(nginx.Status.Conditions, v1beta1.ConditionRolledBack)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, reasonProgressDeadlineExceeded, cond.Reason)
+
+	nginx.Spec.Image = "nginx:1.26"
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+
+	dep = getDeployment(t, c)
+	assert.Equal(t, "nginx:1.26", dep.Spec.Template.Spec.Containers[0].Image)
+	assert.NotContains(t, dep.Annotations, k8s.RolledBackFromAnnotation)
+
+	cond = meta.FindStatusCondition(nginx.Status.Conditions, v1beta1.ConditionRolledBack)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, reasonSpecChanged, cond.Reason)
+
+	close(er.Events)
+	var events []string
+	for event := range er.Events {
+		events = append(events, event)
+	}
+	assert.Equal(t, []string{
+		"Warning RolledBack Deployment my-nginx exceeded its progress deadline, rolled back to the last known good spec",
+	}, events)
+}
+
+func TestNginxReconciler_reconcileDeployment_rollbackWithoutKnownGoodSpec(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+		Spec:       v1beta1.NginxSpec{Image: "nginx:broken"},
+	}
+
+	c := withServerSideApply(fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		Build())
+
+	r := &NginxReconciler{Client: c, EventRecorder: record.NewFakeRecorder(10)}
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+
+	dep := getDeployment(t, c)
+	setDeploymentRollout(t, c, dep, appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"})
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+
+	assert.Equal(t, "nginx:broken", getDeployment(t, c).Spec.Template.Spec.Containers[0].Image)
+	assert.Nil(t, meta.FindStatusCondition(nginx.Status.Conditions, v1beta1.ConditionRolledBack))
+}
+
+func getDeployment(t *testing.T, c client.Client) *appsv1.Deployment {
+	var dep appsv1.Deployment
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &dep))
+	return &dep
+}
+
+func setDeploymentRollout(t *testing.T, c client.Client, dep *appsv1.Deployment, progressing appsv1.DeploymentCondition) {
+	dep.Status = appsv1.DeploymentStatus{
+		ObservedGeneration: dep.Generation,
+		Replicas:           1,
+		UpdatedReplicas:    1,
+		AvailableReplicas:  1,
+		Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+			progressing,
+		},
+	}
+	require.NoError(t, c.Update(context.TODO(), dep))
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+func TestNginxReconciler_reconcileDeployment_configTemplate(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+		Spec: v1beta1.NginxSpec{
+			Config: &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindTemplate, Value: "listen {{ .Ports.http }};"},
+		},
+	}
+
+	c := withServerSideApply(fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		Build())
+
+	er := record.NewFakeRecorder(10)
+	r := &NginxReconciler{Client: c, EventRecorder: er}
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+
+	var dep appsv1.Deployment
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &dep))
+	assert.Equal(t, "listen 8080;", dep.Spec.Template.Annotations["nginx.tsuru.io/custom-nginx-config"])
+
+	nginx.Spec.Config.Value = "listen {{ .Ports.metrics }};"
+	err := r.reconcileDeployment(context.TODO(), nginx)
+	assert.EqualError(t, err, `failed to build Deployment from Nginx: failed to render config template: template: nginx.conf:1:16: executing "nginx.conf" at <.Ports.metrics>: map has no entry for key "metrics"`)
+
+	cond := meta.FindStatusCondition(nginx.Status.Conditions, v1beta1.ConditionConfigValid)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, reasonInvalidConfig, cond.Reason)
+
+	close(er.Events)
+	var events []string
+	for event := range er.Events {
+		events = append(events, event)
+	}
+	assert.Equal(t, []string{
+		`Warning InvalidConfig failed to build Deployment: failed to render config template: template: nginx.conf:1:16: executing "nginx.conf" at <.Ports.metrics>: map has no entry for key "metrics"`,
+	}, events)
+
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &dep))
+	assert.Equal(t, "listen 8080;", dep.Spec.Template.Annotations["nginx.tsuru.io/custom-nginx-config"])
+}
+
+func TestNginxReconciler_reconcileDeployment_invalidConfig(t *testing.T) {
+	nginx := &v1beta1.Nginx{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+		Spec: v1beta1.NginxSpec{
+			Image:  "nginx:stable",
+			Config: &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindConfigMap, Name: "my-config"},
+		},
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-config", Namespace: "default"},
+		Data:       map[string]string{"nginx.conf": "events {}"},
+	}
+
+	c := withServerSideApply(fake.NewClientBuilder().
+		WithScheme(newScheme()).
+		WithObjects(cm).
+		Build())
+
+	er := record.NewFakeRecorder(10)
+	r := &NginxReconciler{Client: c, EventRecorder: er}
+	require.NoError(t, r.reconcileDeployment(context.TODO(), nginx))
+
+	var original appsv1.Deployment
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &original))
+
+	cm.Data["nginx.conf"] = "events {}\nhttp {\n"
+	require.NoError(t, c.Update(context.TODO(), cm))
+	nginx.Spec.Image = "nginx:latest"
+
+	err := r.reconcileDeployment(context.TODO(), nginx)
+	assert.EqualError(t, err, `invalid nginx config: line 2: unexpected end of file, expecting "}"`)
+
+	cond := meta.FindStatusCondition(nginx.Status.Conditions, v1beta1.ConditionConfigValid)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, reasonInvalidConfig, cond.Reason)
+
+	close(er.Events)
+	var events []string
+	for event := range er.Events {
+		events = append(events, event)
+	}
+	assert.Equal(t, []string{`Warning InvalidConfig invalid nginx config: line 2: unexpected end of file, expecting "}"`}, events)
+
+	var got appsv1.Deployment
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "my-nginx", Namespace: "default"}, &got))
+	assert.Equal(t, original.Spec, got.Spec)
+}
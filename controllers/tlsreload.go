@@ -0,0 +1,224 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+const (
+	// tlsReloadedHashAnnotation holds, on the nginx pods, the hash of the
+	// certificates nginx was last reloaded with.
+	tlsReloadedHashAnnotation = "nginx.tsuru.io/tls-reloaded-hash"
+
+	// tlsReloadRetryInterval is how long to wait for kubelet to refresh the
+	// mounted Secrets, or to retry a failed reload.
+	tlsReloadRetryInterval = 15 * time.Second
+
+	// tlsReloadExecTimeout bounds every command run in the nginx pods.
+	tlsReloadExecTimeout = 10 * time.Second
+
+	reasonTLSReloaded     = "TLSReloaded"
+	reasonTLSReloadFailed = "TLSReloadFailed"
+)
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=patch
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+
+// reconcileTLSReload reloads nginx in place, through "nginx -s reload", in the
+// pods not running the certificates of the TLS Secrets yet. It's triggered by
+// the watch on the Secrets, but kubelet refreshes the mounted Secrets with a
+// delay, so pods whose mounted certificates are still the former ones are
+// retried later. It reports whether some pod must be retried.
+//
+// Pods started after the Secrets last changed are skipped, as they already
+// run the current certificates.
+func (r *NginxReconciler) reconcileTLSReload(ctx context.Context, nginx *nginxv1beta1.Nginx) (bool, error) {
+	if nginx.Spec.TLSReloadStrategy != nginxv1beta1.TLSReloadStrategyReload || r.PodExecutor == nil {
+		return false, nil
+	}
+
+	secrets, err := r.referencedTLSSecrets(ctx, nginx)
+	if err != nil {
+		return false, err
+	}
+
+	checksums := k8s.TLSCertificateChecksums(secrets)
+	if len(checksums) == 0 {
+		return false, nil
+	}
+
+	hash := tlsChecksumsHash(checksums)
+	changedAt := lastChanged(secrets)
+
+	var pods corev1.PodList
+	err = r.Client.List(ctx, &pods, client.InNamespace(nginx.Namespace), client.MatchingLabels(k8s.LabelsForNginx(nginx.Name)))
+	if err != nil {
+		return false, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var retry bool
+	var reloaded []string
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Annotations[tlsReloadedHashAnnotation] == hash || !isNginxRunning(pod) || startedAfter(pod, changedAt) {
+			continue
+		}
+
+		mounted, err := r.mountedTLSChecksums(ctx, pod, checksums)
+		if err != nil {
+			r.EventRecorder.Eventf(nginx, corev1.EventTypeWarning, reasonTLSReloadFailed, "failed to check the certificates mounted in pod %s: %s", pod.Name, err)
+			retry = true
+			continue
+		}
+
+		if !equalChecksums(mounted, checksums) {
+			retry = true
+			continue
+		}
+
+		if err = r.reloadNginx(ctx, pod); err != nil {
+			r.EventRecorder.Eventf(nginx, corev1.EventTypeWarning, reasonTLSReloadFailed, "failed to reload nginx in pod %s: %s", pod.Name, err)
+			retry = true
+			continue
+		}
+
+		patch := client.MergeFrom(pod.DeepCopy())
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[tlsReloadedHashAnnotation] = hash
+		if err = r.Client.Patch(ctx, pod, patch); err != nil {
+			return false, fmt.Errorf("failed to patch pod %s: %w", pod.Name, err)
+		}
+
+		reloaded = append(reloaded, pod.Name)
+	}
+
+	if len(reloaded) > 0 {
+		r.EventRecorder.Eventf(nginx, corev1.EventTypeNormal, reasonTLSReloaded, "nginx reloaded with the current TLS certificates in pods: %s", strings.Join(reloaded, ", "))
+	}
+
+	return retry, nil
+}
+
+// mountedTLSChecksums returns the checksums of the certificates mounted in
+// the nginx container of the pod.
+func (r *NginxReconciler) mountedTLSChecksums(ctx context.Context, pod *corev1.Pod, expected map[string]string) (map[string]string, error) {
+	command := []string{"sha256sum"}
+	for path := range expected {
+		command = append(command, path)
+	}
+	sort.Strings(command[1:])
+
+	ctx, cancel := context.WithTimeout(ctx, tlsReloadExecTimeout)
+	defer cancel()
+
+	out, err := r.PodExecutor.Exec(ctx, pod, k8s.NginxContainerName, command...)
+	if err != nil {
+		return nil, err
+	}
+
+	checksums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			checksums[fields[1]] = fields[0]
+		}
+	}
+
+	return checksums, scanner.Err()
+}
+
+func (r *NginxReconciler) reloadNginx(ctx context.Context, pod *corev1.Pod) error {
+	ctx, cancel := context.WithTimeout(ctx, tlsReloadExecTimeout)
+	defer cancel()
+
+	_, err := r.PodExecutor.Exec(ctx, pod, k8s.NginxContainerName, "nginx", "-s", "reload")
+	return err
+}
+
+// lastChanged returns when any of the Secrets was last written, according to
+// their managed fields.
+func lastChanged(secrets []corev1.Secret) time.Time {
+	var changedAt time.Time
+	for _, secret := range secrets {
+		if secret.CreationTimestamp.After(changedAt) {
+			changedAt = secret.CreationTimestamp.Time
+		}
+
+		for _, mf := range secret.ManagedFields {
+			if mf.Time != nil && mf.Time.After(changedAt) {
+				changedAt = mf.Time.Time
+			}
+		}
+	}
+
+	return changedAt
+}
+
+// startedAfter reports whether the pod was started after the given time,
+// thus it mounted the Secrets as they were by then.
+func startedAfter(pod *corev1.Pod, t time.Time) bool {
+	return !t.IsZero() && pod.Status.StartTime != nil && pod.Status.StartTime.After(t)
+}
+
+func isNginxRunning(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning || !pod.DeletionTimestamp.IsZero() {
+		return false
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == k8s.NginxContainerName {
+			return status.State.Running != nil
+		}
+	}
+
+	return false
+}
+
+func equalChecksums(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for path, sum := range b {
+		if a[path] != sum {
+			return false
+		}
+	}
+
+	return true
+}
+
+func tlsChecksumsHash(checksums map[string]string) string {
+	paths := make([]string, 0, len(checksums))
+	for path := range checksums {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, path := range paths {
+		fmt.Fprintf(h, "%s  %s\n", checksums[path], path)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
@@ -0,0 +1,191 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+// fakePodExecutor answers sha256sum with the checksum of the certificate
+// mounted in each pod, and fails to reload nginx in the given pods.
+type fakePodExecutor struct {
+	mounted      map[string]string
+	reloadErrors map[string]error
+	reloaded     []string
+}
+
+func (e *fakePodExecutor) Exec(ctx context.Context, pod *corev1.Pod, container string, command ...string) ([]byte, error) {
+	if container != "nginx" {
+		return nil, fmt.Errorf("unexpected container %q", container)
+	}
+
+	switch command[0] {
+	case "sha256sum":
+		return []byte(fmt.Sprintf("%s  %s\n", e.mounted[pod.Name], command[1])), nil
+
+	case "nginx":
+		if err := e.reloadErrors[pod.Name]; err != nil {
+			return nil, err
+		}
+		e.reloaded = append(e.reloaded, pod.Name)
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("unexpected command %q", strings.Join(command, " "))
+}
+
+func TestNginxReconciler_reconcileTLSReload(t *testing.T) {
+	const (
+		renewedSum = "fed8fde1ff46766f2b467b41b63556974c8fb49d30313abc3efafc9e76f0cc73"
+		formerSum  = "06298432e8066b29e2223bcc23aa9504b56ae508fabf3435508869b9c3190e22"
+	)
+
+	changedAt := metav1.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "example-com",
+			Namespace:     "default",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "cert-manager", Operation: metav1.ManagedFieldsOperationUpdate, Time: &changedAt}},
+		},
+		Data: map[string][]byte{"tls.crt": []byte("renewed cert"), "tls.key": []byte("key")},
+	}
+
+	hash := tlsChecksumsHash(k8s.TLSCertificateChecksums([]corev1.Secret{*secret}))
+
+	newPod := func(name string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: k8s.LabelsForNginx("my-nginx"), Annotations: annotations},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "nginx", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				},
+			},
+		}
+	}
+
+	startedAt := func(pod *corev1.Pod, t time.Time) *corev1.Pod {
+		pod.Status.StartTime = &metav1.Time{Time: t}
+		return pod
+	}
+
+	tests := map[string]struct {
+		strategy         v1beta1.TLSReloadStrategy
+		pods             []runtime.Object
+		executor         *fakePodExecutor
+		expectedReloaded []string
+		expectedRetry    bool
+		expectedEvents   []string
+	}{
+		"without reload strategy": {
+			strategy: v1beta1.TLSReloadStrategyRollingRestart,
+			pods:     []runtime.Object{newPod("pod-1", nil)},
+			executor: &fakePodExecutor{mounted: map[string]string{"pod-1": renewedSum}},
+		},
+
+		"renewed certificates mounted": {
+			strategy:         v1beta1.TLSReloadStrategyReload,
+			pods:             []runtime.Object{newPod("pod-1", nil), newPod("pod-2", nil)},
+			executor:         &fakePodExecutor{mounted: map[string]string{"pod-1": renewedSum, "pod-2": renewedSum}},
+			expectedReloaded: []string{"pod-1", "pod-2"},
+			expectedEvents:   []string{"Normal TLSReloaded nginx reloaded with the current TLS certificates in pods: pod-1, pod-2"},
+		},
+
+		"renewed certificates not mounted yet": {
+			strategy:         v1beta1.TLSReloadStrategyReload,
+			pods:             []runtime.Object{newPod("pod-1", nil), newPod("pod-2", nil)},
+			executor:         &fakePodExecutor{mounted: map[string]string{"pod-1": renewedSum, "pod-2": formerSum}},
+			expectedReloaded: []string{"pod-1"},
+			expectedRetry:    true,
+			expectedEvents:   []string{"Normal TLSReloaded nginx reloaded with the current TLS certificates in pods: pod-1"},
+		},
+
+		"pods started after the certificates changed": {
+			strategy: v1beta1.TLSReloadStrategyReload,
+			pods:     []runtime.Object{startedAt(newPod("pod-1", nil), changedAt.Add(time.Minute))},
+			executor: &fakePodExecutor{mounted: map[string]string{"pod-1": renewedSum}},
+		},
+
+		"pods started before the certificates changed": {
+			strategy:         v1beta1.TLSReloadStrategyReload,
+			pods:             []runtime.Object{startedAt(newPod("pod-1", nil), changedAt.Add(-time.Hour))},
+			executor:         &fakePodExecutor{mounted: map[string]string{"pod-1": renewedSum}},
+			expectedReloaded: []string{"pod-1"},
+			expectedEvents:   []string{"Normal TLSReloaded nginx reloaded with the current TLS certificates in pods: pod-1"},
+		},
+
+		"certificates already reloaded": {
+			strategy: v1beta1.TLSReloadStrategyReload,
+			pods:     []runtime.Object{newPod("pod-1", map[string]string{tlsReloadedHashAnnotation: hash})},
+			executor: &fakePodExecutor{mounted: map[string]string{"pod-1": renewedSum}},
+		},
+
+		"reload failure": {
+			strategy: v1beta1.TLSReloadStrategyReload,
+			pods:     []runtime.Object{newPod("pod-1", nil)},
+			executor: &fakePodExecutor{
+				mounted:      map[string]string{"pod-1": renewedSum},
+				reloadErrors: map[string]error{"pod-1": errors.New("nginx: [emerg] cannot load certificate")},
+			},
+			expectedRetry:  true,
+			expectedEvents: []string{"Warning TLSReloadFailed failed to reload nginx in pod pod-1: nginx: [emerg] cannot load certificate"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nginx := &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+				Spec: v1beta1.NginxSpec{
+					TLS:               []v1beta1.NginxTLS{{SecretName: "example-com"}},
+					TLSReloadStrategy: tt.strategy,
+				},
+			}
+
+			c := fake.NewClientBuilder().
+				WithScheme(newScheme()).
+				WithRuntimeObjects(append(tt.pods, secret.DeepCopy())...).
+				Build()
+
+			recorder := record.NewFakeRecorder(10)
+			r := &NginxReconciler{Client: c, EventRecorder: recorder, PodExecutor: tt.executor}
+
+			retry, err := r.reconcileTLSReload(context.TODO(), nginx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRetry, retry)
+			assert.Equal(t, tt.expectedReloaded, tt.executor.reloaded)
+
+			for _, name := range tt.expectedReloaded {
+				var pod corev1.Pod
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "default"}, &pod))
+				assert.Equal(t, hash, pod.Annotations[tlsReloadedHashAnnotation])
+			}
+
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			assert.Equal(t, tt.expectedEvents, events)
+		})
+	}
+}
@@ -11,18 +11,24 @@ import (
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	ctrlzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	nginxv1alpha1 "github.com/tsuru/nginx-operator/api/v1alpha1"
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
 	"github.com/tsuru/nginx-operator/controllers"
 	"github.com/tsuru/nginx-operator/pkg/gcp"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
 	"github.com/tsuru/nginx-operator/version"
+	"github.com/tsuru/nginx-operator/webhooks"
 
 	// +kubebuilder:scaffold:imports
 
@@ -50,12 +56,20 @@ var (
 
 	namespace        = flag.String("namespace", "", "Limit the observed Nginx resources from specific namespace (empty means all namespaces)")
 	annotationFilter = flag.String("annotation-filter", "", "Filter Nginx resources via annotation using label selector semantics (default: all Nginx resources)")
+
+	enableWebhooks = flag.Bool("enable-webhooks", true, "Serve the admission and conversion webhooks for Nginx resources. It requires a TLS certificate and key in the webhook server's cert dir. The conversion webhook is required by the Nginx CRD, so only disable it when the operator runs outside the cluster.")
+	webhookPort    = flag.Int("webhook-port", 9443, "The port that the webhook server serves at.")
+
+	pruneDryRun = flag.Bool("prune-dry-run", false, "Only report (via events) the orphaned resources of Nginxes instead of deleting them.")
+	configTest  = flag.Bool("config-test", true, "Run \"nginx -t\" in a Job before updating the Deployment of a Nginx, which is only updated when the test passes.")
 )
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(nginxv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(nginxv1beta1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -82,6 +96,10 @@ func main() {
 		LeaderElectionNamespace:    *leaderElectionResourceNamespace,
 		SyncPeriod:                 syncPeriod,
 		HealthProbeBindAddress:     *healthAddr,
+		Port:                       *webhookPort,
+		// NOTE: Secrets are read only for the Nginxes reloading their TLS
+		// certificates, so they aren't worth keeping in memory.
+		ClientDisableCacheFor: []client.Object{&corev1.Secret{}},
 	})
 	if err != nil {
 		ctrl.Log.Error(err, "unable to start manager")
@@ -100,6 +118,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	podExecutor, err := k8s.NewPodExecutor(mgr.GetConfig())
+	if err != nil {
+		ctrl.Log.Error(err, "unable to create pod executor")
+		os.Exit(1)
+	}
+
 	err = (&controllers.NginxReconciler{
 		Client:           mgr.GetClient(),
 		EventRecorder:    mgr.GetEventRecorderFor("nginx-operator"),
@@ -107,11 +131,21 @@ func main() {
 		Scheme:           mgr.GetScheme(),
 		AnnotationFilter: annotationSelector,
 		GcpClient:        gcp.NewGcpClient(os.Getenv("GCP_PROJECT_ID")),
+		PruneDryRun:      *pruneDryRun,
+		ConfigTest:       *configTest,
+		PodExecutor:      podExecutor,
 	}).SetupWithManager(mgr)
 	if err != nil {
 		ctrl.Log.Error(err, "unable to create controller", "controller", "Nginx")
 		os.Exit(1)
 	}
+
+	if *enableWebhooks {
+		if err = webhooks.SetupNginxWebhookWithManager(mgr); err != nil {
+			ctrl.Log.Error(err, "unable to create webhook", "webhook", "Nginx")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -8,7 +8,8 @@ import (
 var _ GcpClient = &MockGcpClient{}
 
 type MockGcpClient struct {
-	Adresses []string
+	Adresses   []string
+	ReleaseErr error
 }
 
 func (m *MockGcpClient) EnsureIPV6(ctx context.Context, name string) error {
@@ -18,3 +19,11 @@ func (m *MockGcpClient) EnsureIPV6(ctx context.Context, name string) error {
 	m.Adresses = append(m.Adresses, name)
 	return nil
 }
+
+func (m *MockGcpClient) ReleaseIPV6(ctx context.Context, name string) error {
+	if m.ReleaseErr != nil {
+		return m.ReleaseErr
+	}
+	m.Adresses = slices.DeleteFunc(m.Adresses, func(addr string) bool { return addr == name })
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 
 type GcpClient interface {
 	EnsureIPV6(ctx context.Context, name string) error
+	ReleaseIPV6(ctx context.Context, name string) error
 }
 
 type gcpClientImpl struct {
@@ -18,8 +19,10 @@ type gcpClientImpl struct {
 }
 
 func NewGcpClient(project string) GcpClient {
-	return &gcpClientImpl{
-		project: project,
+	return &instrumentedClient{
+		GcpClient: &gcpClientImpl{
+			project: project,
+		},
 	}
 }
 
@@ -60,3 +63,24 @@ func (gc *gcpClientImpl) EnsureIPV6(ctx context.Context, name string) error {
 	}
 	return nil
 }
+
+func (gc *gcpClientImpl) ReleaseIPV6(ctx context.Context, name string) error {
+	globalAddrClient, err := gcpComputeClient.NewGlobalAddressesRESTClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer globalAddrClient.Close()
+	deleteGlobalAddressData := &computepb.DeleteGlobalAddressRequest{
+		Address: name,
+		Project: gc.project,
+	}
+	op, err := globalAddrClient.Delete(ctx, deleteGlobalAddressData)
+	if err != nil {
+		var googleApiError *googleapi.Error
+		if errors.As(err, &googleApiError) && googleApiError.Code == 404 {
+			return nil
+		}
+		return err
+	}
+	return op.Wait(ctx)
+}
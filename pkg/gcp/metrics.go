@@ -0,0 +1,58 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "nginx_operator_gcp_request_duration_seconds",
+		Help:    "Latency of the GCP API operations.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	requestErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nginx_operator_gcp_request_errors_total",
+		Help: "Number of GCP API operations which failed.",
+	}, []string{"operation"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(requestDuration, requestErrorsTotal)
+}
+
+var _ GcpClient = &instrumentedClient{}
+
+// instrumentedClient records the latency and errors of the GCP operations.
+type instrumentedClient struct {
+	GcpClient
+}
+
+func (c *instrumentedClient) EnsureIPV6(ctx context.Context, name string) error {
+	start := time.Now()
+	err := c.GcpClient.EnsureIPV6(ctx, name)
+	observeRequest("ensure_ipv6", start, err)
+	return err
+}
+
+func (c *instrumentedClient) ReleaseIPV6(ctx context.Context, name string) error {
+	start := time.Now()
+	err := c.GcpClient.ReleaseIPV6(ctx, name)
+	observeRequest("release_ipv6", start, err)
+	return err
+}
+
+func observeRequest(operation string, start time.Time, err error) {
+	requestDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		requestErrorsTotal.WithLabelValues(operation).Inc()
+	}
+}
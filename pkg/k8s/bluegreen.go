@@ -0,0 +1,60 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package k8s
+
+import (
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+const (
+	// ColorLabel tells apart the blue and green Deployments (and pods) of an
+	// Nginx, it's also set on the Services selector to the active color.
+	ColorLabel = "nginx.tsuru.io/color"
+	ColorBlue  = "blue"
+	ColorGreen = "green"
+
+	// TemplateHashAnnotation holds, on the blue/green Deployments, the hash
+	// of their pod template.
+	TemplateHashAnnotation = "nginx.tsuru.io/template-hash"
+
+	// PromotedAtAnnotation holds, on the blue/green Deployments, when the
+	// Services were switched over to them.
+	PromotedAtAnnotation = "nginx.tsuru.io/promoted-at"
+)
+
+// ColorDeploymentName returns the name of the Deployment of the given color.
+func ColorDeploymentName(n *v1beta1.Nginx, color string) string {
+	return n.Name + "-" + color
+}
+
+// OtherColor returns the color opposite to the given one.
+func OtherColor(color string) string {
+	if color == ColorBlue {
+		return ColorGreen
+	}
+	return ColorBlue
+}
+
+// NewColorDeployment creates the Deployment of the given color, running the
+// pod template of the given Deployment.
+func NewColorDeployment(n *v1beta1.Nginx, dep *appv1.Deployment, color string) (*appv1.Deployment, error) {
+	return newLabeledDeployment(dep, ColorDeploymentName(n, color), ColorLabel, color, TemplateHashAnnotation)
+}
+
+// SetServicesColor makes the Services select only the pods of the given
+// color. Services without pod selector are left as is, as otherwise they'd
+// select the pods of every Nginx of that color.
+func SetServicesColor(services []*corev1.Service, color string) {
+	for _, svc := range services {
+		if svc.Spec.Selector == nil {
+			continue
+		}
+
+		svc.Spec.Selector = mergeMap(svc.Spec.Selector, map[string]string{ColorLabel: color})
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package k8s
+
+import (
+	appv1 "k8s.io/api/apps/v1"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+const (
+	// TrackLabel tells apart the Deployments (and pods) of an Nginx which run
+	// a spec not yet promoted, e.g. the canary ones.
+	TrackLabel  = "nginx.tsuru.io/track"
+	TrackCanary = "canary"
+
+	// CanaryHashAnnotation holds, on the canary Deployment, the hash of the
+	// pod template being tested.
+	CanaryHashAnnotation = "nginx.tsuru.io/canary-hash"
+
+	// CanaryAbortedAnnotation holds, on an aborted canary Deployment, the
+	// reason why it was aborted.
+	CanaryAbortedAnnotation = "nginx.tsuru.io/canary-aborted"
+)
+
+// CanaryName returns the name of the canary Deployment of the Nginx.
+func CanaryName(n *v1beta1.Nginx) string {
+	return n.Name + "-canary"
+}
+
+// NewCanaryDeployment creates the canary Deployment running the pod template
+// of the given Deployment with the given number of replicas. Its pods keep
+// the Nginx labels, so they're selected by the same Services.
+func NewCanaryDeployment(n *v1beta1.Nginx, dep *appv1.Deployment, replicas int32) (*appv1.Deployment, error) {
+	canary, err := newLabeledDeployment(dep, CanaryName(n), TrackLabel, TrackCanary, CanaryHashAnnotation)
+	if err != nil {
+		return nil, err
+	}
+
+	canary.Spec.Replicas = &replicas
+	return canary, nil
+}
+
+// newLabeledDeployment copies the Deployment under another name, telling its
+// pods apart by the given label. The hash of the pod template is kept on the
+// given annotation.
+func newLabeledDeployment(dep *appv1.Deployment, name, label, value, hashAnnotation string) (*appv1.Deployment, error) {
+	hash, err := PodTemplateHash(&dep.Spec.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	d := dep.DeepCopy()
+	d.Name = name
+
+	// NOTE: the label keeps the selector of the copy off the pods of the
+	// Deployment it's copied from. The other way around, the selector of the
+	// Deployment (i.e. the Nginx labels) still matches the pods of the copy,
+	// such as the stable Deployment does with the canary pods. Pods are kept
+	// apart by their owner ReplicaSet, though, and the selector of a
+	// Deployment is immutable, thus it isn't narrowed either.
+	d.Labels = mergeMap(d.Labels, map[string]string{label: value})
+	d.Spec.Selector.MatchLabels = mergeMap(d.Spec.Selector.MatchLabels, map[string]string{label: value})
+	d.Spec.Template.Labels = mergeMap(d.Spec.Template.Labels, map[string]string{label: value})
+
+	if d.Annotations == nil {
+		d.Annotations = make(map[string]string)
+	}
+	d.Annotations[hashAnnotation] = hash
+
+	return d, nil
+}
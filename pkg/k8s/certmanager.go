@@ -0,0 +1,84 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package k8s
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+// CertificateGVK is the kind of the cert-manager Certificates. They are
+// handled as unstructured objects, so that cert-manager is only required
+// in the cluster when some TLS entry has an issuer.
+var CertificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// NewCertificates assembles the cert-manager Certificates of the TLS entries
+// which have an issuer. Each one is issued into the entry's Secret.
+//
+// NOTE: issuer kind and group are set explicitly, so the desired
+// Certificates can be compared with the current ones.
+func NewCertificates(nginx *v1beta1.Nginx) []*unstructured.Unstructured {
+	var certs []*unstructured.Unstructured
+	for _, t := range nginx.Spec.TLS {
+		if t.IssuerRef == nil {
+			continue
+		}
+
+		dnsNames := make([]any, 0, len(t.Hosts))
+		for _, h := range t.Hosts {
+			dnsNames = append(dnsNames, h)
+		}
+
+		cert := &unstructured.Unstructured{
+			Object: map[string]any{
+				"spec": map[string]any{
+					"secretName": t.SecretName,
+					"dnsNames":   dnsNames,
+					"issuerRef": map[string]any{
+						"name":  t.IssuerRef.Name,
+						"kind":  valueOrDefault(t.IssuerRef.Kind, "Issuer"),
+						"group": valueOrDefault(t.IssuerRef.Group, CertificateGVK.Group),
+					},
+				},
+			},
+		}
+		cert.SetGroupVersionKind(CertificateGVK)
+		cert.SetName(CertificateName(nginx, t.SecretName))
+		cert.SetNamespace(nginx.Namespace)
+		cert.SetLabels(LabelsForNginx(nginx.Name))
+		cert.SetOwnerReferences([]metav1.OwnerReference{
+			*metav1.NewControllerRef(nginx, schema.GroupVersionKind{
+				Group:   v1beta1.GroupVersion.Group,
+				Version: v1beta1.GroupVersion.Version,
+				Kind:    "Nginx",
+			}),
+		})
+		certs = append(certs, cert)
+	}
+	return certs
+}
+
+// CertificateName returns the name of the Certificate issued into the given
+// TLS Secret.
+func CertificateName(nginx *v1beta1.Nginx, secretName string) string {
+	return nginx.Name + "-" + secretName
+}
+
+// IsCertificateReady reports whether cert-manager set the Ready condition
+// of the Certificate.
+func IsCertificateReady(cert *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(cert.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if !ok || cond["type"] != "Ready" {
+			continue
+		}
+		return cond["status"] == "True"
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+func TestNewCertificates(t *testing.T) {
+	nginx := baseNginx()
+	nginx.Spec.TLS = []v1beta1.NginxTLS{
+		{SecretName: "example-com", Hosts: []string{"www.example.com", "example.com"}, IssuerRef: &v1beta1.NginxTLSIssuerRef{Name: "letsencrypt", Kind: "ClusterIssuer"}},
+		{SecretName: "hand-made"},
+		{SecretName: "example-org", Hosts: []string{"*.example.org"}, IssuerRef: &v1beta1.NginxTLSIssuerRef{Name: "my-issuer"}},
+	}
+
+	ownerRef := map[string]any{
+		"apiVersion":         "nginx.tsuru.io/v1beta1",
+		"kind":               "Nginx",
+		"name":               "my-nginx",
+		"uid":                "",
+		"controller":         true,
+		"blockOwnerDeletion": true,
+	}
+
+	labels := map[string]any{
+		"nginx.tsuru.io/app":           "nginx",
+		"nginx.tsuru.io/resource-name": "my-nginx",
+	}
+
+	assert.Equal(t, []*unstructured.Unstructured{
+		{
+			Object: map[string]any{
+				"apiVersion": "cert-manager.io/v1",
+				"kind":       "Certificate",
+				"metadata": map[string]any{
+					"name":            "my-nginx-example-com",
+					"namespace":       "default",
+					"labels":          labels,
+					"ownerReferences": []any{ownerRef},
+				},
+				"spec": map[string]any{
+					"secretName": "example-com",
+					"dnsNames":   []any{"www.example.com", "example.com"},
+					"issuerRef":  map[string]any{"name": "letsencrypt", "kind": "ClusterIssuer", "group": "cert-manager.io"},
+				},
+			},
+		},
+		{
+			Object: map[string]any{
+				"apiVersion": "cert-manager.io/v1",
+				"kind":       "Certificate",
+				"metadata": map[string]any{
+					"name":            "my-nginx-example-org",
+					"namespace":       "default",
+					"labels":          labels,
+					"ownerReferences": []any{ownerRef},
+				},
+				"spec": map[string]any{
+					"secretName": "example-org",
+					"dnsNames":   []any{"*.example.org"},
+					"issuerRef":  map[string]any{"name": "my-issuer", "kind": "Issuer", "group": "cert-manager.io"},
+				},
+			},
+		},
+	}, NewCertificates(&nginx))
+}
+
+func TestIsCertificateReady(t *testing.T) {
+	tests := map[string]struct {
+		conditions []any
+		expected   bool
+	}{
+		"without status": {},
+
+		"issuing": {
+			conditions: []any{
+				map[string]any{"type": "Issuing", "status": "True"},
+				map[string]any{"type": "Ready", "status": "False", "reason": "DoesNotExist"},
+			},
+		},
+
+		"ready": {
+			conditions: []any{
+				map[string]any{"type": "Ready", "status": "True"},
+			},
+			expected: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cert := &unstructured.Unstructured{Object: map[string]any{}}
+			if tt.conditions != nil {
+				cert.Object["status"] = map[string]any{"conditions": tt.conditions}
+			}
+			assert.Equal(t, tt.expected, IsCertificateReady(cert))
+		})
+	}
+}
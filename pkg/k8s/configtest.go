@@ -0,0 +1,111 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package k8s
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	appv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+const (
+	// ConfigTestLabel labels the Jobs testing the nginx config of a pod
+	// template, its value is the hash of the template.
+	ConfigTestLabel = "nginx.tsuru.io/config-test"
+
+	configTestDeadlineSeconds = int64(120)
+)
+
+// configTestScript runs "nginx -t", keeping its output as the termination
+// message of the container.
+const configTestScript = `nginx -t > /dev/termination-log 2>&1
+code=$?
+cat /dev/termination-log
+exit $code`
+
+// NewConfigTestJob creates a Job which runs "nginx -t" with the same image,
+// config, extra files and certificates of the given Deployment, so that its
+// config is tested before rolling it out.
+func NewConfigTestJob(n *v1beta1.Nginx, dep *appv1.Deployment) (*batchv1.Job, error) {
+	hash, err := PodTemplateHash(&dep.Spec.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	template := dep.Spec.Template.DeepCopy()
+
+	nginx := template.Spec.Containers[0]
+	nginx.Command = []string{"/bin/sh", "-c", configTestScript}
+	nginx.Ports = nil
+	nginx.ReadinessProbe = nil
+	nginx.LivenessProbe = nil
+	nginx.Lifecycle = nil
+	nginx.TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+
+	// NOTE: sidecars (e.g. the metrics exporter) would keep the Job running,
+	// and the host network isn't needed as nginx -t doesn't bind to the ports.
+	template.Spec.Containers = []corev1.Container{nginx}
+	template.Spec.HostNetwork = false
+	template.Spec.RestartPolicy = corev1.RestartPolicyNever
+
+	// NOTE: the nginx labels are left out, otherwise the test pod would be
+	// selected by the Services and disruption budget of the Nginx.
+	template.Labels = map[string]string{ConfigTestLabel: hash}
+
+	backoffLimit := int32(0)
+	deadline := configTestDeadlineSeconds
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigTestJobName(n, hash),
+			Namespace: n.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(n, v1beta1.GroupVersion.WithKind("Nginx")),
+			},
+			Labels: mergeMap(LabelsForNginx(n.Name), map[string]string{ConfigTestLabel: hash}),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &deadline,
+			Template:              *template,
+		},
+	}, nil
+}
+
+// ConfigTestJobName returns the name of the Job testing the pod template with
+// the given hash.
+func ConfigTestJobName(n *v1beta1.Nginx, hash string) string {
+	suffix := "-config-test-" + hash
+	name := n.Name
+	if len(name)+len(suffix) > validation.DNS1123LabelMaxLength {
+		name = name[:validation.DNS1123LabelMaxLength-len(suffix)]
+	}
+	return name + suffix
+}
+
+// PodTemplateHash returns a short hash of the pod template, which identifies
+// it among the templates rolled out by an Nginx.
+func PodTemplateHash(template *corev1.PodTemplateSpec) (string, error) {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal pod template: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:10], nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// PodExecutor runs commands in the containers of running pods, as "kubectl
+// exec" does.
+type PodExecutor interface {
+	// Exec runs the command in the container of the pod, returning its
+	// standard output. The error holds its standard error, if any. It gives
+	// up waiting for the command once the context is done.
+	Exec(ctx context.Context, pod *corev1.Pod, container string, command ...string) ([]byte, error)
+}
+
+type podExecutor struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+var _ PodExecutor = &podExecutor{}
+
+// NewPodExecutor returns a PodExecutor using the given API server config.
+func NewPodExecutor(config *rest.Config) (PodExecutor, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	return &podExecutor{config: config, clientset: clientset}, nil
+}
+
+func (e *podExecutor) Exec(ctx context.Context, pod *corev1.Pod, container string, command ...string) ([]byte, error) {
+	req := e.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(e.config, "POST", req.URL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to exec in pod %s: %w", pod.Name, err)
+	}
+
+	// NOTE: the stream can't be canceled with this client-go release, so a
+	// hung command is left behind rather than blocking the caller.
+	var stdout, stderr bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- executor.Stream(remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+	}()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to exec in pod %s: %w", pod.Name, ctx.Err())
+	}
+
+	if err != nil {
+		if stderr.Len() > 0 {
+			return nil, fmt.Errorf("failed to exec in pod %s: %w: %s", pod.Name, err, bytes.TrimSpace(stderr.Bytes()))
+		}
+		return nil, fmt.Errorf("failed to exec in pod %s: %w", pod.Name, err)
+	}
+
+	return stdout.Bytes(), nil
+}
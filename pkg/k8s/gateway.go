@@ -0,0 +1,146 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package k8s
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+// NewHTTPRoute assembles the HTTPRoute which attaches the Nginx's main
+// Service to the Gateways in spec.gateway. The route hostnames are the ones
+// from the TLS certificates.
+func NewHTTPRoute(nginx *v1beta1.Nginx) *gatewayv1beta1.HTTPRoute {
+	labels := LabelsForNginx(nginx.Name)
+	var annotations map[string]string
+	var parentRefs []gatewayv1beta1.ParentReference
+
+	if gw := nginx.Spec.Gateway; gw != nil {
+		labels = mergeMap(mergeMap(make(map[string]string), gw.Labels), labels)
+		if len(gw.Annotations) > 0 {
+			annotations = mergeMap(make(map[string]string), gw.Annotations)
+		}
+
+		for _, ref := range gw.ParentRefs {
+			namespace := ref.Namespace
+			if namespace == "" {
+				namespace = nginx.Namespace
+			}
+
+			parentRef := gatewayv1beta1.ParentReference{
+				Group:     groupPtr(gatewayv1beta1.GroupName),
+				Kind:      kindPtr("Gateway"),
+				Namespace: namespacePtr(gatewayv1beta1.Namespace(namespace)),
+				Name:      gatewayv1beta1.ObjectName(ref.Name),
+			}
+
+			if ref.SectionName != "" {
+				sectionName := gatewayv1beta1.SectionName(ref.SectionName)
+				parentRef.SectionName = &sectionName
+			}
+
+			parentRefs = append(parentRefs, parentRef)
+		}
+	}
+
+	pathType := gatewayv1beta1.PathMatchPathPrefix
+	port := gatewayv1beta1.PortNumber(80)
+	weight := int32(1)
+
+	return &gatewayv1beta1.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gatewayv1beta1.GroupVersion.String(),
+			Kind:       "HTTPRoute",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        nginx.Name,
+			Namespace:   nginx.Namespace,
+			Annotations: annotations,
+			Labels:      labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(nginx, schema.GroupVersionKind{
+					Group:   v1beta1.GroupVersion.Group,
+					Version: v1beta1.GroupVersion.Version,
+					Kind:    "Nginx",
+				}),
+			},
+		},
+		Spec: gatewayv1beta1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1beta1.CommonRouteSpec{
+				ParentRefs: parentRefs,
+			},
+			Hostnames: routeHostnames(nginx.Spec.TLS),
+			Rules: []gatewayv1beta1.HTTPRouteRule{
+				{
+					Matches: []gatewayv1beta1.HTTPRouteMatch{
+						{
+							Path: &gatewayv1beta1.HTTPPathMatch{
+								Type:  &pathType,
+								Value: func(s string) *string { return &s }("/"),
+							},
+						},
+					},
+					BackendRefs: []gatewayv1beta1.HTTPBackendRef{
+						{
+							BackendRef: gatewayv1beta1.BackendRef{
+								BackendObjectReference: gatewayv1beta1.BackendObjectReference{
+									Group: groupPtr(""),
+									Kind:  kindPtr("Service"),
+									Name:  gatewayv1beta1.ObjectName(NewService(nginx).Name),
+									Port:  &port,
+								},
+								Weight: &weight,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// routeHostnames returns the hosts of the TLS certificates. When any of them
+// is the "*" wildcard (the default), no hostname is returned at all since a
+// route without hostnames matches any of them.
+func routeHostnames(tls []v1beta1.NginxTLS) []gatewayv1beta1.Hostname {
+	var hostnames []gatewayv1beta1.Hostname
+	seen := make(map[string]struct{})
+	for _, t := range tls {
+		if len(t.Hosts) == 0 {
+			return nil
+		}
+
+		for _, host := range t.Hosts {
+			if host == "" || host == "*" {
+				return nil
+			}
+
+			if _, found := seen[host]; found {
+				continue
+			}
+
+			seen[host] = struct{}{}
+			hostnames = append(hostnames, gatewayv1beta1.Hostname(host))
+		}
+	}
+	return hostnames
+}
+
+func groupPtr(g string) *gatewayv1beta1.Group {
+	group := gatewayv1beta1.Group(g)
+	return &group
+}
+
+func kindPtr(k string) *gatewayv1beta1.Kind {
+	kind := gatewayv1beta1.Kind(k)
+	return &kind
+}
+
+func namespacePtr(ns gatewayv1beta1.Namespace) *gatewayv1beta1.Namespace {
+	return &ns
+}
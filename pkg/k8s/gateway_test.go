@@ -0,0 +1,151 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+func TestNewHTTPRoute(t *testing.T) {
+	nginx := baseNginx()
+	nginx.Spec.TLS = []v1beta1.NginxTLS{
+		{SecretName: "example-com-certs", Hosts: []string{"www.example.com", "example.com"}},
+		{SecretName: "example-org-certs", Hosts: []string{"www.example.com"}},
+	}
+	nginx.Spec.Gateway = &v1beta1.NginxGateway{
+		ParentRefs: []v1beta1.NginxGatewayParentRef{
+			{Name: "shared-gateway", Namespace: "gateways", SectionName: "https"},
+			{Name: "local-gateway"},
+		},
+		Annotations: map[string]string{
+			"custom.annotations.example.com/key": "value",
+		},
+		Labels: map[string]string{
+			"nginx.tsuru.io/app":            "ignored",
+			"custom.labels.example.com/key": "value",
+		},
+	}
+
+	gateway := nginx.Spec.Gateway.DeepCopy()
+
+	pathType := gatewayv1beta1.PathMatchPathPrefix
+	port := gatewayv1beta1.PortNumber(80)
+	weight := int32(1)
+
+	assert.Equal(t, &gatewayv1beta1.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "gateway.networking.k8s.io/v1beta1",
+			Kind:       "HTTPRoute",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-nginx",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"custom.annotations.example.com/key": "value",
+			},
+			Labels: map[string]string{
+				"nginx.tsuru.io/app":            "nginx",
+				"nginx.tsuru.io/resource-name":  "my-nginx",
+				"custom.labels.example.com/key": "value",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(&nginx, schema.GroupVersionKind{
+					Group:   v1beta1.GroupVersion.Group,
+					Version: v1beta1.GroupVersion.Version,
+					Kind:    "Nginx",
+				}),
+			},
+		},
+		Spec: gatewayv1beta1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1beta1.CommonRouteSpec{
+				ParentRefs: []gatewayv1beta1.ParentReference{
+					{
+						Group:       groupPtr("gateway.networking.k8s.io"),
+						Kind:        kindPtr("Gateway"),
+						Namespace:   namespacePtr("gateways"),
+						Name:        "shared-gateway",
+						SectionName: func(s gatewayv1beta1.SectionName) *gatewayv1beta1.SectionName { return &s }("https"),
+					},
+					{
+						Group:     groupPtr("gateway.networking.k8s.io"),
+						Kind:      kindPtr("Gateway"),
+						Namespace: namespacePtr("default"),
+						Name:      "local-gateway",
+					},
+				},
+			},
+			Hostnames: []gatewayv1beta1.Hostname{"www.example.com", "example.com"},
+			Rules: []gatewayv1beta1.HTTPRouteRule{
+				{
+					Matches: []gatewayv1beta1.HTTPRouteMatch{
+						{
+							Path: &gatewayv1beta1.HTTPPathMatch{
+								Type:  &pathType,
+								Value: func(s string) *string { return &s }("/"),
+							},
+						},
+					},
+					BackendRefs: []gatewayv1beta1.HTTPBackendRef{
+						{
+							BackendRef: gatewayv1beta1.BackendRef{
+								BackendObjectReference: gatewayv1beta1.BackendObjectReference{
+									Group: groupPtr(""),
+									Kind:  kindPtr("Service"),
+									Name:  "my-nginx-service",
+									Port:  &port,
+								},
+								Weight: &weight,
+							},
+						},
+					},
+				},
+			},
+		},
+	}, NewHTTPRoute(&nginx))
+	assert.Equal(t, gateway, nginx.Spec.Gateway)
+}
+
+func TestRouteHostnames(t *testing.T) {
+	tests := map[string]struct {
+		tls      []v1beta1.NginxTLS
+		expected []gatewayv1beta1.Hostname
+	}{
+		"without TLS": {},
+
+		"with duplicated hosts": {
+			tls: []v1beta1.NginxTLS{
+				{SecretName: "a", Hosts: []string{"www.example.com", "example.com"}},
+				{SecretName: "b", Hosts: []string{"example.com", "www.example.org"}},
+			},
+			expected: []gatewayv1beta1.Hostname{"www.example.com", "example.com", "www.example.org"},
+		},
+
+		"with certificate without hosts": {
+			tls: []v1beta1.NginxTLS{
+				{SecretName: "a", Hosts: []string{"www.example.com"}},
+				{SecretName: "b"},
+			},
+		},
+
+		"with wildcard host": {
+			tls: []v1beta1.NginxTLS{
+				{SecretName: "a", Hosts: []string{"www.example.com", "*"}},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, routeHostnames(tt.tls))
+		})
+	}
+}
@@ -5,16 +5,22 @@
 package k8s
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	appv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8slabels "k8s.io/apimachinery/pkg/labels"
@@ -23,9 +29,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/tsuru/nginx-operator/api/v1alpha1"
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/nginxconf"
 )
 
 const (
+	// NginxContainerName is the name of the container running nginx in the
+	// pods of the Nginx.
+	NginxContainerName = "nginx"
+
 	// Default docker image used for nginx
 	defaultNginxImage = "nginx:latest"
 
@@ -38,6 +50,7 @@ const (
 	defaultHTTPSHostNetworkPort = int32(443)
 	defaultHTTPSPortName        = "https"
 
+	// Service port names used when proxy protocol is enabled
 	defaultProxyProtocolHTTPPortName  = "proxy-http"
 	defaultProxyProtocolHTTPSPortName = "proxy-https"
 
@@ -58,9 +71,35 @@ const (
 	extraFilesMountPath = configMountPath + "/extra_files"
 
 	// Annotation key used to stored the nginx that created the deployment
-	generatedFromAnnotation = "nginx.tsuru.io/generated-from"
-
-	useHTTPSOverHTTPAnnotation = "nginx.tsuru.io/https-over-http"
+	GeneratedFromAnnotation = "nginx.tsuru.io/generated-from"
+
+	// GeneratedFromVersionAnnotation holds the API version of the spec stored
+	// in the generated-from annotation. Deployments created before v1beta1
+	// lack it, as their spec is a v1alpha1 one.
+	GeneratedFromVersionAnnotation = "nginx.tsuru.io/generated-from-version"
+
+	// LastKnownGoodAnnotation holds, on the Deployment, the Nginx spec (as the
+	// generated-from annotation does) of the last rollout which completed.
+	LastKnownGoodAnnotation = "nginx.tsuru.io/last-known-good"
+
+	// RolledBackFromAnnotation holds, on a rolled back Deployment, the hash
+	// of the pod template which failed to roll out, so it's not retried.
+	RolledBackFromAnnotation = "nginx.tsuru.io/rolled-back-from"
+
+	// ConfigHashAnnotation is the pod template annotation which holds the hash
+	// of the ConfigMaps referenced by the nginx, so changing their content
+	// rolls out new pods.
+	ConfigHashAnnotation = "nginx.tsuru.io/config-hash"
+
+	// TLSHashAnnotation is the pod template annotation which holds the hash of
+	// the TLS certificates when they are reloaded through rolling restarts.
+	TLSHashAnnotation = "nginx.tsuru.io/tls-hash"
+
+	// Names of the volumes generated by the operator
+	configVolumeName      = "nginx-config"
+	extraFilesVolumeName  = "nginx-extra-files"
+	cacheVolumeName       = "cache-vol"
+	certsVolumeNamePrefix = "nginx-certs-"
 )
 
 var nginxEntrypoint = []string{
@@ -75,12 +114,20 @@ var defaultPostStartCommand = []string{
 	"nginx -t | tee /tmp/error && touch /tmp/done",
 }
 
-// NewDeployment creates a deployment for a given Nginx resource.
-func NewDeployment(n *v1alpha1.Nginx) (*appv1.Deployment, error) {
-	n.Spec.Image = valueOrDefault(n.Spec.Image, defaultNginxImage)
-	setDefaultPorts(&n.Spec.PodTemplate)
+// SetDefaults fills the unset fields of the Nginx spec with the values the
+// operator uses to build its resources e.g. image and container ports.
+func SetDefaults(spec *v1beta1.NginxSpec) {
+	spec.Image = valueOrDefault(spec.Image, defaultNginxImage)
+	setDefaultPorts(&spec.PodTemplate)
+}
 
-	containerSecurityContext := n.Spec.PodTemplate.ContainerSecurityContext
+// NewDeployment creates a deployment for a given Nginx resource. The given
+// Nginx is not modified, defaults are applied over a copy of it.
+func NewDeployment(n *v1beta1.Nginx) (*appv1.Deployment, error) {
+	n = n.DeepCopy()
+	SetDefaults(&n.Spec)
+
+	containerSecurityContext := n.Spec.PodTemplate.ContainerSecurityContext.DeepCopy()
 
 	if hasLowPort(n.Spec.PodTemplate.Ports) {
 		if containerSecurityContext == nil {
@@ -121,8 +168,8 @@ func NewDeployment(n *v1alpha1.Nginx) (*appv1.Deployment, error) {
 			Namespace: n.Namespace,
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(n, schema.GroupVersionKind{
-					Group:   v1alpha1.GroupVersion.Group,
-					Version: v1alpha1.GroupVersion.Version,
+					Group:   v1beta1.GroupVersion.Group,
+					Version: v1beta1.GroupVersion.Version,
 					Kind:    "Nginx",
 				}),
 			},
@@ -136,7 +183,8 @@ func NewDeployment(n *v1alpha1.Nginx) (*appv1.Deployment, error) {
 					MaxSurge:       maxSurge,
 				},
 			},
-			Replicas: n.Spec.Replicas,
+			Replicas:                n.Spec.Replicas,
+			ProgressDeadlineSeconds: n.Spec.ProgressDeadlineSeconds,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: LabelsForNginx(n.Name),
 			},
@@ -151,7 +199,7 @@ func NewDeployment(n *v1alpha1.Nginx) (*appv1.Deployment, error) {
 					EnableServiceLinks: func(b bool) *bool { return &b }(false),
 					Containers: append([]corev1.Container{
 						{
-							Name:            "nginx",
+							Name:            NginxContainerName,
 							Image:           n.Spec.Image,
 							Command:         nginxEntrypoint,
 							Resources:       n.Spec.Resources,
@@ -166,21 +214,28 @@ func NewDeployment(n *v1alpha1.Nginx) (*appv1.Deployment, error) {
 					HostNetwork:                   n.Spec.PodTemplate.HostNetwork,
 					TerminationGracePeriodSeconds: n.Spec.PodTemplate.TerminationGracePeriodSeconds,
 					Volumes:                       n.Spec.PodTemplate.Volumes,
-					Tolerations:                   n.Spec.PodTemplate.Toleration,
+					Tolerations:                   n.Spec.PodTemplate.Tolerations,
 					TopologySpreadConstraints:     n.Spec.PodTemplate.TopologySpreadConstraints,
 					SecurityContext:               n.Spec.PodTemplate.PodSecurityContext,
 				},
 			},
 		},
 	}
+	config, err := renderConfig(n)
+	if err != nil {
+		return nil, err
+	}
+
 	setupProbes(n.Spec, &deployment)
-	setupConfig(n.Spec.Config, &deployment)
+	setupConfig(config, &deployment)
 	setupTLS(n.Spec.TLS, &deployment)
 	setupExtraFiles(n.Spec.ExtraFiles, &deployment)
 	setupCacheVolume(n.Spec.Cache, &deployment)
 	setupLifecycle(n.Spec.Lifecycle, &deployment)
+	setupMonitoring(n.Spec.Monitoring, &deployment)
 
-	// This is done on the last step because n.Spec may have mutated during these methods
+	// NOTE: storing the spec with defaults applied, so that it can be compared
+	// with the desired one regardless of the defaulting webhook being enabled.
 	if err := SetNginxSpec(&deployment.ObjectMeta, n.Spec); err != nil {
 		return nil, err
 	}
@@ -188,6 +243,104 @@ func NewDeployment(n *v1alpha1.Nginx) (*appv1.Deployment, error) {
 	return &deployment, nil
 }
 
+// ConfigMapNames returns the names of the ConfigMaps referenced by the nginx
+// spec, i.e. the config and the extra files ones.
+func ConfigMapNames(spec v1beta1.NginxSpec) []string {
+	var names []string
+	if spec.Config != nil && spec.Config.Kind == v1beta1.ConfigKindConfigMap && spec.Config.Name != "" {
+		names = append(names, spec.Config.Name)
+	}
+
+	if spec.ExtraFiles != nil && spec.ExtraFiles.Name != "" && !slices.Contains(names, spec.ExtraFiles.Name) {
+		names = append(names, spec.ExtraFiles.Name)
+	}
+
+	return names
+}
+
+// SetConfigMapsHash sets the hash of the given ConfigMaps' content in the pod
+// template annotations. Nothing is set when there is no ConfigMap.
+func SetConfigMapsHash(dep *appv1.Deployment, configMaps []corev1.ConfigMap) error {
+	content := make(map[string]any)
+	for _, cm := range configMaps {
+		content[cm.Name] = struct {
+			Data       map[string]string `json:"data,omitempty"`
+			BinaryData map[string][]byte `json:"binaryData,omitempty"`
+		}{cm.Data, cm.BinaryData}
+	}
+
+	return setContentHash(dep, ConfigHashAnnotation, content)
+}
+
+// TLSSecretNames returns the names of the TLS Secrets referenced by the nginx
+// spec.
+func TLSSecretNames(spec v1beta1.NginxSpec) []string {
+	var names []string
+	for _, t := range spec.TLS {
+		if t.SecretName != "" && !slices.Contains(names, t.SecretName) {
+			names = append(names, t.SecretName)
+		}
+	}
+	return names
+}
+
+// TLSCertificateChecksums returns the SHA-256 checksums of the certificates
+// in the given TLS Secrets, keyed by the path they're mounted at in the nginx
+// container.
+func TLSCertificateChecksums(secrets []corev1.Secret) map[string]string {
+	checksums := make(map[string]string)
+	for _, secret := range secrets {
+		cert, ok := secret.Data[corev1.TLSCertKey]
+		if !ok {
+			continue
+		}
+
+		sum := sha256.Sum256(cert)
+		checksums[filepath.Join(certMountPath, secret.Name, corev1.TLSCertKey)] = hex.EncodeToString(sum[:])
+	}
+	return checksums
+}
+
+// SetTLSSecretsHash sets the hash of the certificates in the given TLS
+// Secrets in the pod template annotations, when the nginx is restarted on
+// certificate renewals. Private keys are left out of the hash on purpose,
+// they are renewed along with the certificates anyway.
+func SetTLSSecretsHash(n *v1beta1.Nginx, dep *appv1.Deployment, secrets []corev1.Secret) error {
+	if n.Spec.TLSReloadStrategy != v1beta1.TLSReloadStrategyRollingRestart {
+		return nil
+	}
+
+	content := make(map[string]any)
+	for _, secret := range secrets {
+		content[secret.Name] = [][]byte{
+			secret.Data[corev1.TLSCertKey],
+			secret.Data["ca.crt"],
+		}
+	}
+
+	return setContentHash(dep, TLSHashAnnotation, content)
+}
+
+func setContentHash(dep *appv1.Deployment, annotation string, content map[string]any) error {
+	if len(content) == 0 {
+		return nil
+	}
+
+	// NOTE: maps are marshaled with sorted keys, so the hash is stable.
+	data, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+
+	if dep.Spec.Template.Annotations == nil {
+		dep.Spec.Template.Annotations = make(map[string]string)
+	}
+	dep.Spec.Template.Annotations[annotation] = hex.EncodeToString(sum[:])
+	return nil
+}
+
 func mergeMap(a, b map[string]string) map[string]string {
 	if a == nil {
 		return b
@@ -199,7 +352,32 @@ func mergeMap(a, b map[string]string) map[string]string {
 }
 
 // NewService assembles the ClusterIP service for the Nginx
-func NewService(n *v1alpha1.Nginx) *corev1.Service {
+func NewService(n *v1beta1.Nginx) *corev1.Service {
+	return newService(n, n.Name+"-service", n.Spec.Service)
+}
+
+// NewServices assembles every Service of the Nginx: the main one followed by
+// those listed in spec.services and, when scraped through a ServiceMonitor,
+// the metrics one.
+func NewServices(n *v1beta1.Nginx) []*corev1.Service {
+	services := []*corev1.Service{NewService(n)}
+	for i := range n.Spec.Services {
+		svc := &n.Spec.Services[i]
+		services = append(services, newService(n, ServiceName(n, svc.Name), &svc.NginxService))
+	}
+	if MonitorKind(n) == v1beta1.NginxMonitorServiceMonitor {
+		services = append(services, newMetricsService(n))
+	}
+	return services
+}
+
+// ServiceName returns the name of the Service object for the named Service
+// in spec.services.
+func ServiceName(n *v1beta1.Nginx, name string) string {
+	return n.Name + "-" + name
+}
+
+func newService(n *v1beta1.Nginx, name string, svc *v1beta1.NginxService) *corev1.Service {
 	annotations := map[string]string{}
 	labels := map[string]string{}
 
@@ -207,15 +385,15 @@ func NewService(n *v1alpha1.Nginx) *corev1.Service {
 	var externalTrafficPolicy corev1.ServiceExternalTrafficPolicyType
 	labelSelector := LabelsForNginx(n.Name)
 
-	if n.Spec.Service != nil {
-		labels = n.Spec.Service.Labels
+	if svc != nil {
+		labels = svc.Labels
 
-		if n.Spec.Service.Annotations != nil {
-			annotations = n.Spec.Service.Annotations
+		if svc.Annotations != nil {
+			annotations = svc.Annotations
 		}
-		lbIP = n.Spec.Service.LoadBalancerIP
-		externalTrafficPolicy = n.Spec.Service.ExternalTrafficPolicy
-		if n.Spec.Service.UsePodSelector != nil && !*n.Spec.Service.UsePodSelector {
+		lbIP = svc.LoadBalancerIP
+		externalTrafficPolicy = svc.ExternalTrafficPolicy
+		if svc.UsePodSelector != nil && !*svc.UsePodSelector {
 			labelSelector = nil
 		}
 	}
@@ -225,12 +403,12 @@ func NewService(n *v1alpha1.Nginx) *corev1.Service {
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      n.Name + "-service",
+			Name:      name,
 			Namespace: n.Namespace,
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(n, schema.GroupVersionKind{
-					Group:   v1alpha1.GroupVersion.Group,
-					Version: v1alpha1.GroupVersion.Version,
+					Group:   v1beta1.GroupVersion.Group,
+					Version: v1beta1.GroupVersion.Version,
 					Kind:    "Nginx",
 				}),
 			},
@@ -238,10 +416,10 @@ func NewService(n *v1alpha1.Nginx) *corev1.Service {
 			Annotations: annotations,
 		},
 		Spec: corev1.ServiceSpec{
-			Ports:                 fillPorts(n, nginxService(n)),
+			Ports:                 fillPorts(svc, nginxService(svc)),
 			Selector:              labelSelector,
 			LoadBalancerIP:        lbIP,
-			Type:                  nginxService(n),
+			Type:                  nginxService(svc),
 			ExternalTrafficPolicy: externalTrafficPolicy,
 		},
 	}
@@ -252,26 +430,30 @@ func NewService(n *v1alpha1.Nginx) *corev1.Service {
 	return &service
 }
 
-func fillPorts(n *v1alpha1.Nginx, t corev1.ServiceType) []corev1.ServicePort {
-	if n.Spec.PodTemplate.Ports != nil && t == corev1.ServiceTypeLoadBalancer {
+func fillPorts(svc *v1beta1.NginxService, t corev1.ServiceType) []corev1.ServicePort {
+	if svc != nil && len(svc.Ports) > 0 {
+		ports := make([]corev1.ServicePort, len(svc.Ports))
+		copy(ports, svc.Ports)
+		return ports
+	}
+
+	if svc != nil && svc.ProxyProtocol != nil && t == corev1.ServiceTypeLoadBalancer {
 		ports := make([]corev1.ServicePort, 0)
-		for _, port := range n.Spec.PodTemplate.Ports {
-			if port.Name == defaultProxyProtocolHTTPPortName {
-				ports = append(ports, corev1.ServicePort{
-					Name:       defaultProxyProtocolHTTPPortName,
-					Protocol:   corev1.ProtocolTCP,
-					TargetPort: intstr.FromString(defaultProxyProtocolHTTPPortName),
-					Port:       int32(80),
-				})
-			}
-			if port.Name == defaultProxyProtocolHTTPSPortName {
-				ports = append(ports, corev1.ServicePort{
-					Name:       defaultProxyProtocolHTTPSPortName,
-					Protocol:   corev1.ProtocolTCP,
-					TargetPort: intstr.FromString(defaultProxyProtocolHTTPSPortName),
-					Port:       int32(443),
-				})
-			}
+		if pp := svc.ProxyProtocol; pp.HTTPPort != "" {
+			ports = append(ports, corev1.ServicePort{
+				Name:       defaultProxyProtocolHTTPPortName,
+				Protocol:   corev1.ProtocolTCP,
+				TargetPort: intstr.FromString(pp.HTTPPort),
+				Port:       int32(80),
+			})
+		}
+		if pp := svc.ProxyProtocol; pp.HTTPSPort != "" {
+			ports = append(ports, corev1.ServicePort{
+				Name:       defaultProxyProtocolHTTPSPortName,
+				Protocol:   corev1.ProtocolTCP,
+				TargetPort: intstr.FromString(pp.HTTPSPort),
+				Port:       int32(443),
+			})
 		}
 		if len(ports) > 0 {
 			return ports
@@ -287,24 +469,41 @@ func fillPorts(n *v1alpha1.Nginx, t corev1.ServiceType) []corev1.ServicePort {
 		{
 			Name:       defaultHTTPSPortName,
 			Protocol:   corev1.ProtocolTCP,
-			TargetPort: fillHTTPSTargetPort(n),
+			TargetPort: fillHTTPSTargetPort(svc),
 			Port:       int32(443),
 		},
 	}
 }
 
-func fillHTTPSTargetPort(n *v1alpha1.Nginx) intstr.IntOrString {
-	if n.Spec.Service != nil && n.Spec.Service.Annotations != nil && n.Spec.Service.Annotations[useHTTPSOverHTTPAnnotation] == "true" {
+func fillHTTPSTargetPort(svc *v1beta1.NginxService) intstr.IntOrString {
+	if svc != nil && svc.HTTPSOverHTTP {
 		return intstr.FromString(defaultHTTPPortName)
 	}
 	return intstr.FromString(defaultHTTPSPortName)
 }
 
-func nginxService(n *v1alpha1.Nginx) corev1.ServiceType {
-	if n == nil || n.Spec.Service == nil {
+func nginxService(svc *v1beta1.NginxService) corev1.ServiceType {
+	if svc == nil {
 		return corev1.ServiceTypeClusterIP
 	}
-	return corev1.ServiceType(n.Spec.Service.Type)
+	return corev1.ServiceType(svc.Type)
+}
+
+// IsReservedVolumeName returns whether name is used by one of the volumes
+// generated by the operator.
+func IsReservedVolumeName(name string) bool {
+	switch name {
+	case configVolumeName, extraFilesVolumeName, cacheVolumeName, stubStatusVolumeName:
+		return true
+	}
+
+	suffix, found := strings.CutPrefix(name, certsVolumeNamePrefix)
+	if !found {
+		return false
+	}
+
+	_, err := strconv.Atoi(suffix)
+	return err == nil
 }
 
 // LabelsForNginx returns the labels for a Nginx CR with the given name
@@ -325,20 +524,38 @@ func GetNginxNameFromObject(o client.Object) string {
 }
 
 // ExtractNginxSpec extracts the nginx used to create the object
-func ExtractNginxSpec(o metav1.ObjectMeta) (v1alpha1.NginxSpec, error) {
-	ann, ok := o.Annotations[generatedFromAnnotation]
+func ExtractNginxSpec(o metav1.ObjectMeta) (v1beta1.NginxSpec, error) {
+	ann, ok := o.Annotations[GeneratedFromAnnotation]
 	if !ok {
-		return v1alpha1.NginxSpec{}, fmt.Errorf("missing %q annotation in deployment", generatedFromAnnotation)
+		return v1beta1.NginxSpec{}, fmt.Errorf("missing %q annotation in deployment", GeneratedFromAnnotation)
 	}
-	var spec v1alpha1.NginxSpec
+	if _, ok = o.Annotations[GeneratedFromVersionAnnotation]; !ok {
+		return extractLegacyNginxSpec(ann)
+	}
+	var spec v1beta1.NginxSpec
 	if err := json.Unmarshal([]byte(ann), &spec); err != nil {
-		return v1alpha1.NginxSpec{}, fmt.Errorf("failed to unmarshal nginx from annotation: %v", err)
+		return v1beta1.NginxSpec{}, fmt.Errorf("failed to unmarshal nginx from annotation: %v", err)
 	}
 	return spec, nil
 }
 
+// extractLegacyNginxSpec converts the v1alpha1 spec stored by former versions
+// of the operator, so that renamed fields (e.g. the pod tolerations) are not
+// lost when compared with the desired one.
+func extractLegacyNginxSpec(ann string) (v1beta1.NginxSpec, error) {
+	var legacy v1alpha1.Nginx
+	if err := json.Unmarshal([]byte(ann), &legacy.Spec); err != nil {
+		return v1beta1.NginxSpec{}, fmt.Errorf("failed to unmarshal nginx from annotation: %v", err)
+	}
+	var nginx v1beta1.Nginx
+	if err := legacy.ConvertTo(&nginx); err != nil {
+		return v1beta1.NginxSpec{}, fmt.Errorf("failed to convert nginx from annotation: %v", err)
+	}
+	return nginx.Spec, nil
+}
+
 // SetNginxSpec sets the nginx spec into the object annotation to be later extracted
-func SetNginxSpec(o *metav1.ObjectMeta, spec v1alpha1.NginxSpec) error {
+func SetNginxSpec(o *metav1.ObjectMeta, spec v1beta1.NginxSpec) error {
 	if o.Annotations == nil {
 		o.Annotations = make(map[string]string)
 	}
@@ -346,11 +563,12 @@ func SetNginxSpec(o *metav1.ObjectMeta, spec v1alpha1.NginxSpec) error {
 	if err != nil {
 		return err
 	}
-	o.Annotations[generatedFromAnnotation] = string(origSpec)
+	o.Annotations[GeneratedFromAnnotation] = string(origSpec)
+	o.Annotations[GeneratedFromVersionAnnotation] = v1beta1.GroupVersion.Version
 	return nil
 }
 
-func NewIngress(nginx *v1alpha1.Nginx) *networkingv1.Ingress {
+func NewIngress(nginx *v1beta1.Nginx) *networkingv1.Ingress {
 	labels := LabelsForNginx(nginx.Name)
 	if nginx.Spec.Ingress != nil {
 		labels = mergeMap(nginx.Spec.Ingress.Labels, labels)
@@ -420,8 +638,8 @@ func NewIngress(nginx *v1alpha1.Nginx) *networkingv1.Ingress {
 			Labels:      labels,
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(nginx, schema.GroupVersionKind{
-					Group:   v1alpha1.GroupVersion.Group,
-					Version: v1alpha1.GroupVersion.Version,
+					Group:   v1beta1.GroupVersion.Group,
+					Version: v1beta1.GroupVersion.Version,
 					Kind:    "Nginx",
 				}),
 			},
@@ -442,12 +660,110 @@ func NewIngress(nginx *v1alpha1.Nginx) *networkingv1.Ingress {
 	}
 }
 
-func setupConfig(conf *v1alpha1.ConfigRef, dep *appv1.Deployment) {
+// NewPodDisruptionBudget assembles the PodDisruptionBudget which protects the
+// pods of the given nginx.
+func NewPodDisruptionBudget(nginx *v1beta1.Nginx) *policyv1.PodDisruptionBudget {
+	var spec policyv1.PodDisruptionBudgetSpec
+	if nginx.Spec.DisruptionBudget != nil {
+		spec.MinAvailable = nginx.Spec.DisruptionBudget.MinAvailable
+		spec.MaxUnavailable = nginx.Spec.DisruptionBudget.MaxUnavailable
+	}
+
+	spec.Selector = &metav1.LabelSelector{
+		MatchLabels: LabelsForNginx(nginx.Name),
+	}
+
+	return &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "policy/v1",
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nginx.Name,
+			Namespace: nginx.Namespace,
+			Labels:    LabelsForNginx(nginx.Name),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(nginx, schema.GroupVersionKind{
+					Group:   v1beta1.GroupVersion.Group,
+					Version: v1beta1.GroupVersion.Version,
+					Kind:    "Nginx",
+				}),
+			},
+		},
+		Spec: spec,
+	}
+}
+
+// NewHorizontalPodAutoscaler assembles the HorizontalPodAutoscaler which
+// scales the given nginx through its scale subresource.
+func NewHorizontalPodAutoscaler(nginx *v1beta1.Nginx) *autoscalingv2.HorizontalPodAutoscaler {
+	var spec autoscalingv2.HorizontalPodAutoscalerSpec
+	if as := nginx.Spec.Autoscaling; as != nil {
+		minReplicas := int32(1)
+		if as.MinReplicas != nil {
+			minReplicas = *as.MinReplicas
+		}
+
+		spec.MinReplicas = &minReplicas
+		spec.MaxReplicas = as.MaxReplicas
+
+		if as.TargetCPUUtilizationPercentage != nil {
+			spec.Metrics = append(spec.Metrics, resourceUtilizationMetric(corev1.ResourceCPU, *as.TargetCPUUtilizationPercentage))
+		}
+
+		if as.TargetMemoryUtilizationPercentage != nil {
+			spec.Metrics = append(spec.Metrics, resourceUtilizationMetric(corev1.ResourceMemory, *as.TargetMemoryUtilizationPercentage))
+		}
+
+		spec.Metrics = append(spec.Metrics, as.Metrics...)
+	}
+
+	spec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{
+		APIVersion: v1beta1.GroupVersion.String(),
+		Kind:       "Nginx",
+		Name:       nginx.Name,
+	}
+
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "autoscaling/v2",
+			Kind:       "HorizontalPodAutoscaler",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nginx.Name,
+			Namespace: nginx.Namespace,
+			Labels:    LabelsForNginx(nginx.Name),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(nginx, schema.GroupVersionKind{
+					Group:   v1beta1.GroupVersion.Group,
+					Version: v1beta1.GroupVersion.Version,
+					Kind:    "Nginx",
+				}),
+			},
+		},
+		Spec: spec,
+	}
+}
+
+func resourceUtilizationMetric(name corev1.ResourceName, utilization int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: name,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: &utilization,
+			},
+		},
+	}
+}
+
+func setupConfig(conf *v1beta1.ConfigRef, dep *appv1.Deployment) {
 	if conf == nil {
 		return
 	}
 
-	volumeName := "nginx-config"
+	volumeName := configVolumeName
 
 	dep.Spec.Template.Spec.Containers[0].VolumeMounts = append(dep.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
 		Name:      volumeName,
@@ -457,7 +773,7 @@ func setupConfig(conf *v1alpha1.ConfigRef, dep *appv1.Deployment) {
 	})
 
 	switch conf.Kind {
-	case v1alpha1.ConfigKindConfigMap:
+	case v1beta1.ConfigKindConfigMap:
 		dep.Spec.Template.Spec.Volumes = append(dep.Spec.Template.Spec.Volumes, corev1.Volume{
 			Name: volumeName,
 			VolumeSource: corev1.VolumeSource{
@@ -470,7 +786,7 @@ func setupConfig(conf *v1alpha1.ConfigRef, dep *appv1.Deployment) {
 			},
 		})
 
-	case v1alpha1.ConfigKindInline:
+	case v1beta1.ConfigKindInline:
 		if dep.Spec.Template.Annotations == nil {
 			dep.Spec.Template.Annotations = make(map[string]string)
 		}
@@ -496,10 +812,87 @@ func setupConfig(conf *v1alpha1.ConfigRef, dep *appv1.Deployment) {
 	}
 }
 
+// renderConfig renders the configs generated by the operator, which are then
+// setup the same way as the Inline ones.
+func renderConfig(n *v1beta1.Nginx) (*v1beta1.ConfigRef, error) {
+	if n.Spec.Config == nil {
+		return nil, nil
+	}
+
+	switch n.Spec.Config.Kind {
+	case v1beta1.ConfigKindTemplate:
+		rendered, err := RenderConfigTemplate(n)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render config template: %w", err)
+		}
+
+		return &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindInline, Value: rendered}, nil
+
+	case v1beta1.ConfigKindStructured:
+		rendered, err := RenderStructuredConfig(n)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render structured config: %w", err)
+		}
+
+		return &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindInline, Value: rendered}, nil
+	}
+
+	return n.Spec.Config, nil
+}
+
+// ValidateConfig checks the nginx.conf of the Nginx, either rendered from its
+// spec or read from the given ConfigMaps, e.g. for unbalanced braces and
+// certificates not mounted from spec.tls.
+func ValidateConfig(n *v1beta1.Nginx, configMaps []corev1.ConfigMap) error {
+	if n.Spec.Config == nil {
+		return nil
+	}
+
+	var config string
+	switch n.Spec.Config.Kind {
+	case v1beta1.ConfigKindConfigMap:
+		var found bool
+		for _, cm := range configMaps {
+			if cm.Name == n.Spec.Config.Name {
+				config, found = cm.Data[configFileName]
+			}
+		}
+
+		// NOTE: nothing to check until the ConfigMap is created.
+		if !found {
+			return nil
+		}
+
+	default:
+		rendered, err := renderConfig(n)
+		if err != nil {
+			return err
+		}
+
+		config = rendered.Value
+	}
+
+	opts := nginxconf.Options{
+		Prefix:          configMountPath,
+		CertificatesDir: certMountPath,
+		TLSSecrets:      TLSSecretNames(n.Spec),
+		ExtraFilesDir:   extraFilesMountPath,
+	}
+
+	if n.Spec.ExtraFiles != nil && len(n.Spec.ExtraFiles.Files) > 0 {
+		opts.ExtraFiles = []string{}
+		for _, path := range n.Spec.ExtraFiles.Files {
+			opts.ExtraFiles = append(opts.ExtraFiles, path)
+		}
+	}
+
+	return nginxconf.Validate(config, opts)
+}
+
 // setupTLS configures the Secret volumes and attaches them in the nginx container.
-func setupTLS(tls []v1alpha1.NginxTLS, dep *appv1.Deployment) {
+func setupTLS(tls []v1beta1.NginxTLS, dep *appv1.Deployment) {
 	for index, t := range tls {
-		volumeName := fmt.Sprintf("nginx-certs-%d", index)
+		volumeName := fmt.Sprintf("%s%d", certsVolumeNamePrefix, index)
 
 		dep.Spec.Template.Spec.Volumes = append(dep.Spec.Template.Spec.Volumes, corev1.Volume{
 			Name: volumeName,
@@ -520,11 +913,11 @@ func setupTLS(tls []v1alpha1.NginxTLS, dep *appv1.Deployment) {
 }
 
 // setupExtraFiles configures the volume source and mount into Deployment resource.
-func setupExtraFiles(fRef *v1alpha1.FilesRef, dep *appv1.Deployment) {
+func setupExtraFiles(fRef *v1beta1.FilesRef, dep *appv1.Deployment) {
 	if fRef == nil {
 		return
 	}
-	volumeMountName := "nginx-extra-files"
+	volumeMountName := extraFilesVolumeName
 	dep.Spec.Template.Spec.Containers[0].VolumeMounts = append(dep.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
 		Name:      volumeMountName,
 		MountPath: extraFilesMountPath,
@@ -562,17 +955,16 @@ func valueOrDefault(value, def string) string {
 	return def
 }
 
-func setupCacheVolume(cache v1alpha1.NginxCacheSpec, dep *appv1.Deployment) {
+func setupCacheVolume(cache v1beta1.NginxCacheSpec, dep *appv1.Deployment) {
 	if cache.Path == "" {
 		return
 	}
-	const cacheVolName = "cache-vol"
 	medium := corev1.StorageMediumDefault
 	if cache.InMemory {
 		medium = corev1.StorageMediumMemory
 	}
 	cacheVolume := corev1.Volume{
-		Name: cacheVolName,
+		Name: cacheVolumeName,
 		VolumeSource: corev1.VolumeSource{
 			EmptyDir: &corev1.EmptyDirVolumeSource{
 				Medium: medium,
@@ -589,12 +981,12 @@ func setupCacheVolume(cache v1alpha1.NginxCacheSpec, dep *appv1.Deployment) {
 	}
 	dep.Spec.Template.Spec.Volumes = append(dep.Spec.Template.Spec.Volumes, cacheVolume)
 	dep.Spec.Template.Spec.Containers[0].VolumeMounts = append(dep.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
-		Name:      cacheVolName,
+		Name:      cacheVolumeName,
 		MountPath: cache.Path,
 	})
 }
 
-func setupLifecycle(lifecycle *v1alpha1.NginxLifecycle, dep *appv1.Deployment) {
+func setupLifecycle(lifecycle *v1beta1.NginxLifecycle, dep *appv1.Deployment) {
 	defaultLifecycle := corev1.Lifecycle{
 		PostStart: &corev1.LifecycleHandler{
 			Exec: &corev1.ExecAction{
@@ -637,7 +1029,7 @@ func portByName(ports []corev1.ContainerPort, name string) *corev1.ContainerPort
 	return nil
 }
 
-func setDefaultPorts(podSpec *v1alpha1.NginxPodTemplateSpec) {
+func setDefaultPorts(podSpec *v1beta1.NginxPodTemplateSpec) {
 	if portByName(podSpec.Ports, defaultHTTPPortName) == nil {
 		httpPort := defaultHTTPPort
 		if podSpec.HostNetwork {
@@ -663,7 +1055,7 @@ func setDefaultPorts(podSpec *v1alpha1.NginxPodTemplateSpec) {
 	}
 }
 
-func setupProbes(nginxSpec v1alpha1.NginxSpec, dep *appv1.Deployment) {
+func setupProbes(nginxSpec v1beta1.NginxSpec, dep *appv1.Deployment) {
 	httpPort := portByName(nginxSpec.PodTemplate.Ports, defaultHTTPPortName)
 	cmdTimeoutSec := int32(1)
 
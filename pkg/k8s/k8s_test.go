@@ -7,43 +7,46 @@ package k8s
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
-	"github.com/tsuru/nginx-operator/api/v1alpha1"
+	"github.com/tsuru/nginx-operator/api/v1beta1"
 )
 
-func baseNginx() v1alpha1.Nginx {
-	return v1alpha1.Nginx{
+func baseNginx() v1beta1.Nginx {
+	return v1beta1.Nginx{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "my-nginx",
 			Namespace: "default",
 		},
-		Spec:   v1alpha1.NginxSpec{},
-		Status: v1alpha1.NginxStatus{},
+		Spec:   v1beta1.NginxSpec{},
+		Status: v1beta1.NginxStatus{},
 	}
 }
 
-func nginxWithService() v1alpha1.Nginx {
+func nginxWithService() v1beta1.Nginx {
 	n := baseNginx()
-	n.Spec.Service = &v1alpha1.NginxService{
+	n.Spec.Service = &v1beta1.NginxService{
 		Type: "LoadBalancer",
 	}
 	return n
 }
 
-func nginxWithCertificate() v1alpha1.Nginx {
+func nginxWithCertificate() v1beta1.Nginx {
 	n := baseNginx()
-	n.Spec.TLS = []v1alpha1.NginxTLS{{SecretName: "my-secret"}}
+	n.Spec.TLS = []v1beta1.NginxTLS{{SecretName: "my-secret"}}
 	return n
 }
 
@@ -127,13 +130,13 @@ func baseDeployment() appv1.Deployment {
 func Test_NewDeployment(t *testing.T) {
 	tests := []struct {
 		name       string
-		nginxFn    func(n v1alpha1.Nginx) v1alpha1.Nginx
+		nginxFn    func(n v1beta1.Nginx) v1beta1.Nginx
 		deployFn   func(d appv1.Deployment) appv1.Deployment
 		teardownFn func()
 	}{
 		{
 			name: "empty-spec",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				return n
 			},
 			deployFn: func(d appv1.Deployment) appv1.Deployment {
@@ -142,7 +145,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "multiple-replicas",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				v := int32(3)
 				n.Spec.Replicas = &v
 				return n
@@ -155,7 +158,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "zero-replicas",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				v := int32(0)
 				n.Spec.Replicas = &v
 				return n
@@ -168,7 +171,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "custom-image",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.Image = "tsuru/nginx:latest"
 				return n
 			},
@@ -179,7 +182,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "pod-security-context",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.PodSecurityContext = &corev1.PodSecurityContext{
 					Sysctls: []corev1.Sysctl{
 						{
@@ -204,9 +207,9 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-config-configmap",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
-				n.Spec.Config = &v1alpha1.ConfigRef{
-					Kind: v1alpha1.ConfigKindConfigMap,
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
+				n.Spec.Config = &v1beta1.ConfigRef{
+					Kind: v1beta1.ConfigKindConfigMap,
 					Name: "config-map-xpto",
 				}
 				return n
@@ -238,9 +241,9 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-config-inline",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
-				n.Spec.Config = &v1alpha1.ConfigRef{
-					Kind:  v1alpha1.ConfigKindInline,
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
+				n.Spec.Config = &v1beta1.ConfigRef{
+					Kind:  v1beta1.ConfigKindInline,
 					Value: "server {}",
 				}
 				return n
@@ -279,8 +282,8 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-tls",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
-				n.Spec.TLS = []v1alpha1.NginxTLS{{SecretName: "my-secret"}}
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
+				n.Spec.TLS = []v1beta1.NginxTLS{{SecretName: "my-secret"}}
 				return n
 			},
 			deployFn: func(d appv1.Deployment) appv1.Deployment {
@@ -327,8 +330,8 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-two-certificates",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
-				n.Spec.TLS = []v1alpha1.NginxTLS{
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
+				n.Spec.TLS = []v1beta1.NginxTLS{
 					{SecretName: "rsa-cert"},
 					{SecretName: "ecdsa-cert"},
 				}
@@ -392,7 +395,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-affinity",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.Affinity = &corev1.Affinity{
 					NodeAffinity: &corev1.NodeAffinity{
 						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
@@ -423,7 +426,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-node-selector",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.NodeSelector = map[string]string{
 					"diskType": "ssd",
 				}
@@ -438,7 +441,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-host-network",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.HostNetwork = true
 				return n
 			},
@@ -479,8 +482,8 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-toleration",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
-				n.Spec.PodTemplate.Toleration = []corev1.Toleration{
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
+				n.Spec.PodTemplate.Tolerations = []corev1.Toleration{
 					{Key: "rpaas-node", Operator: corev1.TolerationOpEqual, Value: "rpaas-only", Effect: corev1.TaintEffectNoSchedule},
 				}
 				return n
@@ -494,7 +497,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-topology-spread-constraints",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{
 					{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}}},
 				}
@@ -509,7 +512,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-host-network-zero-replicas",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				zero := int32(0)
 				n.Spec.Replicas = &zero
 				n.Spec.PodTemplate.HostNetwork = true
@@ -554,9 +557,9 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-tls-and-host-network",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.HostNetwork = true
-				n.Spec.TLS = []v1alpha1.NginxTLS{
+				n.Spec.TLS = []v1beta1.NginxTLS{
 					{SecretName: "my-secret"},
 				}
 				return n
@@ -616,7 +619,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-security-context",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.ContainerSecurityContext = &corev1.SecurityContext{
 					RunAsUser:  new(int64),
 					RunAsGroup: new(int64),
@@ -633,8 +636,8 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-security-context-and-host-network",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
-				n.Spec.PodTemplate = v1alpha1.NginxPodTemplateSpec{
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
+				n.Spec.PodTemplate = v1beta1.NginxPodTemplateSpec{
 					HostNetwork: true,
 				}
 				n.Spec.PodTemplate.ContainerSecurityContext = &corev1.SecurityContext{
@@ -691,7 +694,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-resources",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.Resources = corev1.ResourceRequirements{
 					Limits: corev1.ResourceList(map[corev1.ResourceName]resource.Quantity{
 						corev1.ResourceMemory: *resource.NewQuantity(int64(100), resource.DecimalSI),
@@ -710,8 +713,8 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-extra-files",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
-				n.Spec.ExtraFiles = &v1alpha1.FilesRef{
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
+				n.Spec.ExtraFiles = &v1beta1.FilesRef{
 					Name: "my-extra-files-in-configmap",
 				}
 				return n
@@ -736,8 +739,8 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-extra-files-and-files-mounted-on-custom-subdirs",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
-				n.Spec.ExtraFiles = &v1alpha1.FilesRef{
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
+				n.Spec.ExtraFiles = &v1beta1.FilesRef{
 					Name: "my-extra-files-in-configmap",
 					Files: map[string]string{
 						"www_index.html":     "www/index.html",
@@ -781,7 +784,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "when adding extra labels to PodTemplate",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.Labels = map[string]string{
 					"some-custom-label": "label-value",
 					"project":           "z",
@@ -798,7 +801,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "when adding extra annotations to PodTemplate",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.Annotations = map[string]string{
 					"tsuru.io/pool":        "some-pool",
 					"tsuru.io/another-key": "another-value",
@@ -815,8 +818,8 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-cache",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
-				n.Spec.Cache = v1alpha1.NginxCacheSpec{
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
+				n.Spec.Cache = v1beta1.NginxCacheSpec{
 					Path: "/var/cache",
 				}
 				return n
@@ -837,10 +840,10 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-cache-memory-size",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				q, err := resource.ParseQuantity("10Mi")
 				require.NoError(t, err)
-				n.Spec.Cache = v1alpha1.NginxCacheSpec{
+				n.Spec.Cache = v1beta1.NginxCacheSpec{
 					InMemory: true,
 					Path:     "/var/cache",
 					Size:     &q,
@@ -867,9 +870,9 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-lifecycle-pre-stop",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
-				n.Spec.Lifecycle = &v1alpha1.NginxLifecycle{
-					PreStop: &v1alpha1.NginxLifecycleHandler{
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
+				n.Spec.Lifecycle = &v1beta1.NginxLifecycle{
+					PreStop: &v1beta1.NginxLifecycleHandler{
 						Exec: &corev1.ExecAction{
 							Command: []string{
 								"echo",
@@ -905,9 +908,9 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-lifecycle-poststart-exec",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
-				n.Spec.Lifecycle = &v1alpha1.NginxLifecycle{
-					PreStop: &v1alpha1.NginxLifecycleHandler{
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
+				n.Spec.Lifecycle = &v1beta1.NginxLifecycle{
+					PreStop: &v1beta1.NginxLifecycleHandler{
 						Exec: &corev1.ExecAction{
 							Command: []string{
 								"echo",
@@ -915,7 +918,7 @@ func Test_NewDeployment(t *testing.T) {
 							},
 						},
 					},
-					PostStart: &v1alpha1.NginxLifecycleHandler{
+					PostStart: &v1beta1.NginxLifecycleHandler{
 						Exec: &corev1.ExecAction{
 							Command: []string{
 								"echo",
@@ -951,9 +954,9 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-lifecycle-poststart-exec-empty-command",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
-				n.Spec.Lifecycle = &v1alpha1.NginxLifecycle{
-					PostStart: &v1alpha1.NginxLifecycleHandler{
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
+				n.Spec.Lifecycle = &v1beta1.NginxLifecycle{
+					PostStart: &v1beta1.NginxLifecycleHandler{
 						Exec: &corev1.ExecAction{
 							Command: []string{},
 						},
@@ -978,7 +981,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with custom termination graceful period",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.TerminationGracePeriodSeconds = func(n int64) *int64 { return &n }(int64(60 * 2))
 				return n
 			},
@@ -989,7 +992,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with custom ports",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.Ports = []corev1.ContainerPort{
 					{
 						Name:          "http",
@@ -1030,7 +1033,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with low port",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.HostNetwork = true
 				n.Spec.PodTemplate.Ports = []corev1.ContainerPort{
 					{
@@ -1083,7 +1086,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with host network and custom ports",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.HostNetwork = true
 				n.Spec.PodTemplate.Ports = []corev1.ContainerPort{
 					{
@@ -1129,7 +1132,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with tls and custom ports",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.Ports = []corev1.ContainerPort{
 					{
 						Name:          "http",
@@ -1142,7 +1145,7 @@ func Test_NewDeployment(t *testing.T) {
 						Protocol:      corev1.ProtocolTCP,
 					},
 				}
-				n.Spec.TLS = []v1alpha1.NginxTLS{
+				n.Spec.TLS = []v1beta1.NginxTLS{
 					{SecretName: "my-secret"},
 				}
 				return n
@@ -1191,7 +1194,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with volumes",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.Volumes = []corev1.Volume{
 					{
 						Name: "test",
@@ -1229,7 +1232,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with initContainers",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.InitContainers = []corev1.Container{
 					{
 						Name:  "preheat-cache",
@@ -1251,7 +1254,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with rolling update params",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.RollingUpdate = &appv1.RollingUpdateDeployment{
 					MaxSurge: &intstr.IntOrString{
 						Type:   intstr.String,
@@ -1283,7 +1286,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with-custom-service-account",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.ServiceAccountName = "custom"
 				return n
 			},
@@ -1294,7 +1297,7 @@ func Test_NewDeployment(t *testing.T) {
 		},
 		{
 			name: "with sidecar containers",
-			nginxFn: func(n v1alpha1.Nginx) v1alpha1.Nginx {
+			nginxFn: func(n v1beta1.Nginx) v1beta1.Nginx {
 				n.Spec.PodTemplate.Containers = []corev1.Container{
 					{
 						Name:  "sidecar",
@@ -1319,16 +1322,20 @@ func Test_NewDeployment(t *testing.T) {
 			want := tt.deployFn(baseDeployment())
 			want.OwnerReferences = []metav1.OwnerReference{
 				*metav1.NewControllerRef(&nginx, schema.GroupVersionKind{
-					Group:   v1alpha1.GroupVersion.Group,
-					Version: v1alpha1.GroupVersion.Version,
+					Group:   v1beta1.GroupVersion.Group,
+					Version: v1beta1.GroupVersion.Version,
 					Kind:    "Nginx",
 				}),
 			}
+			original := nginx.DeepCopy()
 			dep, err := NewDeployment(&nginx)
 			assert.NoError(t, err)
+			assert.Equal(t, original, &nginx, "NewDeployment must not modify the given Nginx")
+			SetDefaults(&nginx.Spec)
 			spec, err := json.Marshal(nginx.Spec)
 			assert.NoError(t, err)
-			want.Annotations[generatedFromAnnotation] = string(spec)
+			want.Annotations[GeneratedFromAnnotation] = string(spec)
+			want.Annotations[GeneratedFromVersionAnnotation] = "v1beta1"
 			assertDeployment(t, &want, dep)
 			if tt.teardownFn != nil {
 				tt.teardownFn()
@@ -1347,7 +1354,7 @@ func assertDeployment(t *testing.T, want, got *appv1.Deployment) {
 func TestNewService(t *testing.T) {
 	tests := []struct {
 		name  string
-		nginx v1alpha1.Nginx
+		nginx v1beta1.Nginx
 		want  *corev1.Service
 	}{
 		{
@@ -1472,7 +1479,7 @@ func TestNewService(t *testing.T) {
 		},
 		{
 			name: "with-service-extra",
-			nginx: func() v1alpha1.Nginx {
+			nginx: func() v1beta1.Nginx {
 				n := nginxWithService()
 				n.Spec.Service.LoadBalancerIP = "10.0.0.1"
 				n.Spec.Service.Labels = map[string]string{
@@ -1527,7 +1534,7 @@ func TestNewService(t *testing.T) {
 		},
 		{
 			name: "using Local externalTrafficPolicy",
-			nginx: func() v1alpha1.Nginx {
+			nginx: func() v1beta1.Nginx {
 				n := nginxWithService()
 				n.Spec.Service.Type = corev1.ServiceTypeLoadBalancer
 				n.Spec.Service.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeLocal
@@ -1573,7 +1580,7 @@ func TestNewService(t *testing.T) {
 		},
 		{
 			name: "without pod selector",
-			nginx: func() v1alpha1.Nginx {
+			nginx: func() v1beta1.Nginx {
 				n := nginxWithService()
 				n.Spec.Service.UsePodSelector = func(b bool) *bool { return &b }(false)
 				return n
@@ -1613,15 +1620,19 @@ func TestNewService(t *testing.T) {
 		},
 		{
 			name: "using proxy protocol with LB svc type",
-			nginx: func() v1alpha1.Nginx {
+			nginx: func() v1beta1.Nginx {
 				n := nginxWithService()
 				n.Spec.Service.Type = corev1.ServiceTypeLoadBalancer
+				n.Spec.Service.ProxyProtocol = &v1beta1.NginxProxyProtocol{
+					HTTPPort:  "my-proxy-http",
+					HTTPSPort: "my-proxy-https",
+				}
 				n.Spec.PodTemplate.Ports = []corev1.ContainerPort{
 					{
-						Name: defaultProxyProtocolHTTPPortName,
+						Name: "my-proxy-http",
 					},
 					{
-						Name: defaultProxyProtocolHTTPSPortName,
+						Name: "my-proxy-https",
 					},
 				}
 				return n
@@ -1645,13 +1656,13 @@ func TestNewService(t *testing.T) {
 						{
 							Name:       "proxy-http",
 							Protocol:   corev1.ProtocolTCP,
-							TargetPort: intstr.FromString("proxy-http"),
+							TargetPort: intstr.FromString("my-proxy-http"),
 							Port:       int32(80),
 						},
 						{
 							Name:       "proxy-https",
 							Protocol:   corev1.ProtocolTCP,
-							TargetPort: intstr.FromString("proxy-https"),
+							TargetPort: intstr.FromString("my-proxy-https"),
 							Port:       int32(443),
 						},
 					},
@@ -1665,7 +1676,7 @@ func TestNewService(t *testing.T) {
 		},
 		{
 			name: "using default ports with clusterIP svc type",
-			nginx: func() v1alpha1.Nginx {
+			nginx: func() v1beta1.Nginx {
 				n := nginxWithService()
 				n.Spec.Service.Type = corev1.ServiceTypeClusterIP
 				n.Spec.PodTemplate.Ports = []corev1.ContainerPort{
@@ -1715,13 +1726,65 @@ func TestNewService(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "proxy protocol port names without proxy protocol enabled",
+			nginx: func() v1beta1.Nginx {
+				n := nginxWithService()
+				n.Spec.Service.Type = corev1.ServiceTypeLoadBalancer
+				n.Spec.PodTemplate.Ports = []corev1.ContainerPort{
+					{
+						Name: defaultProxyProtocolHTTPPortName,
+					},
+					{
+						Name: defaultProxyProtocolHTTPSPortName,
+					},
+				}
+				return n
+			}(),
+			want: &corev1.Service{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Service",
+					APIVersion: "v1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-nginx-service",
+					Namespace: "default",
+					Labels: map[string]string{
+						"nginx.tsuru.io/resource-name": "my-nginx",
+						"nginx.tsuru.io/app":           "nginx",
+					},
+					Annotations: map[string]string{},
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{
+							Name:       "http",
+							Protocol:   corev1.ProtocolTCP,
+							TargetPort: intstr.FromString("http"),
+							Port:       int32(80),
+						},
+						{
+							Name:       "https",
+							Protocol:   corev1.ProtocolTCP,
+							TargetPort: intstr.FromString("https"),
+							Port:       int32(443),
+						},
+					},
+					Selector: map[string]string{
+						"nginx.tsuru.io/resource-name": "my-nginx",
+						"nginx.tsuru.io/app":           "nginx",
+					},
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.want.OwnerReferences = []metav1.OwnerReference{
 				*metav1.NewControllerRef(&tt.nginx, schema.GroupVersionKind{
-					Group:   v1alpha1.GroupVersion.Group,
-					Version: v1alpha1.GroupVersion.Version,
+					Group:   v1beta1.GroupVersion.Group,
+					Version: v1beta1.GroupVersion.Version,
 					Kind:    "Nginx",
 				}),
 			}
@@ -1730,8 +1793,47 @@ func TestNewService(t *testing.T) {
 	}
 }
 
+func TestNewServices(t *testing.T) {
+	n := baseNginx()
+	n.Spec.Service = &v1beta1.NginxService{Type: corev1.ServiceTypeLoadBalancer}
+	n.Spec.Services = []v1beta1.NginxNamedService{
+		{
+			Name: "internal",
+			NginxService: v1beta1.NginxService{
+				Type:          corev1.ServiceTypeLoadBalancer,
+				Labels:        map[string]string{"team": "platform"},
+				HTTPSOverHTTP: true,
+			},
+		},
+		{
+			Name: "metrics",
+			NginxService: v1beta1.NginxService{
+				Ports: []corev1.ServicePort{{Name: "metrics", Port: 9113, TargetPort: intstr.FromString("metrics")}},
+			},
+		},
+	}
+
+	services := NewServices(&n)
+	require.Len(t, services, 3)
+
+	assert.Equal(t, NewService(&n), services[0])
+
+	assert.Equal(t, "my-nginx-internal", services[1].Name)
+	assert.Equal(t, corev1.ServiceTypeLoadBalancer, services[1].Spec.Type)
+	assert.Equal(t, map[string]string{
+		"nginx.tsuru.io/resource-name": "my-nginx",
+		"nginx.tsuru.io/app":           "nginx",
+		"team":                         "platform",
+	}, services[1].Labels)
+	assert.Equal(t, intstr.FromString("http"), services[1].Spec.Ports[1].TargetPort)
+	assert.Equal(t, LabelsForNginx("my-nginx"), services[1].Spec.Selector)
+
+	assert.Equal(t, "my-nginx-metrics", services[2].Name)
+	assert.Equal(t, []corev1.ServicePort{{Name: "metrics", Port: 9113, TargetPort: intstr.FromString("metrics")}}, services[2].Spec.Ports)
+}
+
 func TestExtractNginxSpec(t *testing.T) {
-	mustMarshal := func(t *testing.T, n v1alpha1.NginxSpec) string {
+	mustMarshal := func(t *testing.T, n v1beta1.NginxSpec) string {
 		data, err := json.Marshal(n)
 		assert.Nil(t, err)
 		return string(data)
@@ -1739,22 +1841,42 @@ func TestExtractNginxSpec(t *testing.T) {
 	tests := []struct {
 		name        string
 		annotations map[string]string
-		want        v1alpha1.NginxSpec
+		want        v1beta1.NginxSpec
 		wantedErr   string
 	}{
 		{
 			name:      "missing-annotation",
-			want:      v1alpha1.NginxSpec{},
+			want:      v1beta1.NginxSpec{},
 			wantedErr: `missing "nginx.tsuru.io/generated-from" annotation in deployment`,
 		},
 		{
 			name: "default",
 			annotations: map[string]string{
-				generatedFromAnnotation: mustMarshal(t, v1alpha1.NginxSpec{
+				GeneratedFromAnnotation: mustMarshal(t, v1beta1.NginxSpec{
 					Image: "custom-image",
 				}),
+				GeneratedFromVersionAnnotation: "v1beta1",
+			},
+			want:      v1beta1.NginxSpec{Image: "custom-image"},
+			wantedErr: "",
+		},
+		{
+			name: "legacy-v1alpha1-spec",
+			annotations: map[string]string{
+				GeneratedFromAnnotation: `{"image":"custom-image","podTemplate":{"ports":[{"name":"proxy-http","containerPort":8080}],"toleration":[{"key":"dedicated","operator":"Exists"}]},"service":{"type":"LoadBalancer","annotations":{"nginx.tsuru.io/https-over-http":"true"}}}`,
+			},
+			want: v1beta1.NginxSpec{
+				Image: "custom-image",
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					Ports:       []corev1.ContainerPort{{Name: "proxy-http", ContainerPort: 8080}},
+					Tolerations: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+				},
+				Service: &v1beta1.NginxService{
+					Type:          corev1.ServiceTypeLoadBalancer,
+					HTTPSOverHTTP: true,
+					ProxyProtocol: &v1beta1.NginxProxyProtocol{HTTPPort: "proxy-http"},
+				},
 			},
-			want:      v1alpha1.NginxSpec{Image: "custom-image"},
 			wantedErr: "",
 		},
 	}
@@ -1772,15 +1894,33 @@ func TestExtractNginxSpec(t *testing.T) {
 	}
 }
 
+func TestIsReservedVolumeName(t *testing.T) {
+	tests := map[string]bool{
+		"nginx-config":      true,
+		"nginx-extra-files": true,
+		"cache-vol":         true,
+		"nginx-certs-0":     true,
+		"nginx-certs-12":    true,
+		"nginx-certs-":      false,
+		"nginx-certs-foo":   false,
+		"my-volume":         false,
+	}
+	for name, expected := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, expected, IsReservedVolumeName(name))
+		})
+	}
+}
+
 func TestNewIngress(t *testing.T) {
 	nginx := baseNginx()
 
 	tests := map[string]struct {
-		nginx func() v1alpha1.Nginx
+		nginx func() v1beta1.Nginx
 		want  *networkingv1.Ingress
 	}{
 		"nginx resource without ingress spec": {
-			nginx: func() v1alpha1.Nginx {
+			nginx: func() v1beta1.Nginx {
 				return baseNginx()
 			},
 			want: &networkingv1.Ingress{
@@ -1797,8 +1937,8 @@ func TestNewIngress(t *testing.T) {
 					},
 					OwnerReferences: []metav1.OwnerReference{
 						*metav1.NewControllerRef(&nginx, schema.GroupVersionKind{
-							Group:   v1alpha1.GroupVersion.Group,
-							Version: v1alpha1.GroupVersion.Version,
+							Group:   v1beta1.GroupVersion.Group,
+							Version: v1beta1.GroupVersion.Version,
 							Kind:    "Nginx",
 						}),
 					},
@@ -1815,9 +1955,9 @@ func TestNewIngress(t *testing.T) {
 		},
 
 		"with custom annotations and labels": {
-			nginx: func() v1alpha1.Nginx {
+			nginx: func() v1beta1.Nginx {
 				n := baseNginx()
-				n.Spec.Ingress = &v1alpha1.NginxIngress{
+				n.Spec.Ingress = &v1beta1.NginxIngress{
 					Annotations: map[string]string{
 						"custom.annotations.example.com/key": "value",
 					},
@@ -1846,8 +1986,8 @@ func TestNewIngress(t *testing.T) {
 					},
 					OwnerReferences: []metav1.OwnerReference{
 						*metav1.NewControllerRef(&nginx, schema.GroupVersionKind{
-							Group:   v1alpha1.GroupVersion.Group,
-							Version: v1alpha1.GroupVersion.Version,
+							Group:   v1beta1.GroupVersion.Group,
+							Version: v1beta1.GroupVersion.Version,
 							Kind:    "Nginx",
 						}),
 					},
@@ -1864,12 +2004,12 @@ func TestNewIngress(t *testing.T) {
 		},
 
 		"when there are TLS configs on the Nginx, should create the ingress with TLS configs too": {
-			nginx: func() v1alpha1.Nginx {
+			nginx: func() v1beta1.Nginx {
 				n := baseNginx()
-				n.Spec.Ingress = &v1alpha1.NginxIngress{
+				n.Spec.Ingress = &v1beta1.NginxIngress{
 					IngressClassName: func(s string) *string { return &s }("custom-class"),
 				}
-				n.Spec.TLS = []v1alpha1.NginxTLS{
+				n.Spec.TLS = []v1beta1.NginxTLS{
 					{SecretName: "specific-domains-cert", Hosts: []string{"www.example.com", "blog.example.com", "id.example.com"}},
 					{SecretName: "wildcard-cert"},
 				}
@@ -1889,8 +2029,8 @@ func TestNewIngress(t *testing.T) {
 					},
 					OwnerReferences: []metav1.OwnerReference{
 						*metav1.NewControllerRef(&nginx, schema.GroupVersionKind{
-							Group:   v1alpha1.GroupVersion.Group,
-							Version: v1alpha1.GroupVersion.Version,
+							Group:   v1beta1.GroupVersion.Group,
+							Version: v1beta1.GroupVersion.Version,
 							Kind:    "Nginx",
 						}),
 					},
@@ -2010,3 +2150,378 @@ func TestNewIngress(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPodDisruptionBudget(t *testing.T) {
+	nginx := baseNginx()
+	nginx.Spec.DisruptionBudget = &v1beta1.NginxDisruptionBudget{
+		MinAvailable: func(v intstr.IntOrString) *intstr.IntOrString { return &v }(intstr.FromString("50%")),
+	}
+
+	assert.Equal(t, &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "policy/v1",
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-nginx",
+			Namespace: "default",
+			Labels: map[string]string{
+				"nginx.tsuru.io/app":           "nginx",
+				"nginx.tsuru.io/resource-name": "my-nginx",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(&nginx, schema.GroupVersionKind{
+					Group:   v1beta1.GroupVersion.Group,
+					Version: v1beta1.GroupVersion.Version,
+					Kind:    "Nginx",
+				}),
+			},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: func(v intstr.IntOrString) *intstr.IntOrString { return &v }(intstr.FromString("50%")),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"nginx.tsuru.io/app":           "nginx",
+					"nginx.tsuru.io/resource-name": "my-nginx",
+				},
+			},
+		},
+	}, NewPodDisruptionBudget(&nginx))
+}
+
+func TestNewHorizontalPodAutoscaler(t *testing.T) {
+	nginx := baseNginx()
+	nginx.Spec.Autoscaling = &v1beta1.NginxAutoscaling{
+		MaxReplicas:                       10,
+		TargetCPUUtilizationPercentage:    func(n int32) *int32 { return &n }(int32(85)),
+		TargetMemoryUtilizationPercentage: func(n int32) *int32 { return &n }(int32(70)),
+		Metrics: []autoscalingv2.MetricSpec{
+			{
+				Type: autoscalingv2.PodsMetricSourceType,
+				Pods: &autoscalingv2.PodsMetricSource{
+					Metric: autoscalingv2.MetricIdentifier{Name: "nginx_http_requests_per_second"},
+					Target: autoscalingv2.MetricTarget{
+						Type:         autoscalingv2.AverageValueMetricType,
+						AverageValue: func(q resource.Quantity) *resource.Quantity { return &q }(resource.MustParse("100")),
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, &autoscalingv2.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "autoscaling/v2",
+			Kind:       "HorizontalPodAutoscaler",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-nginx",
+			Namespace: "default",
+			Labels: map[string]string{
+				"nginx.tsuru.io/app":           "nginx",
+				"nginx.tsuru.io/resource-name": "my-nginx",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(&nginx, schema.GroupVersionKind{
+					Group:   v1beta1.GroupVersion.Group,
+					Version: v1beta1.GroupVersion.Version,
+					Kind:    "Nginx",
+				}),
+			},
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "nginx.tsuru.io/v1beta1",
+				Kind:       "Nginx",
+				Name:       "my-nginx",
+			},
+			MinReplicas: func(n int32) *int32 { return &n }(int32(1)),
+			MaxReplicas: 10,
+			Metrics: []autoscalingv2.MetricSpec{
+				{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricSource{
+						Name: corev1.ResourceCPU,
+						Target: autoscalingv2.MetricTarget{
+							Type:               autoscalingv2.UtilizationMetricType,
+							AverageUtilization: func(n int32) *int32 { return &n }(int32(85)),
+						},
+					},
+				},
+				{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricSource{
+						Name: corev1.ResourceMemory,
+						Target: autoscalingv2.MetricTarget{
+							Type:               autoscalingv2.UtilizationMetricType,
+							AverageUtilization: func(n int32) *int32 { return &n }(int32(70)),
+						},
+					},
+				},
+				{
+					Type: autoscalingv2.PodsMetricSourceType,
+					Pods: &autoscalingv2.PodsMetricSource{
+						Metric: autoscalingv2.MetricIdentifier{Name: "nginx_http_requests_per_second"},
+						Target: autoscalingv2.MetricTarget{
+							Type:         autoscalingv2.AverageValueMetricType,
+							AverageValue: func(q resource.Quantity) *resource.Quantity { return &q }(resource.MustParse("100")),
+						},
+					},
+				},
+			},
+		},
+	}, NewHorizontalPodAutoscaler(&nginx))
+}
+
+func TestConfigMapNames(t *testing.T) {
+	tests := map[string]struct {
+		spec     v1beta1.NginxSpec
+		expected []string
+	}{
+		"without config maps": {
+			spec: v1beta1.NginxSpec{
+				Config: &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindInline, Value: "events {}"},
+			},
+		},
+
+		"with config and extra files": {
+			spec: v1beta1.NginxSpec{
+				Config:     &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindConfigMap, Name: "my-config"},
+				ExtraFiles: &v1beta1.FilesRef{Name: "my-files"},
+			},
+			expected: []string{"my-config", "my-files"},
+		},
+
+		"with same config map for both": {
+			spec: v1beta1.NginxSpec{
+				Config:     &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindConfigMap, Name: "my-config"},
+				ExtraFiles: &v1beta1.FilesRef{Name: "my-config"},
+			},
+			expected: []string{"my-config"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ConfigMapNames(tt.spec))
+		})
+	}
+}
+
+func TestSetConfigMapsHash(t *testing.T) {
+	configMaps := []corev1.ConfigMap{
+		{ObjectMeta: metav1.ObjectMeta{Name: "my-config"}, Data: map[string]string{"nginx.conf": "events {}"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "my-files"}, BinaryData: map[string][]byte{"favicon.ico": {0x00, 0x01}}},
+	}
+
+	hash := func(cms []corev1.ConfigMap) string {
+		var dep appv1.Deployment
+		require.NoError(t, SetConfigMapsHash(&dep, cms))
+		return dep.Spec.Template.Annotations[ConfigHashAnnotation]
+	}
+
+	assert.Empty(t, hash(nil))
+
+	original := hash(configMaps)
+	assert.Len(t, original, 64)
+	assert.Equal(t, original, hash([]corev1.ConfigMap{configMaps[1], configMaps[0]}))
+
+	changed := []corev1.ConfigMap{*configMaps[0].DeepCopy(), configMaps[1]}
+	changed[0].Data["nginx.conf"] = "events {}\nhttp {}"
+	assert.NotEqual(t, original, hash(changed))
+}
+
+func TestSetTLSSecretsHash(t *testing.T) {
+	secrets := []corev1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-com"},
+			Data:       map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+		},
+	}
+
+	hash := func(strategy v1beta1.TLSReloadStrategy, secrets []corev1.Secret) string {
+		nginx := baseNginx()
+		nginx.Spec.TLSReloadStrategy = strategy
+
+		var dep appv1.Deployment
+		require.NoError(t, SetTLSSecretsHash(&nginx, &dep, secrets))
+		return dep.Spec.Template.Annotations[TLSHashAnnotation]
+	}
+
+	assert.Empty(t, hash(v1beta1.TLSReloadStrategyReload, secrets))
+	assert.Empty(t, hash(v1beta1.TLSReloadStrategyRollingRestart, nil))
+
+	original := hash(v1beta1.TLSReloadStrategyRollingRestart, secrets)
+	assert.Len(t, original, 64)
+
+	renewed := []corev1.Secret{*secrets[0].DeepCopy()}
+	renewed[0].Data["tls.crt"] = []byte("renewed cert")
+	assert.NotEqual(t, original, hash(v1beta1.TLSReloadStrategyRollingRestart, renewed))
+}
+
+func TestTLSCertificateChecksums(t *testing.T) {
+	secrets := []corev1.Secret{
+		{ObjectMeta: metav1.ObjectMeta{Name: "example-com"}, Data: map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "opaque"}, Data: map[string][]byte{"password": []byte("secret")}},
+	}
+
+	assert.Equal(t, map[string]string{
+		"/etc/nginx/certs/example-com/tls.crt": "06298432e8066b29e2223bcc23aa9504b56ae508fabf3435508869b9c3190e22",
+	}, TLSCertificateChecksums(secrets))
+}
+
+func TestValidateConfig(t *testing.T) {
+	configMaps := []corev1.ConfigMap{
+		{ObjectMeta: metav1.ObjectMeta{Name: "other-config"}, Data: map[string]string{"nginx.conf": "events {"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "my-config"}, Data: map[string]string{"nginx.conf": "events {}\nhttp {\n  include extra_files/servers.conf;\n"}},
+	}
+
+	tests := map[string]struct {
+		nginx         func(n *v1beta1.Nginx)
+		expectedError string
+	}{
+		"without config": {
+			nginx: func(n *v1beta1.Nginx) {},
+		},
+
+		"inline config": {
+			nginx: func(n *v1beta1.Nginx) {
+				n.Spec.TLS = []v1beta1.NginxTLS{{SecretName: "example-com"}}
+				n.Spec.ExtraFiles = &v1beta1.FilesRef{Name: "my-files", Files: map[string]string{"servers": "servers.conf"}}
+				n.Spec.Config = &v1beta1.ConfigRef{
+					Kind:  v1beta1.ConfigKindInline,
+					Value: "http {\n  include extra_files/servers.conf;\n  ssl_certificate ./certs/example-com/tls.crt;\n}",
+				}
+			},
+		},
+
+		"inline config with certificate not in spec.tls": {
+			nginx: func(n *v1beta1.Nginx) {
+				n.Spec.Config = &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindInline, Value: "ssl_certificate ./certs/example-com/tls.crt;"}
+			},
+			expectedError: `line 1: ssl_certificate "./certs/example-com/tls.crt" refers to Secret "example-com" which is not in spec.tls`,
+		},
+
+		"template config": {
+			nginx: func(n *v1beta1.Nginx) {
+				n.Spec.Config = &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindTemplate, Value: "server { listen {{ .Ports.http }} }"}
+			},
+			expectedError: `line 1: unexpected "}", directive "listen" is not terminated by ";"`,
+		},
+
+		"config map": {
+			nginx: func(n *v1beta1.Nginx) {
+				n.Spec.ExtraFiles = &v1beta1.FilesRef{Name: "my-files", Files: map[string]string{"locations": "locations.conf"}}
+				n.Spec.Config = &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindConfigMap, Name: "my-config"}
+			},
+			expectedError: `line 3: unexpected end of file, expecting "}"`,
+		},
+
+		"config map not found": {
+			nginx: func(n *v1beta1.Nginx) {
+				n.Spec.Config = &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindConfigMap, Name: "unknown-config"}
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nginx := baseNginx()
+			tt.nginx(&nginx)
+
+			err := ValidateConfig(&nginx, configMaps)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNewConfigTestJob(t *testing.T) {
+	nginx := baseNginx()
+	nginx.Spec.TLS = []v1beta1.NginxTLS{{SecretName: "example-com"}}
+	nginx.Spec.TLSReloadStrategy = v1beta1.TLSReloadStrategyReload
+	nginx.Spec.PodTemplate.HostNetwork = true
+
+	dep, err := NewDeployment(&nginx)
+	require.NoError(t, err)
+
+	job, err := NewConfigTestJob(&nginx, dep)
+	require.NoError(t, err)
+
+	hash := job.Labels[ConfigTestLabel]
+	require.Len(t, hash, 10)
+	assert.Equal(t, "my-nginx-config-test-"+hash, job.Name)
+	assert.Equal(t, "default", job.Namespace)
+	assert.Equal(t, "my-nginx", job.Labels["nginx.tsuru.io/resource-name"])
+	require.Len(t, job.OwnerReferences, 1)
+	assert.Equal(t, "my-nginx", job.OwnerReferences[0].Name)
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	assert.Equal(t, int64(120), *job.Spec.ActiveDeadlineSeconds)
+
+	pod := job.Spec.Template
+	assert.Equal(t, map[string]string{ConfigTestLabel: hash}, pod.Labels)
+	assert.Equal(t, dep.Spec.Template.Annotations, pod.Annotations)
+	assert.Equal(t, corev1.RestartPolicyNever, pod.Spec.RestartPolicy)
+	assert.False(t, pod.Spec.HostNetwork)
+	assert.Nil(t, pod.Spec.ShareProcessNamespace)
+	assert.Equal(t, dep.Spec.Template.Spec.Volumes, pod.Spec.Volumes)
+
+	require.Len(t, pod.Spec.Containers, 1)
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, dep.Spec.Template.Spec.Containers[0].Image, container.Image)
+	assert.Equal(t, dep.Spec.Template.Spec.Containers[0].VolumeMounts, container.VolumeMounts)
+	assert.Equal(t, []string{"/bin/sh", "-c", configTestScript}, container.Command)
+	assert.Equal(t, corev1.TerminationMessageFallbackToLogsOnError, container.TerminationMessagePolicy)
+	assert.Empty(t, container.Ports)
+	assert.Nil(t, container.ReadinessProbe)
+	assert.Nil(t, container.LivenessProbe)
+	assert.Nil(t, container.Lifecycle)
+
+	again, err := NewConfigTestJob(&nginx, dep)
+	require.NoError(t, err)
+	assert.Equal(t, job.Name, again.Name)
+
+	dep.Spec.Template.Spec.Containers[0].Image = "nginx:other"
+	other, err := NewConfigTestJob(&nginx, dep)
+	require.NoError(t, err)
+	assert.NotEqual(t, job.Name, other.Name)
+
+	nginx.Name = strings.Repeat("a", 60)
+	other, err = NewConfigTestJob(&nginx, dep)
+	require.NoError(t, err)
+	assert.Len(t, other.Name, 63)
+}
+
+func TestNewControllerRevision(t *testing.T) {
+	nginx := baseNginx()
+	nginx.Spec.Image = "nginx:1.25"
+
+	rev, err := NewControllerRevision(&nginx, 3)
+	require.NoError(t, err)
+
+	hash := rev.Labels[RevisionHashLabel]
+	require.Len(t, hash, 10)
+	assert.Equal(t, "my-nginx-"+hash, rev.Name)
+	assert.Equal(t, "default", rev.Namespace)
+	assert.Equal(t, "my-nginx", rev.Labels["nginx.tsuru.io/resource-name"])
+	require.Len(t, rev.OwnerReferences, 1)
+	assert.Equal(t, "my-nginx", rev.OwnerReferences[0].Name)
+	assert.Equal(t, int64(3), rev.Revision)
+
+	spec, err := ExtractRevisionSpec(rev)
+	require.NoError(t, err)
+	assert.Equal(t, nginx.Spec, spec)
+
+	same, err := NewControllerRevision(&nginx, 4)
+	require.NoError(t, err)
+	assert.Equal(t, rev.Name, same.Name)
+
+	nginx.Spec.Image = "nginx:1.26"
+	other, err := NewControllerRevision(&nginx, 4)
+	require.NoError(t, err)
+	assert.NotEqual(t, rev.Name, other.Name)
+}
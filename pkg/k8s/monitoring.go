@@ -0,0 +1,254 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package k8s
+
+import (
+	"fmt"
+
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+const (
+	defaultExporterImage       = "nginx/nginx-prometheus-exporter:1.3.0"
+	defaultExporterPort        = int32(9113)
+	defaultStubStatusPort      = int32(8090)
+	exporterContainerName      = "nginx-exporter"
+	metricsPortName            = "metrics"
+	stubStatusPortName         = "stub-status"
+	stubStatusPath             = "/stub_status"
+	stubStatusVolumeName       = "nginx-stub-status"
+	stubStatusFileName         = "nginx-operator-stub-status.conf"
+	stubStatusMountPath        = configMountPath + "/conf.d/" + stubStatusFileName
+	stubStatusConfigAnnotation = "nginx.tsuru.io/stub-status-config"
+
+	// MetricsServiceLabel tells the Service exposing the exporter apart from
+	// the other Services of the Nginx.
+	MetricsServiceLabel = "nginx.tsuru.io/metrics"
+)
+
+// stubStatusConfig is the server serving the nginx stub_status, which the
+// exporter sidecar scrapes through the loopback interface.
+const stubStatusConfig = `server {
+    listen %d;
+    access_log off;
+    allow 127.0.0.1;
+    deny all;
+
+    location = %s {
+        stub_status;
+    }
+}
+`
+
+var (
+	// PodMonitorGVK and ServiceMonitorGVK are the kinds of the Prometheus
+	// Operator monitors. They are handled as unstructured objects, so that
+	// the Prometheus Operator is only required in the cluster when some Nginx
+	// is monitored.
+	PodMonitorGVK     = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+	ServiceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+)
+
+// MonitorKind returns the kind of monitor created for the Nginx, if any.
+func MonitorKind(n *v1beta1.Nginx) v1beta1.NginxMonitorKind {
+	if n.Spec.Monitoring == nil {
+		return v1beta1.NginxMonitorNone
+	}
+
+	if n.Spec.Monitoring.Monitor == "" {
+		return v1beta1.NginxMonitorPodMonitor
+	}
+
+	return n.Spec.Monitoring.Monitor
+}
+
+// setupMonitoring adds the exporter sidecar, along with the stub_status
+// server it scrapes, which is included by the nginx config from conf.d.
+func setupMonitoring(monitoring *v1beta1.NginxMonitoring, dep *appv1.Deployment) {
+	if monitoring == nil {
+		return
+	}
+
+	stubStatusPort := stubStatusPortOrDefault(monitoring)
+
+	nginx := &dep.Spec.Template.Spec.Containers[0]
+	nginx.Ports = append(append([]corev1.ContainerPort{}, nginx.Ports...), corev1.ContainerPort{
+		Name:          stubStatusPortName,
+		ContainerPort: stubStatusPort,
+		Protocol:      corev1.ProtocolTCP,
+	})
+	nginx.VolumeMounts = append(nginx.VolumeMounts, corev1.VolumeMount{
+		Name:      stubStatusVolumeName,
+		MountPath: stubStatusMountPath,
+		SubPath:   stubStatusFileName,
+		ReadOnly:  true,
+	})
+
+	if dep.Spec.Template.Annotations == nil {
+		dep.Spec.Template.Annotations = make(map[string]string)
+	}
+	dep.Spec.Template.Annotations[stubStatusConfigAnnotation] = fmt.Sprintf(stubStatusConfig, stubStatusPort, stubStatusPath)
+
+	dep.Spec.Template.Spec.Volumes = append(dep.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: stubStatusVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{
+				Items: []corev1.DownwardAPIVolumeFile{
+					{
+						Path: stubStatusFileName,
+						FieldRef: &corev1.ObjectFieldSelector{
+							FieldPath: fmt.Sprintf("metadata.annotations['%s']", stubStatusConfigAnnotation),
+						},
+					},
+				},
+			},
+		},
+	})
+
+	resources := monitoring.Resources
+	if resources.Requests == nil && resources.Limits == nil {
+		resources = corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+				corev1.ResourceMemory: resource.MustParse("16Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+		}
+	}
+
+	port := exporterPortOrDefault(monitoring)
+	dep.Spec.Template.Spec.Containers = append(dep.Spec.Template.Spec.Containers, corev1.Container{
+		Name:  exporterContainerName,
+		Image: valueOrDefault(monitoring.Image, defaultExporterImage),
+		Args: []string{
+			fmt.Sprintf("--nginx.scrape-uri=http://127.0.0.1:%d%s", stubStatusPort, stubStatusPath),
+			fmt.Sprintf("--web.listen-address=:%d", port),
+		},
+		Ports: []corev1.ContainerPort{
+			{Name: metricsPortName, ContainerPort: port, Protocol: corev1.ProtocolTCP},
+		},
+		Resources: resources,
+	})
+}
+
+func exporterPortOrDefault(monitoring *v1beta1.NginxMonitoring) int32 {
+	if monitoring.Port == 0 {
+		return defaultExporterPort
+	}
+	return monitoring.Port
+}
+
+func stubStatusPortOrDefault(monitoring *v1beta1.NginxMonitoring) int32 {
+	if monitoring.StubStatusPort == 0 {
+		return defaultStubStatusPort
+	}
+	return monitoring.StubStatusPort
+}
+
+// MonitoringPorts returns the container ports added by spec.monitoring, so
+// they can be checked against the ones of the pod template.
+func MonitoringPorts(monitoring *v1beta1.NginxMonitoring) []int32 {
+	if monitoring == nil {
+		return nil
+	}
+	return []int32{exporterPortOrDefault(monitoring), stubStatusPortOrDefault(monitoring)}
+}
+
+// MetricsServiceName returns the name of the Service scraped through the
+// ServiceMonitor.
+func MetricsServiceName(n *v1beta1.Nginx) string {
+	return ServiceName(n, metricsPortName)
+}
+
+// newMetricsService assembles the headless Service which exposes the exporter
+// of every nginx pod to the ServiceMonitor.
+func newMetricsService(n *v1beta1.Nginx) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      MetricsServiceName(n),
+			Namespace: n.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(n, v1beta1.GroupVersion.WithKind("Nginx")),
+			},
+			Labels: mergeMap(LabelsForNginx(n.Name), map[string]string{MetricsServiceLabel: "true"}),
+		},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeClusterIP,
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  LabelsForNginx(n.Name),
+			Ports: []corev1.ServicePort{
+				{
+					Name:       metricsPortName,
+					Port:       exporterPortOrDefault(n.Spec.Monitoring),
+					TargetPort: intstr.FromString(metricsPortName),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
+// NewMonitor assembles the PodMonitor or ServiceMonitor scraping the exporter
+// sidecars of the Nginx. It returns nil when no monitor is desired.
+func NewMonitor(n *v1beta1.Nginx) *unstructured.Unstructured {
+	var gvk schema.GroupVersionKind
+	var spec map[string]any
+
+	endpoint := map[string]any{"port": metricsPortName}
+	if n.Spec.Monitoring != nil && n.Spec.Monitoring.Interval != "" {
+		endpoint["interval"] = n.Spec.Monitoring.Interval
+	}
+
+	switch MonitorKind(n) {
+	case v1beta1.NginxMonitorPodMonitor:
+		gvk = PodMonitorGVK
+		spec = map[string]any{
+			"selector":            map[string]any{"matchLabels": toAnyMap(LabelsForNginx(n.Name))},
+			"podMetricsEndpoints": []any{endpoint},
+		}
+
+	case v1beta1.NginxMonitorServiceMonitor:
+		gvk = ServiceMonitorGVK
+		spec = map[string]any{
+			"selector":  map[string]any{"matchLabels": toAnyMap(newMetricsService(n).Labels)},
+			"endpoints": []any{endpoint},
+		}
+
+	default:
+		return nil
+	}
+
+	monitor := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	monitor.SetGroupVersionKind(gvk)
+	monitor.SetName(n.Name)
+	monitor.SetNamespace(n.Namespace)
+	monitor.SetLabels(mergeMap(mergeMap(make(map[string]string), n.Spec.Monitoring.Labels), LabelsForNginx(n.Name)))
+	monitor.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(n, v1beta1.GroupVersion.WithKind("Nginx")),
+	})
+	return monitor
+}
+
+func toAnyMap(m map[string]string) map[string]any {
+	result := make(map[string]any, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
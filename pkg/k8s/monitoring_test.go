@@ -0,0 +1,183 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+func TestNewDeployment_monitoring(t *testing.T) {
+	nginx := baseNginx()
+
+	dep, err := NewDeployment(&nginx)
+	require.NoError(t, err)
+	assert.Len(t, dep.Spec.Template.Spec.Containers, 1)
+
+	nginx.Spec.Monitoring = &v1beta1.NginxMonitoring{StubStatusPort: 8081}
+	dep, err = NewDeployment(&nginx)
+	require.NoError(t, err)
+	require.Len(t, dep.Spec.Template.Spec.Containers, 2)
+
+	container := dep.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []corev1.ContainerPort{
+		{Name: "http", ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
+		{Name: "https", ContainerPort: 8443, Protocol: corev1.ProtocolTCP},
+		{Name: "stub-status", ContainerPort: 8081, Protocol: corev1.ProtocolTCP},
+	}, container.Ports)
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{
+		Name:      "nginx-stub-status",
+		MountPath: "/etc/nginx/conf.d/nginx-operator-stub-status.conf",
+		SubPath:   "nginx-operator-stub-status.conf",
+		ReadOnly:  true,
+	})
+	assert.Contains(t, dep.Spec.Template.Annotations["nginx.tsuru.io/stub-status-config"], "listen 8081;")
+
+	exporter := dep.Spec.Template.Spec.Containers[1]
+	assert.Equal(t, "nginx-exporter", exporter.Name)
+	assert.Equal(t, "nginx/nginx-prometheus-exporter:1.3.0", exporter.Image)
+	assert.Equal(t, []string{
+		"--nginx.scrape-uri=http://127.0.0.1:8081/stub_status",
+		"--web.listen-address=:9113",
+	}, exporter.Args)
+	assert.Equal(t, []corev1.ContainerPort{
+		{Name: "metrics", ContainerPort: 9113, Protocol: corev1.ProtocolTCP},
+	}, exporter.Ports)
+
+	// the stored spec isn't changed by the injected port
+	spec, err := ExtractNginxSpec(dep.ObjectMeta)
+	require.NoError(t, err)
+	assert.Len(t, spec.PodTemplate.Ports, 2)
+}
+
+func TestRenderStructuredConfig_monitoring(t *testing.T) {
+	nginx := baseNginx()
+	nginx.Spec.Config = &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindStructured}
+
+	config, err := RenderStructuredConfig(&nginx)
+	require.NoError(t, err)
+	assert.NotContains(t, config, "stub-status")
+
+	nginx.Spec.Monitoring = &v1beta1.NginxMonitoring{}
+	config, err = RenderStructuredConfig(&nginx)
+	require.NoError(t, err)
+	assert.Contains(t, config, "include conf.d/nginx-operator-stub-status.conf;")
+}
+
+func TestNewMonitor(t *testing.T) {
+	tests := map[string]struct {
+		monitoring       *v1beta1.NginxMonitoring
+		expected         map[string]any
+		expectedServices []string
+	}{
+		"without monitoring": {
+			expectedServices: []string{"my-nginx-service"},
+		},
+
+		"without monitor": {
+			monitoring:       &v1beta1.NginxMonitoring{Monitor: v1beta1.NginxMonitorNone},
+			expectedServices: []string{"my-nginx-service"},
+		},
+
+		"pod monitor": {
+			monitoring: &v1beta1.NginxMonitoring{Interval: "30s", Labels: map[string]string{"release": "prometheus"}},
+			expected: map[string]any{
+				"apiVersion": "monitoring.coreos.com/v1",
+				"kind":       "PodMonitor",
+				"metadata": map[string]any{
+					"name":      "my-nginx",
+					"namespace": "default",
+					"labels": map[string]any{
+						"nginx.tsuru.io/app":           "nginx",
+						"nginx.tsuru.io/resource-name": "my-nginx",
+						"release":                      "prometheus",
+					},
+				},
+				"spec": map[string]any{
+					"selector": map[string]any{"matchLabels": map[string]any{
+						"nginx.tsuru.io/app":           "nginx",
+						"nginx.tsuru.io/resource-name": "my-nginx",
+					}},
+					"podMetricsEndpoints": []any{map[string]any{"port": "metrics", "interval": "30s"}},
+				},
+			},
+			expectedServices: []string{"my-nginx-service"},
+		},
+
+		"service monitor": {
+			monitoring: &v1beta1.NginxMonitoring{Monitor: v1beta1.NginxMonitorServiceMonitor},
+			expected: map[string]any{
+				"apiVersion": "monitoring.coreos.com/v1",
+				"kind":       "ServiceMonitor",
+				"metadata": map[string]any{
+					"name":      "my-nginx",
+					"namespace": "default",
+					"labels": map[string]any{
+						"nginx.tsuru.io/app":           "nginx",
+						"nginx.tsuru.io/resource-name": "my-nginx",
+					},
+				},
+				"spec": map[string]any{
+					"selector": map[string]any{"matchLabels": map[string]any{
+						"nginx.tsuru.io/app":           "nginx",
+						"nginx.tsuru.io/resource-name": "my-nginx",
+						"nginx.tsuru.io/metrics":       "true",
+					}},
+					"endpoints": []any{map[string]any{"port": "metrics"}},
+				},
+			},
+			expectedServices: []string{"my-nginx-service", "my-nginx-metrics"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nginx := baseNginx()
+			nginx.Spec.Monitoring = tt.monitoring
+
+			var names []string
+			for _, svc := range NewServices(&nginx) {
+				names = append(names, svc.Name)
+			}
+			assert.Equal(t, tt.expectedServices, names)
+
+			monitor := NewMonitor(&nginx)
+			if tt.expected == nil {
+				assert.Nil(t, monitor)
+				return
+			}
+
+			require.NotNil(t, monitor)
+			require.Len(t, monitor.GetOwnerReferences(), 1)
+			assert.Equal(t, "Nginx", monitor.GetOwnerReferences()[0].Kind)
+
+			monitor.SetOwnerReferences(nil)
+			assert.Equal(t, tt.expected, monitor.Object)
+		})
+	}
+}
+
+func TestNewServices_metrics(t *testing.T) {
+	nginx := baseNginx()
+	nginx.Spec.Monitoring = &v1beta1.NginxMonitoring{Monitor: v1beta1.NginxMonitorServiceMonitor, Port: 9000}
+
+	services := NewServices(&nginx)
+	require.Len(t, services, 2)
+
+	svc := services[1]
+	assert.Equal(t, "my-nginx-metrics", svc.Name)
+	assert.Equal(t, "true", svc.Labels["nginx.tsuru.io/metrics"])
+	assert.Equal(t, corev1.ClusterIPNone, svc.Spec.ClusterIP)
+	assert.Equal(t, LabelsForNginx("my-nginx"), svc.Spec.Selector)
+	assert.Equal(t, []corev1.ServicePort{
+		{Name: "metrics", Port: 9000, TargetPort: intstr.FromString("metrics"), Protocol: corev1.ProtocolTCP},
+	}, svc.Spec.Ports)
+}
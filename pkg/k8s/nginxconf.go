@@ -0,0 +1,248 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+// RenderStructuredConfig generates the nginx.conf from the servers of a
+// config of kind "Structured".
+func RenderStructuredConfig(n *v1beta1.Nginx) (string, error) {
+	if n.Spec.Config == nil || n.Spec.Config.Kind != v1beta1.ConfigKindStructured {
+		return "", errors.New("nginx config is not structured")
+	}
+
+	n = n.DeepCopy()
+	SetDefaults(&n.Spec)
+
+	var renderErr error
+	w := &confWriter{}
+	w.line("events {}")
+	w.line("")
+	w.block("http", func() {
+		w.line("include mime.types;")
+		w.line("default_type application/octet-stream;")
+
+		if n.Spec.Monitoring != nil {
+			w.line("include conf.d/%s;", stubStatusFileName)
+		}
+
+		for i, server := range n.Spec.Config.Servers {
+			w.line("")
+			if err := renderServer(w, n.Spec, server); err != nil {
+				renderErr = fmt.Errorf("servers[%d]: %w", i, err)
+				return
+			}
+		}
+	})
+
+	if renderErr != nil {
+		return "", renderErr
+	}
+
+	return w.sb.String(), nil
+}
+
+func renderServer(w *confWriter, spec v1beta1.NginxSpec, server v1beta1.NginxServerConfig) error {
+	listen := server.Listen
+	if len(listen) == 0 {
+		listen = []v1beta1.NginxListen{{Port: defaultHTTPPortName}}
+	}
+
+	var directives []string
+	hasSSL := false
+	for _, l := range listen {
+		port := portByName(spec.PodTemplate.Ports, l.Port)
+		if port == nil {
+			return fmt.Errorf("container port %q not found", l.Port)
+		}
+
+		directive := fmt.Sprintf("listen %d", port.ContainerPort)
+		for _, param := range []struct {
+			enabled bool
+			name    string
+		}{{l.DefaultServer, "default_server"}, {l.SSL, "ssl"}, {l.HTTP2, "http2"}, {l.ProxyProtocol, "proxy_protocol"}} {
+			if param.enabled {
+				directive += " " + param.name
+			}
+		}
+
+		directives = append(directives, directive+";")
+		hasSSL = hasSSL || l.SSL
+	}
+
+	if hasSSL && server.TLSSecretName == "" {
+		return errors.New("tlsSecretName is required by ssl ports")
+	}
+
+	if server.TLSSecretName != "" && !hasTLSSecret(spec.TLS, server.TLSSecretName) {
+		return fmt.Errorf("TLS secret %q not found in spec.tls", server.TLSSecretName)
+	}
+
+	var renderErr error
+	w.block("server", func() {
+		for _, d := range directives {
+			w.line("%s", d)
+		}
+
+		serverNames := server.ServerNames
+		if len(serverNames) == 0 {
+			serverNames = []string{"_"}
+		}
+		w.line("server_name %s;", strings.Join(serverNames, " "))
+
+		if server.TLSSecretName != "" {
+			w.line("")
+			w.line("ssl_certificate     %s;", filepath.Join(certMountPath, server.TLSSecretName, "tls.crt"))
+			w.line("ssl_certificate_key %s;", filepath.Join(certMountPath, server.TLSSecretName, "tls.key"))
+		}
+
+		if len(server.Headers) > 0 {
+			w.line("")
+			renderHeaders(w, "add_header", server.Headers, " always")
+		}
+
+		healthcheck := spec.HealthcheckPath != ""
+		for i, location := range server.Locations {
+			if location.Path == spec.HealthcheckPath {
+				healthcheck = false
+			}
+
+			w.line("")
+			if err := renderLocation(w, server, location); err != nil {
+				renderErr = fmt.Errorf("locations[%d]: %w", i, err)
+				return
+			}
+		}
+
+		if healthcheck {
+			w.line("")
+			w.block("location = "+spec.HealthcheckPath, func() {
+				w.line("access_log off;")
+				w.line("return 200;")
+			})
+		}
+	})
+
+	return renderErr
+}
+
+func renderLocation(w *confWriter, server v1beta1.NginxServerConfig, location v1beta1.NginxLocation) error {
+	if location.Path == "" {
+		return errors.New("path is required")
+	}
+
+	actions := 0
+	for _, set := range []bool{location.ProxyPass != "", location.Root != "", location.Return != nil, location.Redirect != nil} {
+		if set {
+			actions++
+		}
+	}
+
+	if actions != 1 {
+		return errors.New("exactly one of proxyPass, root, return and redirect is required")
+	}
+
+	name := "location " + location.Path
+	if location.Modifier != "" {
+		name = fmt.Sprintf("location %s %s", location.Modifier, location.Path)
+	}
+
+	w.block(name, func() {
+		// NOTE: add_header directives are only inherited from the server
+		// when the location has none, thus they're repeated here.
+		if len(location.Headers) > 0 {
+			headers := mergeMap(make(map[string]string), server.Headers)
+			renderHeaders(w, "add_header", mergeMap(headers, location.Headers), " always")
+		}
+
+		switch {
+		case location.ProxyPass != "":
+			w.line("proxy_pass %s;", location.ProxyPass)
+			renderHeaders(w, "proxy_set_header", location.ProxyHeaders, "")
+
+		case location.Root != "":
+			w.line("root %s;", location.Root)
+
+		case location.Return != nil:
+			if location.Return.Body == "" {
+				w.line("return %d;", location.Return.Code)
+				break
+			}
+
+			w.line("return %d %s;", location.Return.Code, quote(location.Return.Body))
+
+		case location.Redirect != nil:
+			code := http.StatusFound
+			if location.Redirect.Permanent {
+				code = http.StatusMovedPermanently
+			}
+
+			w.line("return %d %s;", code, quote(location.Redirect.URL))
+		}
+	})
+
+	return nil
+}
+
+func renderHeaders(w *confWriter, directive string, headers map[string]string, suffix string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		w.line("%s %s %s%s;", directive, name, quote(headers[name]), suffix)
+	}
+}
+
+func hasTLSSecret(tls []v1beta1.NginxTLS, secretName string) bool {
+	for _, t := range tls {
+		if t.SecretName == secretName {
+			return true
+		}
+	}
+	return false
+}
+
+// quote returns the value as a double-quoted nginx string. Variables, e.g.
+// "$host", are still expanded.
+func quote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// confWriter writes nginx directives, indenting the nested blocks.
+type confWriter struct {
+	sb     strings.Builder
+	indent int
+}
+
+func (w *confWriter) line(format string, args ...any) {
+	if format == "" {
+		w.sb.WriteString("\n")
+		return
+	}
+
+	w.sb.WriteString(strings.Repeat("    ", w.indent))
+	fmt.Fprintf(&w.sb, format, args...)
+	w.sb.WriteString("\n")
+}
+
+func (w *confWriter) block(name string, body func()) {
+	w.line("%s {", name)
+	w.indent++
+	body()
+	w.indent--
+	w.line("}")
+}
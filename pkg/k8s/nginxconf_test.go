@@ -0,0 +1,185 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package k8s
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files of testdata")
+
+func TestRenderStructuredConfig(t *testing.T) {
+	tests := map[string]struct {
+		nginx func(n *v1beta1.Nginx)
+	}{
+		"reverse-proxy": {
+			nginx: func(n *v1beta1.Nginx) {
+				n.Spec.Config.Servers = []v1beta1.NginxServerConfig{
+					{
+						ServerNames: []string{"www.example.com", "example.com"},
+						Locations: []v1beta1.NginxLocation{
+							{
+								Path:      "/",
+								ProxyPass: "http://backend.default.svc:8080",
+								ProxyHeaders: map[string]string{
+									"X-Forwarded-For": "$proxy_add_x_forwarded_for",
+									"Host":            "$host",
+								},
+							},
+						},
+					},
+				}
+			},
+		},
+
+		"static": {
+			nginx: func(n *v1beta1.Nginx) {
+				n.Spec.HealthcheckPath = "/healthz"
+				n.Spec.Config.Servers = []v1beta1.NginxServerConfig{
+					{
+						Listen: []v1beta1.NginxListen{{Port: "http", DefaultServer: true}},
+						Locations: []v1beta1.NginxLocation{
+							{Path: "/", Root: "/usr/share/nginx/html"},
+							{Path: `\.(css|js)$`, Modifier: "~*", Root: "/usr/share/nginx/assets", Headers: map[string]string{"Cache-Control": "max-age=3600"}},
+						},
+					},
+				}
+			},
+		},
+
+		"tls-with-redirects": {
+			nginx: func(n *v1beta1.Nginx) {
+				n.Spec.HealthcheckPath = "/healthz"
+				n.Spec.TLS = []v1beta1.NginxTLS{{SecretName: "example-com", Hosts: []string{"www.example.com"}}}
+				n.Spec.PodTemplate.Ports = []corev1.ContainerPort{
+					{Name: "http", ContainerPort: 8080},
+					{Name: "https", ContainerPort: 8443},
+					{Name: "proxy-https", ContainerPort: 9443},
+				}
+				n.Spec.Config.Servers = []v1beta1.NginxServerConfig{
+					{
+						ServerNames: []string{"www.example.com"},
+						Listen: []v1beta1.NginxListen{
+							{Port: "https", SSL: true, HTTP2: true},
+							{Port: "proxy-https", SSL: true, ProxyProtocol: true},
+						},
+						TLSSecretName: "example-com",
+						Headers: map[string]string{
+							"Strict-Transport-Security": "max-age=31536000",
+							"X-Frame-Options":           "DENY",
+						},
+						Locations: []v1beta1.NginxLocation{
+							{Path: "/", ProxyPass: "http://backend:8080", Headers: map[string]string{"X-Frame-Options": "SAMEORIGIN"}},
+							{Path: "/healthz", Modifier: "=", Return: &v1beta1.NginxReturn{Code: 200, Body: `{"status": "WORKING"}`}},
+							{Path: "/old", Redirect: &v1beta1.NginxRedirect{URL: "https://$host/new", Permanent: true}},
+							{Path: "/gone", Return: &v1beta1.NginxReturn{Code: 410}},
+						},
+					},
+					{
+						ServerNames: []string{"www.example.com"},
+						Locations: []v1beta1.NginxLocation{
+							{Path: "/", Redirect: &v1beta1.NginxRedirect{URL: "https://$host$request_uri"}},
+						},
+					},
+				}
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nginx := baseNginx()
+			nginx.Spec.Config = &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindStructured}
+			tt.nginx(&nginx)
+
+			got, err := RenderStructuredConfig(&nginx)
+			require.NoError(t, err)
+
+			golden := filepath.Join("testdata", "nginxconf", name+".conf")
+			if *updateGolden {
+				require.NoError(t, os.MkdirAll(filepath.Dir(golden), 0755))
+				require.NoError(t, os.WriteFile(golden, []byte(got), 0644))
+			}
+
+			expected, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), got)
+		})
+	}
+}
+
+func TestRenderStructuredConfig_errors(t *testing.T) {
+	tests := map[string]struct {
+		servers       []v1beta1.NginxServerConfig
+		expectedError string
+	}{
+		"unknown port": {
+			servers:       []v1beta1.NginxServerConfig{{Listen: []v1beta1.NginxListen{{Port: "metrics"}}}},
+			expectedError: `servers[0]: container port "metrics" not found`,
+		},
+
+		"ssl port without certificate": {
+			servers:       []v1beta1.NginxServerConfig{{Listen: []v1beta1.NginxListen{{Port: "https", SSL: true}}}},
+			expectedError: "servers[0]: tlsSecretName is required by ssl ports",
+		},
+
+		"unknown certificate": {
+			servers:       []v1beta1.NginxServerConfig{{Listen: []v1beta1.NginxListen{{Port: "https", SSL: true}}, TLSSecretName: "example-org"}},
+			expectedError: `servers[0]: TLS secret "example-org" not found in spec.tls`,
+		},
+
+		"location without action": {
+			servers: []v1beta1.NginxServerConfig{
+				{},
+				{Locations: []v1beta1.NginxLocation{{Path: "/", Root: "/srv"}, {Path: "/api"}}},
+			},
+			expectedError: "servers[1]: locations[1]: exactly one of proxyPass, root, return and redirect is required",
+		},
+
+		"location with many actions": {
+			servers:       []v1beta1.NginxServerConfig{{Locations: []v1beta1.NginxLocation{{Path: "/", Root: "/srv", ProxyPass: "http://backend"}}}},
+			expectedError: "servers[0]: locations[0]: exactly one of proxyPass, root, return and redirect is required",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nginx := baseNginx()
+			nginx.Spec.Config = &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindStructured, Servers: tt.servers}
+
+			_, err := RenderStructuredConfig(&nginx)
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestNewDeployment_StructuredConfig(t *testing.T) {
+	nginx := baseNginx()
+	nginx.Spec.Config = &v1beta1.ConfigRef{
+		Kind:    v1beta1.ConfigKindStructured,
+		Servers: []v1beta1.NginxServerConfig{{Locations: []v1beta1.NginxLocation{{Path: "/", Root: "/srv"}}}},
+	}
+
+	expected, err := RenderStructuredConfig(&nginx)
+	require.NoError(t, err)
+
+	dep, err := NewDeployment(&nginx)
+	require.NoError(t, err)
+	assert.Equal(t, expected, dep.Spec.Template.Annotations["nginx.tsuru.io/custom-nginx-config"])
+	assert.Equal(t, configVolumeName, dep.Spec.Template.Spec.Volumes[0].Name)
+
+	nginx.Spec.Config.Servers[0].Listen = []v1beta1.NginxListen{{Port: "metrics"}}
+	_, err = NewDeployment(&nginx)
+	assert.EqualError(t, err, `failed to render structured config: servers[0]: container port "metrics" not found`)
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package k8s
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	appv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+// RevisionHashLabel labels the ControllerRevisions of an Nginx with the hash
+// of the spec they record.
+const RevisionHashLabel = "nginx.tsuru.io/revision-hash"
+
+// NewControllerRevision creates a ControllerRevision which records the Nginx
+// spec under the given revision number. Its name is derived from the spec, so
+// the same spec always maps to the same ControllerRevision.
+func NewControllerRevision(n *v1beta1.Nginx, revision int64) (*appv1.ControllerRevision, error) {
+	data, err := json.Marshal(n.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal nginx spec: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])[:10]
+
+	return &appv1.ControllerRevision{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ControllerRevision",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", n.Name, hash),
+			Namespace: n.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(n, v1beta1.GroupVersion.WithKind("Nginx")),
+			},
+			Labels: mergeMap(LabelsForNginx(n.Name), map[string]string{RevisionHashLabel: hash}),
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: revision,
+	}, nil
+}
+
+// ExtractRevisionSpec returns the Nginx spec recorded by the ControllerRevision.
+func ExtractRevisionSpec(rev *appv1.ControllerRevision) (v1beta1.NginxSpec, error) {
+	var spec v1beta1.NginxSpec
+	if err := json.Unmarshal(rev.Data.Raw, &spec); err != nil {
+		return v1beta1.NginxSpec{}, fmt.Errorf("failed to unmarshal nginx spec from revision %d: %w", rev.Revision, err)
+	}
+	return spec, nil
+}
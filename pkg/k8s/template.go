@@ -0,0 +1,116 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package k8s
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+// ConfigTemplateData is the data model available to the configs of kind
+// "Template", e.g.:
+//
+//	listen {{ .Ports.http }};
+//	{{- range .TLS }}
+//	ssl_certificate     {{ .CertificatePath }};
+//	ssl_certificate_key {{ .KeyPath }};
+//	{{- end }}
+type ConfigTemplateData struct {
+	// Name of the Nginx.
+	Name string
+	// Namespace of the Nginx.
+	Namespace string
+	// Ports maps the names of the nginx container ports to their numbers,
+	// e.g. {{ .Ports.http }} and {{ .Ports.https }}.
+	Ports map[string]int32
+	// TLS holds the certificates mounted in the nginx container, in the same
+	// order as spec.tls.
+	TLS []ConfigTemplateTLS
+	// CachePath is the directory of the cache volume. Empty when no cache is
+	// configured.
+	CachePath string
+	// HealthcheckPath is the endpoint checked by the readiness probes.
+	HealthcheckPath string
+	// ExtraFilesPath is the directory where spec.extraFiles are mounted.
+	ExtraFilesPath string
+}
+
+// ConfigTemplateTLS is a certificate mounted in the nginx container.
+type ConfigTemplateTLS struct {
+	// SecretName is the name of the Secret holding the certificate.
+	SecretName string
+	// Hosts included in the certificate.
+	Hosts []string
+	// CertificatePath is the path of the certificate file.
+	CertificatePath string
+	// KeyPath is the path of the private key file.
+	KeyPath string
+}
+
+var configTemplateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// ParseConfigTemplate parses the body of a config of kind "Template".
+func ParseConfigTemplate(body string) (*template.Template, error) {
+	return template.New(configFileName).
+		Funcs(configTemplateFuncs).
+		Option("missingkey=error").
+		Parse(body)
+}
+
+// NewConfigTemplateData builds the data model of the config templates from
+// the given Nginx. Defaults are applied over a copy of it.
+func NewConfigTemplateData(n *v1beta1.Nginx) ConfigTemplateData {
+	n = n.DeepCopy()
+	SetDefaults(&n.Spec)
+
+	data := ConfigTemplateData{
+		Name:            n.Name,
+		Namespace:       n.Namespace,
+		Ports:           make(map[string]int32),
+		CachePath:       n.Spec.Cache.Path,
+		HealthcheckPath: n.Spec.HealthcheckPath,
+		ExtraFilesPath:  extraFilesMountPath,
+	}
+
+	for _, port := range n.Spec.PodTemplate.Ports {
+		data.Ports[port.Name] = port.ContainerPort
+	}
+
+	for _, t := range n.Spec.TLS {
+		data.TLS = append(data.TLS, ConfigTemplateTLS{
+			SecretName:      t.SecretName,
+			Hosts:           t.Hosts,
+			CertificatePath: filepath.Join(certMountPath, t.SecretName, "tls.crt"),
+			KeyPath:         filepath.Join(certMountPath, t.SecretName, "tls.key"),
+		})
+	}
+
+	return data
+}
+
+// RenderConfigTemplate renders the config template of the given Nginx.
+func RenderConfigTemplate(n *v1beta1.Nginx) (string, error) {
+	if n.Spec.Config == nil || n.Spec.Config.Kind != v1beta1.ConfigKindTemplate {
+		return "", errors.New("nginx config is not a template")
+	}
+
+	tmpl, err := ParseConfigTemplate(n.Spec.Config.Value)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err = tmpl.Execute(&sb, NewConfigTemplateData(n)); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+func TestRenderConfigTemplate(t *testing.T) {
+	tests := map[string]struct {
+		nginx         func(n *v1beta1.Nginx)
+		expected      string
+		expectedError string
+	}{
+		"default ports": {
+			nginx: func(n *v1beta1.Nginx) {
+				n.Spec.Config.Value = "listen {{ .Ports.http }}; listen {{ .Ports.https }} ssl;"
+			},
+			expected: "listen 8080; listen 8443 ssl;",
+		},
+
+		"host network ports": {
+			nginx: func(n *v1beta1.Nginx) {
+				n.Spec.PodTemplate.HostNetwork = true
+				n.Spec.Config.Value = "listen {{ .Ports.http }}; listen {{ .Ports.https }} ssl;"
+			},
+			expected: "listen 80; listen 443 ssl;",
+		},
+
+		"certificates, cache and healthcheck": {
+			nginx: func(n *v1beta1.Nginx) {
+				n.Spec.TLS = []v1beta1.NginxTLS{
+					{SecretName: "example-com", Hosts: []string{"www.example.com", "example.com"}},
+					{SecretName: "example-org"},
+				}
+				n.Spec.Cache = v1beta1.NginxCacheSpec{Path: "/var/cache/nginx", Size: resource.NewQuantity(1024, resource.BinarySI)}
+				n.Spec.HealthcheckPath = "/healthz"
+				n.Spec.Config.Value = `{{ .Name }}.{{ .Namespace }}
+{{- range .TLS }}
+server_name {{ join .Hosts " " }};
+ssl_certificate {{ .CertificatePath }};
+ssl_certificate_key {{ .KeyPath }};
+{{- end }}
+proxy_cache_path {{ .CachePath }};
+location = {{ .HealthcheckPath }} {}
+include {{ .ExtraFilesPath }}/*.conf;`
+			},
+			expected: `my-nginx.default
+server_name www.example.com example.com;
+ssl_certificate /etc/nginx/certs/example-com/tls.crt;
+ssl_certificate_key /etc/nginx/certs/example-com/tls.key;
+server_name ;
+ssl_certificate /etc/nginx/certs/example-org/tls.crt;
+ssl_certificate_key /etc/nginx/certs/example-org/tls.key;
+proxy_cache_path /var/cache/nginx;
+location = /healthz {}
+include /etc/nginx/extra_files/*.conf;`,
+		},
+
+		"unknown port": {
+			nginx: func(n *v1beta1.Nginx) {
+				n.Spec.Config.Value = "listen {{ .Ports.metrics }};"
+			},
+			expectedError: `template: nginx.conf:1:16: executing "nginx.conf" at <.Ports.metrics>: map has no entry for key "metrics"`,
+		},
+
+		"unknown field": {
+			nginx: func(n *v1beta1.Nginx) {
+				n.Spec.Config.Value = "root {{ .Root }};"
+			},
+			expectedError: `template: nginx.conf:1:8: executing "nginx.conf" at <.Root>: can't evaluate field Root in type k8s.ConfigTemplateData`,
+		},
+
+		"malformed template": {
+			nginx: func(n *v1beta1.Nginx) {
+				n.Spec.Config.Value = "listen {{ .Ports.http };"
+			},
+			expectedError: `template: nginx.conf:1: unexpected "}" in operand`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nginx := baseNginx()
+			nginx.Spec.Config = &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindTemplate}
+			tt.nginx(&nginx)
+
+			got, err := RenderConfigTemplate(&nginx)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestNewDeployment_ConfigTemplate(t *testing.T) {
+	nginx := baseNginx()
+	nginx.Spec.Config = &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindTemplate, Value: "listen {{ .Ports.http }};"}
+
+	dep, err := NewDeployment(&nginx)
+	require.NoError(t, err)
+	assert.Equal(t, "listen 8080;", dep.Spec.Template.Annotations["nginx.tsuru.io/custom-nginx-config"])
+	assert.Equal(t, configVolumeName, dep.Spec.Template.Spec.Volumes[0].Name)
+	require.NotNil(t, dep.Spec.Template.Spec.Volumes[0].DownwardAPI)
+
+	nginx.Spec.Config.Value = "listen {{ .Ports.metrics }};"
+	_, err = NewDeployment(&nginx)
+	assert.ErrorContains(t, err, "failed to render config template: ")
+}
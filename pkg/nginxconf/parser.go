@@ -0,0 +1,250 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package nginxconf parses nginx configuration files, so they can be checked
+// before reaching the nginx pods.
+package nginxconf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Directive is a simple directive (e.g. "listen 80;") or a block directive
+// (e.g. "server { ... }") of the nginx configuration.
+type Directive struct {
+	// Name of the directive.
+	Name string
+	// Args of the directive, unquoted.
+	Args []string
+	// Line where the directive starts.
+	Line int
+	// Block holds the directives inside the block. It's nil for simple
+	// directives.
+	Block []*Directive
+}
+
+// IsBlock reports whether the directive is a block directive.
+func (d *Directive) IsBlock() bool {
+	return d.Block != nil
+}
+
+// Error is a problem found in a configuration, e.g. a syntax error.
+type Error struct {
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// Parse parses the nginx configuration, returning its top-level directives.
+// Includes aren't followed, they're kept as "include" directives.
+func Parse(config string) ([]*Directive, error) {
+	tokens, err := tokenize(config)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	directives, err := p.parseBlock(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return directives, nil
+}
+
+// Walk calls fn for every directive, including the ones nested in blocks.
+func Walk(directives []*Directive, fn func(d *Directive)) {
+	for _, d := range directives {
+		fn(d)
+		Walk(d.Block, fn)
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenSemicolon
+	tokenOpenBrace
+	tokenCloseBrace
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+}
+
+func tokenize(config string) ([]token, error) {
+	var tokens []token
+	line := 1
+
+	for i := 0; i < len(config); {
+		ch := config[i]
+		switch {
+		case ch == '\n':
+			line++
+			i++
+
+		case isSpace(ch):
+			i++
+
+		case ch == '#':
+			for i < len(config) && config[i] != '\n' {
+				i++
+			}
+
+		case ch == ';':
+			tokens = append(tokens, token{kind: tokenSemicolon, value: ";", line: line})
+			i++
+
+		case ch == '{':
+			tokens = append(tokens, token{kind: tokenOpenBrace, value: "{", line: line})
+			i++
+
+		case ch == '}':
+			tokens = append(tokens, token{kind: tokenCloseBrace, value: "}", line: line})
+			i++
+
+		case ch == '"' || ch == '\'':
+			start := line
+			var sb strings.Builder
+			i++
+			for ; i < len(config) && config[i] != ch; i++ {
+				if config[i] == '\\' && i+1 < len(config) && (config[i+1] == ch || config[i+1] == '\\') {
+					i++
+				}
+
+				if config[i] == '\n' {
+					line++
+				}
+				sb.WriteByte(config[i])
+			}
+
+			if i >= len(config) {
+				return nil, &Error{Line: start, Msg: fmt.Sprintf("unterminated quoted string, expecting %q", string(ch))}
+			}
+
+			tokens = append(tokens, token{kind: tokenWord, value: sb.String(), line: start})
+			i++
+
+		default:
+			var sb strings.Builder
+			for i < len(config) {
+				c := config[i]
+				if isSpace(c) || c == ';' || c == '}' || (c == '{' && config[i-1] != '$') {
+					break
+				}
+
+				// NOTE: braces after "$" delimit variable names, e.g. "${host}".
+				if c == '{' {
+					end := strings.IndexByte(config[i:], '}')
+					if end < 0 {
+						return nil, &Error{Line: line, Msg: `unterminated variable, expecting "}"`}
+					}
+
+					sb.WriteString(config[i : i+end+1])
+					i += end + 1
+					continue
+				}
+
+				// escaped characters are kept as is, e.g. regexes like "\.php$".
+				if c == '\\' && i+1 < len(config) {
+					sb.WriteByte(c)
+					i++
+					c = config[i]
+				}
+
+				sb.WriteByte(c)
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenWord, value: sb.String(), line: line})
+		}
+	}
+
+	return tokens, nil
+}
+
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n'
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) parseBlock(depth int) ([]*Directive, error) {
+	directives := []*Directive{}
+
+	for p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+		p.pos++
+
+		switch t.kind {
+		case tokenCloseBrace:
+			if depth == 0 {
+				return nil, &Error{Line: t.line, Msg: `unexpected "}"`}
+			}
+			return directives, nil
+
+		case tokenSemicolon, tokenOpenBrace:
+			return nil, &Error{Line: t.line, Msg: fmt.Sprintf("unexpected %q", t.value)}
+		}
+
+		d := &Directive{Name: t.value, Line: t.line}
+		if err := p.parseDirective(d, depth); err != nil {
+			return nil, err
+		}
+
+		directives = append(directives, d)
+	}
+
+	if depth > 0 {
+		return nil, &Error{Line: p.lastLine(), Msg: `unexpected end of file, expecting "}"`}
+	}
+
+	return directives, nil
+}
+
+func (p *parser) parseDirective(d *Directive, depth int) error {
+	for p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+		p.pos++
+
+		switch t.kind {
+		case tokenWord:
+			d.Args = append(d.Args, t.value)
+
+		case tokenSemicolon:
+			return nil
+
+		case tokenOpenBrace:
+			block, err := p.parseBlock(depth + 1)
+			if err != nil {
+				return err
+			}
+
+			d.Block = block
+			return nil
+
+		case tokenCloseBrace:
+			return &Error{Line: t.line, Msg: fmt.Sprintf("unexpected \"}\", directive %q is not terminated by \";\"", d.Name)}
+		}
+	}
+
+	return &Error{Line: p.lastLine(), Msg: `unexpected end of file, expecting ";" or "}"`}
+}
+
+func (p *parser) lastLine() int {
+	if len(p.tokens) == 0 {
+		return 1
+	}
+	return p.tokens[len(p.tokens)-1].line
+}
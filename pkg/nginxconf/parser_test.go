@@ -0,0 +1,118 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nginxconf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		config        string
+		expected      []*Directive
+		expectedError string
+	}{
+		"empty": {
+			expected: []*Directive{},
+		},
+
+		"blocks and simple directives": {
+			config: `
+# global settings
+worker_processes auto;
+
+events {}
+
+http {
+  server {
+    listen 8080 default_server; # comment after directive
+    location ~ \.php$ {
+      return 200 "ok";
+    }
+  }
+}`,
+			expected: []*Directive{
+				{Name: "worker_processes", Args: []string{"auto"}, Line: 3},
+				{Name: "events", Line: 5, Block: []*Directive{}},
+				{Name: "http", Line: 7, Block: []*Directive{
+					{Name: "server", Line: 8, Block: []*Directive{
+						{Name: "listen", Args: []string{"8080", "default_server"}, Line: 9},
+						{Name: "location", Args: []string{"~", `\.php$`}, Line: 10, Block: []*Directive{
+							{Name: "return", Args: []string{"200", "ok"}, Line: 11},
+						}},
+					}},
+				}},
+			},
+		},
+
+		"variables and quoted strings": {
+			config: `set $name ${arg_name}; return 301 'https://${host}${request_uri}'; add_header X-Quote "say \"hi\";{}";`,
+			expected: []*Directive{
+				{Name: "set", Args: []string{"$name", "${arg_name}"}, Line: 1},
+				{Name: "return", Args: []string{"301", "https://${host}${request_uri}"}, Line: 1},
+				{Name: "add_header", Args: []string{"X-Quote", `say "hi";{}`}, Line: 1},
+			},
+		},
+
+		"quoted string spanning lines": {
+			config: "return 200 'a\nb';\nlisten 80;",
+			expected: []*Directive{
+				{Name: "return", Args: []string{"200", "a\nb"}, Line: 1},
+				{Name: "listen", Args: []string{"80"}, Line: 3},
+			},
+		},
+
+		"unexpected closing brace": {
+			config:        "events {}\n}",
+			expectedError: `line 2: unexpected "}"`,
+		},
+
+		"missing closing brace": {
+			config:        "http {\n  server {\n    listen 80;\n  }\n",
+			expectedError: `line 4: unexpected end of file, expecting "}"`,
+		},
+
+		"missing semicolon": {
+			config:        "http {\n  listen 80\n}",
+			expectedError: `line 3: unexpected "}", directive "listen" is not terminated by ";"`,
+		},
+
+		"missing semicolon at the end": {
+			config:        "worker_processes auto",
+			expectedError: `line 1: unexpected end of file, expecting ";" or "}"`,
+		},
+
+		"unexpected semicolon": {
+			config:        "events {};",
+			expectedError: `line 1: unexpected ";"`,
+		},
+
+		"unterminated quoted string": {
+			config:        "events {}\nreturn 200 'ok;",
+			expectedError: `line 2: unterminated quoted string, expecting "'"`,
+		},
+
+		"unterminated variable": {
+			config:        "set $name ${arg_name;",
+			expectedError: `line 1: unterminated variable, expecting "}"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(tt.config)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nginxconf
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Options describe the files available to nginx.
+type Options struct {
+	// Prefix is the directory the relative paths are resolved from, i.e.
+	// the nginx configuration directory.
+	Prefix string
+	// CertificatesDir is the directory where the TLS Secrets are mounted,
+	// each one on a sub-directory named after it.
+	CertificatesDir string
+	// TLSSecrets are the names of the mounted TLS Secrets.
+	TLSSecrets []string
+	// ExtraFilesDir is the directory where the extra files are mounted.
+	ExtraFilesDir string
+	// ExtraFiles are the paths of the extra files, relative to ExtraFilesDir.
+	// When nil, any file is assumed to exist, e.g. when every key of the
+	// ConfigMap is mounted.
+	ExtraFiles []string
+}
+
+// Validate parses the configuration and checks the files it references
+// against the ones available to nginx. Every problem found is returned.
+func Validate(config string, opts Options) error {
+	directives, err := Parse(config)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	Walk(directives, func(d *Directive) {
+		switch d.Name {
+		case "include":
+			errs = append(errs, checkInclude(d, opts))
+
+		case "ssl_certificate", "ssl_certificate_key":
+			errs = append(errs, checkCertificate(d, opts))
+		}
+	})
+
+	return errors.Join(errs...)
+}
+
+func checkInclude(d *Directive, opts Options) error {
+	if len(d.Args) != 1 {
+		return &Error{Line: d.Line, Msg: fmt.Sprintf("invalid number of arguments in %q directive", d.Name)}
+	}
+
+	// NOTE: only the extra files are known, the ones shipped with the nginx
+	// image (e.g. "mime.types") aren't checked. So are the globs, which are
+	// allowed to match nothing.
+	rel, found := relativeTo(opts.resolve(d.Args[0]), opts.ExtraFilesDir)
+	if !found || opts.ExtraFiles == nil || strings.ContainsAny(rel, "*?[") {
+		return nil
+	}
+
+	for _, f := range opts.ExtraFiles {
+		if filepath.Clean(f) == rel {
+			return nil
+		}
+	}
+
+	return &Error{Line: d.Line, Msg: fmt.Sprintf("included file %q not found in the extra files", d.Args[0])}
+}
+
+func checkCertificate(d *Directive, opts Options) error {
+	if len(d.Args) != 1 {
+		return &Error{Line: d.Line, Msg: fmt.Sprintf("invalid number of arguments in %q directive", d.Name)}
+	}
+
+	// NOTE: certificates might be loaded from variables, e.g.
+	// "data:$certificate", which aren't known until the requests come.
+	if strings.Contains(d.Args[0], "$") {
+		return nil
+	}
+
+	rel, found := relativeTo(opts.resolve(d.Args[0]), opts.CertificatesDir)
+	if !found {
+		return nil
+	}
+
+	secret, _, found := strings.Cut(rel, string(filepath.Separator))
+	if !found {
+		return &Error{Line: d.Line, Msg: fmt.Sprintf("%s %q is not within a TLS Secret directory", d.Name, d.Args[0])}
+	}
+
+	for _, s := range opts.TLSSecrets {
+		if s == secret {
+			return nil
+		}
+	}
+
+	return &Error{Line: d.Line, Msg: fmt.Sprintf("%s %q refers to Secret %q which is not in spec.tls", d.Name, d.Args[0], secret)}
+}
+
+func (o Options) resolve(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(o.Prefix, path)
+}
+
+func relativeTo(path, dir string) (string, bool) {
+	if dir == "" {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return rel, true
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nginxconf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	opts := Options{
+		Prefix:          "/etc/nginx",
+		CertificatesDir: "/etc/nginx/certs",
+		TLSSecrets:      []string{"example-com"},
+		ExtraFilesDir:   "/etc/nginx/extra_files",
+		ExtraFiles:      []string{"conf.d/upstreams.conf", "locations.conf"},
+	}
+
+	tests := map[string]struct {
+		config        string
+		opts          *Options
+		expectedError string
+	}{
+		"valid config": {
+			config: `
+events {}
+http {
+  include mime.types;
+  include /etc/nginx/extra_files/conf.d/upstreams.conf;
+  include extra_files/conf.d/*.conf;
+  server {
+    ssl_certificate     ./certs/example-com/tls.crt;
+    ssl_certificate_key /etc/nginx/certs/example-com/tls.key;
+    ssl_certificate     /etc/ssl/certs/fallback.crt;
+    ssl_certificate_key data:$certificate_key;
+    include ./extra_files/locations.conf;
+  }
+}`,
+		},
+
+		"syntax error": {
+			config:        "http {\n  include mime.types;\n",
+			expectedError: `line 2: unexpected end of file, expecting "}"`,
+		},
+
+		"unknown extra files": {
+			config: `
+http {
+  include extra_files/locations.conf;
+  include /etc/nginx/extra_files/servers.conf;
+  server {
+    include ./extra_files/conf.d/locations.conf;
+  }
+}`,
+			expectedError: `line 4: included file "/etc/nginx/extra_files/servers.conf" not found in the extra files` + "\n" +
+				`line 6: included file "./extra_files/conf.d/locations.conf" not found in the extra files`,
+		},
+
+		"extra files mounted as a whole": {
+			config: "include extra_files/servers.conf;",
+			opts:   &Options{Prefix: "/etc/nginx", ExtraFilesDir: "/etc/nginx/extra_files"},
+		},
+
+		"certificates not in spec.tls": {
+			config: `
+server {
+  ssl_certificate     certs/example-org/tls.crt;
+  ssl_certificate_key certs/example-org/tls.key;
+  ssl_certificate     /etc/nginx/certs/tls.crt;
+}`,
+			expectedError: `line 3: ssl_certificate "certs/example-org/tls.crt" refers to Secret "example-org" which is not in spec.tls` + "\n" +
+				`line 4: ssl_certificate_key "certs/example-org/tls.key" refers to Secret "example-org" which is not in spec.tls` + "\n" +
+				`line 5: ssl_certificate "/etc/nginx/certs/tls.crt" is not within a TLS Secret directory`,
+		},
+
+		"invalid number of arguments": {
+			config:        "include a.conf b.conf;",
+			expectedError: `line 1: invalid number of arguments in "include" directive`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := opts
+			if tt.opts != nil {
+				o = *tt.opts
+			}
+
+			err := Validate(tt.config, o)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
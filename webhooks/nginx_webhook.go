@@ -0,0 +1,460 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	nginxv1beta1 "github.com/tsuru/nginx-operator/api/v1beta1"
+	"github.com/tsuru/nginx-operator/pkg/k8s"
+)
+
+// +kubebuilder:webhook:path=/mutate-nginx-tsuru-io-v1beta1-nginx,mutating=true,failurePolicy=fail,sideEffects=None,groups=nginx.tsuru.io,resources=nginxes,verbs=create;update,versions=v1beta1,name=mnginx.tsuru.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-nginx-tsuru-io-v1beta1-nginx,mutating=false,failurePolicy=fail,sideEffects=None,groups=nginx.tsuru.io,resources=nginxes,verbs=create;update,versions=v1beta1,name=vnginx.tsuru.io,admissionReviewVersions=v1
+
+// NginxValidator rejects Nginx resources whose spec cannot be turned into a
+// working Deployment.
+type NginxValidator struct{}
+
+var _ admission.CustomValidator = &NginxValidator{}
+
+// NginxDefaulter fills the unset fields of Nginx resources with the values
+// used by the operator, so the stored object reflects what is deployed.
+type NginxDefaulter struct{}
+
+var _ admission.CustomDefaulter = &NginxDefaulter{}
+
+// SetupNginxWebhookWithManager registers the Nginx admission webhooks in the
+// manager's webhook server, along with the conversion one between the Nginx
+// API versions.
+func SetupNginxWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&nginxv1beta1.Nginx{}).
+		WithDefaulter(&NginxDefaulter{}).
+		WithValidator(&NginxValidator{}).
+		Complete()
+}
+
+func (d *NginxDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	nginx, ok := obj.(*nginxv1beta1.Nginx)
+	if !ok {
+		return fmt.Errorf("expected a Nginx object but got %T", obj)
+	}
+
+	k8s.SetDefaults(&nginx.Spec)
+
+	// NOTE: the HPA doesn't scale targets with zero replicas, so the Nginx
+	// must start with some replicas when autoscaling is enabled.
+	if as := nginx.Spec.Autoscaling; as != nil && nginx.Spec.Replicas == nil {
+		replicas := int32(1)
+		if as.MinReplicas != nil {
+			replicas = *as.MinReplicas
+		}
+		nginx.Spec.Replicas = &replicas
+	}
+
+	return nil
+}
+
+func (v *NginxValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	nginx, ok := obj.(*nginxv1beta1.Nginx)
+	if !ok {
+		return fmt.Errorf("expected a Nginx object but got %T", obj)
+	}
+
+	return validateNginx(nginx)
+}
+
+func (v *NginxValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	nginx, ok := newObj.(*nginxv1beta1.Nginx)
+	if !ok {
+		return fmt.Errorf("expected a Nginx object but got %T", newObj)
+	}
+
+	return validateNginx(nginx)
+}
+
+func (v *NginxValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func validateNginx(nginx *nginxv1beta1.Nginx) error {
+	specPath := field.NewPath("spec")
+
+	var errs field.ErrorList
+	errs = append(errs, validateConfig(nginx, specPath.Child("config"))...)
+	errs = append(errs, validateTLS(nginx.Spec.TLS, specPath.Child("tls"))...)
+	errs = append(errs, validatePodTemplate(&nginx.Spec.PodTemplate, specPath.Child("podTemplate"))...)
+	errs = append(errs, validateService(nginx.Spec.Service, nginx.Spec.PodTemplate.Ports, specPath.Child("service"))...)
+	errs = append(errs, validateServices(nginx, specPath.Child("services"))...)
+	errs = append(errs, validateGateway(nginx.Spec.Gateway, specPath.Child("gateway"))...)
+	errs = append(errs, validateDisruptionBudget(nginx.Spec.DisruptionBudget, specPath.Child("disruptionBudget"))...)
+	errs = append(errs, validateAutoscaling(nginx.Spec.Autoscaling, specPath.Child("autoscaling"))...)
+	errs = append(errs, validateMonitoring(nginx, specPath.Child("monitoring"))...)
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(schema.GroupKind{Group: nginxv1beta1.GroupVersion.Group, Kind: "Nginx"}, nginx.Name, errs)
+}
+
+func validateConfig(nginx *nginxv1beta1.Nginx, path *field.Path) field.ErrorList {
+	config := nginx.Spec.Config
+	if config == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	if config.Name != "" && config.Value != "" {
+		errs = append(errs, field.Forbidden(path, "name and value are mutually exclusive"))
+	}
+
+	if len(config.Servers) > 0 && config.Kind != nginxv1beta1.ConfigKindStructured {
+		errs = append(errs, field.Forbidden(path.Child("servers"), "servers is only allowed when kind is Structured"))
+	}
+
+	switch config.Kind {
+	case nginxv1beta1.ConfigKindConfigMap:
+		if config.Name == "" {
+			errs = append(errs, field.Required(path.Child("name"), "name is required when kind is ConfigMap"))
+		}
+
+	case nginxv1beta1.ConfigKindInline:
+		if config.Value == "" {
+			errs = append(errs, field.Required(path.Child("value"), "value is required when kind is Inline"))
+		}
+
+	case nginxv1beta1.ConfigKindTemplate:
+		if config.Value == "" {
+			errs = append(errs, field.Required(path.Child("value"), "value is required when kind is Template"))
+			break
+		}
+
+		if _, err := k8s.ParseConfigTemplate(config.Value); err != nil {
+			errs = append(errs, field.Invalid(path.Child("value"), config.Value, err.Error()))
+		}
+
+	case nginxv1beta1.ConfigKindStructured:
+		if config.Name != "" || config.Value != "" {
+			errs = append(errs, field.Forbidden(path, "name and value are not allowed when kind is Structured"))
+		}
+
+		if len(config.Servers) == 0 {
+			errs = append(errs, field.Required(path.Child("servers"), "servers is required when kind is Structured"))
+			break
+		}
+
+		if _, err := k8s.RenderStructuredConfig(nginx); err != nil {
+			errs = append(errs, field.Invalid(path, config.Kind, err.Error()))
+		}
+
+	default:
+		errs = append(errs, field.NotSupported(path.Child("kind"), config.Kind, []string{
+			string(nginxv1beta1.ConfigKindConfigMap),
+			string(nginxv1beta1.ConfigKindInline),
+			string(nginxv1beta1.ConfigKindTemplate),
+			string(nginxv1beta1.ConfigKindStructured),
+		}))
+	}
+
+	// NOTE: the ConfigMaps are only checked by the operator, as they may
+	// change after the Nginx is admitted.
+	if len(errs) == 0 && config.Kind != nginxv1beta1.ConfigKindConfigMap {
+		if err := k8s.ValidateConfig(nginx, nil); err != nil {
+			errs = append(errs, field.Invalid(path, config.Kind, err.Error()))
+		}
+	}
+
+	return errs
+}
+
+func validateTLS(tls []nginxv1beta1.NginxTLS, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	secrets := make(map[string]struct{})
+	for i, t := range tls {
+		if t.SecretName == "" {
+			errs = append(errs, field.Required(path.Index(i).Child("secretName"), ""))
+			continue
+		}
+
+		if _, found := secrets[t.SecretName]; found {
+			errs = append(errs, field.Duplicate(path.Index(i).Child("secretName"), t.SecretName))
+			continue
+		}
+
+		secrets[t.SecretName] = struct{}{}
+
+		errs = append(errs, validateTLSIssuer(t, path.Index(i))...)
+	}
+
+	return errs
+}
+
+func validateTLSIssuer(tls nginxv1beta1.NginxTLS, path *field.Path) field.ErrorList {
+	if tls.IssuerRef == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	if tls.IssuerRef.Name == "" {
+		errs = append(errs, field.Required(path.Child("issuerRef", "name"), ""))
+	}
+
+	if len(tls.Hosts) == 0 {
+		errs = append(errs, field.Required(path.Child("hosts"), "hosts are required to issue the certificate"))
+	}
+
+	for i, host := range tls.Hosts {
+		// NOTE: wildcard domains (e.g. "*.example.com") can be issued, the
+		// catch-all "*" can't though.
+		msgs := validation.IsDNS1123Subdomain(host)
+		if strings.HasPrefix(host, "*") {
+			msgs = validation.IsWildcardDNS1123Subdomain(host)
+		}
+
+		for _, msg := range msgs {
+			errs = append(errs, field.Invalid(path.Child("hosts").Index(i), host, msg))
+		}
+	}
+
+	return errs
+}
+
+func validatePodTemplate(podTemplate *nginxv1beta1.NginxPodTemplateSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	portNames := make(map[string]struct{})
+	portNumbers := make(map[int32]struct{})
+	for i, port := range podTemplate.Ports {
+		if port.Name != "" {
+			if _, found := portNames[port.Name]; found {
+				errs = append(errs, field.Duplicate(path.Child("ports").Index(i).Child("name"), port.Name))
+			}
+			portNames[port.Name] = struct{}{}
+		}
+
+		if _, found := portNumbers[port.ContainerPort]; found {
+			errs = append(errs, field.Duplicate(path.Child("ports").Index(i).Child("containerPort"), port.ContainerPort))
+		}
+		portNumbers[port.ContainerPort] = struct{}{}
+	}
+
+	volumes := make(map[string]struct{})
+	for i, volume := range podTemplate.Volumes {
+		if k8s.IsReservedVolumeName(volume.Name) {
+			errs = append(errs, field.Invalid(path.Child("volumes").Index(i).Child("name"), volume.Name, "name is reserved for volumes generated by the operator"))
+			continue
+		}
+
+		if _, found := volumes[volume.Name]; found {
+			errs = append(errs, field.Duplicate(path.Child("volumes").Index(i).Child("name"), volume.Name))
+		}
+		volumes[volume.Name] = struct{}{}
+	}
+
+	return errs
+}
+
+func validateService(service *nginxv1beta1.NginxService, ports []corev1.ContainerPort, path *field.Path) field.ErrorList {
+	if service == nil || service.ProxyProtocol == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	for _, p := range []struct {
+		name string
+		path *field.Path
+	}{
+		{service.ProxyProtocol.HTTPPort, path.Child("proxyProtocol", "httpPort")},
+		{service.ProxyProtocol.HTTPSPort, path.Child("proxyProtocol", "httpsPort")},
+	} {
+		if p.name != "" && !hasPortName(ports, p.name) {
+			errs = append(errs, field.NotFound(p.path, p.name))
+		}
+	}
+
+	return errs
+}
+
+func validateServices(nginx *nginxv1beta1.Nginx, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	// NOTE: the main Service is named "<nginx name>-service".
+	names := map[string]struct{}{"service": {}}
+	for i, svc := range nginx.Spec.Services {
+		namePath := path.Index(i).Child("name")
+		if svc.Name == "" {
+			errs = append(errs, field.Required(namePath, ""))
+			continue
+		}
+
+		if _, found := names[svc.Name]; found {
+			errs = append(errs, field.Duplicate(namePath, svc.Name))
+			continue
+		}
+		names[svc.Name] = struct{}{}
+
+		// NOTE: "<nginx name>-<a>-service" is the main Service of the Nginx
+		// named "<nginx name>-<a>".
+		if strings.HasSuffix(svc.Name, "-service") {
+			errs = append(errs, field.Invalid(namePath, svc.Name, `name must not end with "-service", which is reserved for the main Service of other Nginxes`))
+			continue
+		}
+
+		for _, msg := range validation.IsDNS1035Label(k8s.ServiceName(nginx, svc.Name)) {
+			errs = append(errs, field.Invalid(namePath, svc.Name, msg))
+		}
+
+		errs = append(errs, validateService(&svc.NginxService, nginx.Spec.PodTemplate.Ports, path.Index(i))...)
+	}
+
+	return errs
+}
+
+func validateGateway(gateway *nginxv1beta1.NginxGateway, path *field.Path) field.ErrorList {
+	if gateway == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	if len(gateway.ParentRefs) == 0 {
+		errs = append(errs, field.Required(path.Child("parentRefs"), "at least one Gateway is required"))
+	}
+
+	refs := make(map[nginxv1beta1.NginxGatewayParentRef]struct{})
+	for i, ref := range gateway.ParentRefs {
+		if ref.Name == "" {
+			errs = append(errs, field.Required(path.Child("parentRefs").Index(i).Child("name"), ""))
+			continue
+		}
+
+		if _, found := refs[ref]; found {
+			errs = append(errs, field.Duplicate(path.Child("parentRefs").Index(i).Child("name"), ref.Name))
+			continue
+		}
+		refs[ref] = struct{}{}
+	}
+
+	return errs
+}
+
+func validateDisruptionBudget(budget *nginxv1beta1.NginxDisruptionBudget, path *field.Path) field.ErrorList {
+	if budget == nil {
+		return nil
+	}
+
+	if budget.MinAvailable != nil && budget.MaxUnavailable != nil {
+		return field.ErrorList{field.Forbidden(path, "minAvailable and maxUnavailable are mutually exclusive")}
+	}
+
+	if budget.MinAvailable == nil && budget.MaxUnavailable == nil {
+		return field.ErrorList{field.Required(path, "either minAvailable or maxUnavailable is required")}
+	}
+
+	if budget.MinAvailable != nil {
+		return validateIntOrPercent(budget.MinAvailable, path.Child("minAvailable"))
+	}
+
+	return validateIntOrPercent(budget.MaxUnavailable, path.Child("maxUnavailable"))
+}
+
+func validateAutoscaling(autoscaling *nginxv1beta1.NginxAutoscaling, path *field.Path) field.ErrorList {
+	if autoscaling == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	if autoscaling.MaxReplicas < 1 {
+		errs = append(errs, field.Invalid(path.Child("maxReplicas"), autoscaling.MaxReplicas, "must be greater than or equal to 1"))
+	}
+
+	if min := autoscaling.MinReplicas; min != nil {
+		if *min < 1 {
+			errs = append(errs, field.Invalid(path.Child("minReplicas"), *min, "must be greater than or equal to 1"))
+		} else if *min > autoscaling.MaxReplicas {
+			errs = append(errs, field.Invalid(path.Child("minReplicas"), *min, "must be less than or equal to maxReplicas"))
+		}
+	}
+
+	if cpu := autoscaling.TargetCPUUtilizationPercentage; cpu != nil && *cpu < 1 {
+		errs = append(errs, field.Invalid(path.Child("targetCPUUtilizationPercentage"), *cpu, "must be greater than 0"))
+	}
+
+	if memory := autoscaling.TargetMemoryUtilizationPercentage; memory != nil && *memory < 1 {
+		errs = append(errs, field.Invalid(path.Child("targetMemoryUtilizationPercentage"), *memory, "must be greater than 0"))
+	}
+
+	return errs
+}
+
+func validateMonitoring(nginx *nginxv1beta1.Nginx, path *field.Path) field.ErrorList {
+	monitoring := nginx.Spec.Monitoring
+	if monitoring == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+
+	ports := k8s.MonitoringPorts(monitoring)
+	if ports[0] == ports[1] {
+		errs = append(errs, field.Duplicate(path.Child("stubStatusPort"), ports[1]))
+	}
+
+	for _, port := range nginx.Spec.PodTemplate.Ports {
+		for i, name := range []string{"port", "stubStatusPort"} {
+			if port.ContainerPort == ports[i] {
+				errs = append(errs, field.Invalid(path.Child(name), ports[i], fmt.Sprintf("port is already used by container port %q", port.Name)))
+			}
+		}
+	}
+
+	if k8s.MonitorKind(nginx) == nginxv1beta1.NginxMonitorServiceMonitor {
+		for i, svc := range nginx.Spec.Services {
+			if k8s.ServiceName(nginx, svc.Name) == k8s.MetricsServiceName(nginx) {
+				errs = append(errs, field.Invalid(field.NewPath("spec", "services").Index(i).Child("name"), svc.Name, "name is reserved for the metrics Service of the ServiceMonitor"))
+			}
+		}
+	}
+
+	return errs
+}
+
+func validateIntOrPercent(value *intstr.IntOrString, path *field.Path) field.ErrorList {
+	if value.Type == intstr.String {
+		var errs field.ErrorList
+		for _, msg := range validation.IsValidPercent(value.StrVal) {
+			errs = append(errs, field.Invalid(path, value.StrVal, msg))
+		}
+		return errs
+	}
+
+	if value.IntVal < 0 {
+		return field.ErrorList{field.Invalid(path, value.IntVal, "must be greater than or equal to 0")}
+	}
+
+	return nil
+}
+
+func hasPortName(ports []corev1.ContainerPort, name string) bool {
+	for _, port := range ports {
+		if port.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,478 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/tsuru/nginx-operator/api/v1beta1"
+)
+
+func TestNginxValidator(t *testing.T) {
+	tests := map[string]struct {
+		spec          v1beta1.NginxSpec
+		expectedError string
+	}{
+		"empty spec": {},
+
+		"valid spec": {
+			spec: v1beta1.NginxSpec{
+				Config: &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindConfigMap, Name: "my-config"},
+				TLS: []v1beta1.NginxTLS{
+					{SecretName: "my-secret-1"},
+					{SecretName: "my-secret-2"},
+				},
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					Ports: []corev1.ContainerPort{
+						{Name: "http", ContainerPort: 8080},
+						{Name: "https", ContainerPort: 8443},
+					},
+					Volumes: []corev1.Volume{
+						{Name: "my-volume"},
+						{Name: "nginx-certs-extra"},
+					},
+				},
+			},
+		},
+
+		"config map kind without name": {
+			spec: v1beta1.NginxSpec{
+				Config: &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindConfigMap},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.config.name: Required value: name is required when kind is ConfigMap`,
+		},
+
+		"inline kind without value": {
+			spec: v1beta1.NginxSpec{
+				Config: &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindInline},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.config.value: Required value: value is required when kind is Inline`,
+		},
+
+		"template kind without value": {
+			spec: v1beta1.NginxSpec{
+				Config: &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindTemplate},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.config.value: Required value: value is required when kind is Template`,
+		},
+
+		"malformed template": {
+			spec: v1beta1.NginxSpec{
+				Config: &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindTemplate, Value: "listen {{ .Ports.http };"},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.config.value: Invalid value: "listen {{ .Ports.http };": template: nginx.conf:1: unexpected "}" in operand`,
+		},
+
+		"structured kind without servers": {
+			spec: v1beta1.NginxSpec{
+				Config: &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindStructured},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.config.servers: Required value: servers is required when kind is Structured`,
+		},
+
+		"structured kind with unknown port": {
+			spec: v1beta1.NginxSpec{
+				Config: &v1beta1.ConfigRef{
+					Kind: v1beta1.ConfigKindStructured,
+					Servers: []v1beta1.NginxServerConfig{
+						{Listen: []v1beta1.NginxListen{{Port: "metrics"}}},
+					},
+				},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.config: Invalid value: "Structured": servers[0]: container port "metrics" not found`,
+		},
+
+		"servers set on other kinds": {
+			spec: v1beta1.NginxSpec{
+				Config: &v1beta1.ConfigRef{
+					Kind:    v1beta1.ConfigKindInline,
+					Value:   "events {}",
+					Servers: []v1beta1.NginxServerConfig{{ServerNames: []string{"www.example.com"}}},
+				},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.config.servers: Forbidden: servers is only allowed when kind is Structured`,
+		},
+
+		"inline config with unbalanced braces": {
+			spec: v1beta1.NginxSpec{
+				Config: &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindInline, Value: "events {}\nhttp {\n"},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.config: Invalid value: "Inline": line 2: unexpected end of file, expecting "}"`,
+		},
+
+		"template config with certificate not in spec.tls": {
+			spec: v1beta1.NginxSpec{
+				Config: &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindTemplate, Value: "ssl_certificate /etc/nginx/certs/{{ .Name }}/tls.crt;"},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.config: Invalid value: "Template": line 1: ssl_certificate "/etc/nginx/certs/my-nginx/tls.crt" refers to Secret "my-nginx" which is not in spec.tls`,
+		},
+
+		"both name and value set": {
+			spec: v1beta1.NginxSpec{
+				Config: &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindInline, Name: "my-config", Value: "events {}"},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.config: Forbidden: name and value are mutually exclusive`,
+		},
+
+		"unknown config kind": {
+			spec: v1beta1.NginxSpec{
+				Config: &v1beta1.ConfigRef{Kind: "Secret", Name: "my-config"},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.config.kind: Unsupported value: "Secret": supported values: "ConfigMap", "Inline", "Template", "Structured"`,
+		},
+
+		"duplicated TLS secret names": {
+			spec: v1beta1.NginxSpec{
+				TLS: []v1beta1.NginxTLS{
+					{SecretName: "my-secret", Hosts: []string{"www.example.com"}},
+					{SecretName: "my-secret", Hosts: []string{"www.example.org"}},
+				},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.tls[1].secretName: Duplicate value: "my-secret"`,
+		},
+
+		"TLS without secret name": {
+			spec: v1beta1.NginxSpec{
+				TLS: []v1beta1.NginxTLS{{Hosts: []string{"www.example.com"}}},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.tls[0].secretName: Required value`,
+		},
+
+		"duplicated port names and numbers": {
+			spec: v1beta1.NginxSpec{
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					Ports: []corev1.ContainerPort{
+						{Name: "http", ContainerPort: 8080},
+						{Name: "http", ContainerPort: 8080},
+					},
+				},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: [spec.podTemplate.ports[1].name: Duplicate value: "http", spec.podTemplate.ports[1].containerPort: Duplicate value: 8080]`,
+		},
+
+		"volumes colliding with the generated ones": {
+			spec: v1beta1.NginxSpec{
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					Volumes: []corev1.Volume{
+						{Name: "nginx-config"},
+						{Name: "cache-vol"},
+						{Name: "nginx-certs-0"},
+						{Name: "nginx-extra-files"},
+					},
+				},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: [spec.podTemplate.volumes[0].name: Invalid value: "nginx-config": name is reserved for volumes generated by the operator, spec.podTemplate.volumes[1].name: Invalid value: "cache-vol": name is reserved for volumes generated by the operator, spec.podTemplate.volumes[2].name: Invalid value: "nginx-certs-0": name is reserved for volumes generated by the operator, spec.podTemplate.volumes[3].name: Invalid value: "nginx-extra-files": name is reserved for volumes generated by the operator]`,
+		},
+
+		"proxy protocol ports not found": {
+			spec: v1beta1.NginxSpec{
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					Ports: []corev1.ContainerPort{{Name: "proxy-http", ContainerPort: 9080}},
+				},
+				Service: &v1beta1.NginxService{
+					Type:          corev1.ServiceTypeLoadBalancer,
+					ProxyProtocol: &v1beta1.NginxProxyProtocol{HTTPPort: "proxy-http", HTTPSPort: "proxy-https"},
+				},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.service.proxyProtocol.httpsPort: Not found: "proxy-https"`,
+		},
+
+		"invalid service names": {
+			spec: v1beta1.NginxSpec{
+				Services: []v1beta1.NginxNamedService{
+					{Name: "internal"},
+					{Name: "service"},
+					{Name: "internal"},
+					{Name: "Public"},
+					{},
+					{Name: "admin-service"},
+				},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: [spec.services[1].name: Duplicate value: "service", spec.services[2].name: Duplicate value: "internal", spec.services[3].name: Invalid value: "Public": a DNS-1035 label must consist of lower case alphanumeric characters or '-', start with an alphabetic character, and end with an alphanumeric character (e.g. 'my-name',  or 'abc-123', regex used for validation is '[a-z]([-a-z0-9]*[a-z0-9])?'), spec.services[4].name: Required value, spec.services[5].name: Invalid value: "admin-service": name must not end with "-service", which is reserved for the main Service of other Nginxes]`,
+		},
+
+		"invalid gateway parent refs": {
+			spec: v1beta1.NginxSpec{
+				Gateway: &v1beta1.NginxGateway{
+					ParentRefs: []v1beta1.NginxGatewayParentRef{
+						{Name: "my-gateway"},
+						{Namespace: "gateways"},
+						{Name: "my-gateway"},
+						{Name: "my-gateway", SectionName: "https"},
+					},
+				},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: [spec.gateway.parentRefs[1].name: Required value, spec.gateway.parentRefs[2].name: Duplicate value: "my-gateway"]`,
+		},
+
+		"gateway without parent refs": {
+			spec: v1beta1.NginxSpec{
+				Gateway: &v1beta1.NginxGateway{},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.gateway.parentRefs: Required value: at least one Gateway is required`,
+		},
+
+		"disruption budget with both minAvailable and maxUnavailable": {
+			spec: v1beta1.NginxSpec{
+				DisruptionBudget: &v1beta1.NginxDisruptionBudget{
+					MinAvailable:   func(v intstr.IntOrString) *intstr.IntOrString { return &v }(intstr.FromInt(1)),
+					MaxUnavailable: func(v intstr.IntOrString) *intstr.IntOrString { return &v }(intstr.FromInt(1)),
+				},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.disruptionBudget: Forbidden: minAvailable and maxUnavailable are mutually exclusive`,
+		},
+
+		"empty disruption budget": {
+			spec: v1beta1.NginxSpec{
+				DisruptionBudget: &v1beta1.NginxDisruptionBudget{},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.disruptionBudget: Required value: either minAvailable or maxUnavailable is required`,
+		},
+
+		"disruption budget with invalid values": {
+			spec: v1beta1.NginxSpec{
+				DisruptionBudget: &v1beta1.NginxDisruptionBudget{
+					MaxUnavailable: func(v intstr.IntOrString) *intstr.IntOrString { return &v }(intstr.FromString("half")),
+				},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.disruptionBudget.maxUnavailable: Invalid value: "half": a valid percent string must be a numeric string followed by an ending '%' (e.g. '1%',  or '93%', regex used for validation is '[0-9]+%')`,
+		},
+
+		"negative disruption budget": {
+			spec: v1beta1.NginxSpec{
+				DisruptionBudget: &v1beta1.NginxDisruptionBudget{
+					MinAvailable: func(v intstr.IntOrString) *intstr.IntOrString { return &v }(intstr.FromInt(-1)),
+				},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.disruptionBudget.minAvailable: Invalid value: -1: must be greater than or equal to 0`,
+		},
+
+		"invalid autoscaling": {
+			spec: v1beta1.NginxSpec{
+				Autoscaling: &v1beta1.NginxAutoscaling{
+					MinReplicas:                       func(n int32) *int32 { return &n }(int32(5)),
+					MaxReplicas:                       3,
+					TargetCPUUtilizationPercentage:    func(n int32) *int32 { return &n }(int32(0)),
+					TargetMemoryUtilizationPercentage: func(n int32) *int32 { return &n }(int32(80)),
+				},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: [spec.autoscaling.minReplicas: Invalid value: 5: must be less than or equal to maxReplicas, spec.autoscaling.targetCPUUtilizationPercentage: Invalid value: 0: must be greater than 0]`,
+		},
+
+		"autoscaling without max replicas": {
+			spec: v1beta1.NginxSpec{
+				Autoscaling: &v1beta1.NginxAutoscaling{},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.autoscaling.maxReplicas: Invalid value: 0: must be greater than or equal to 1`,
+		},
+
+		"tls with invalid issuer": {
+			spec: v1beta1.NginxSpec{
+				TLS: []v1beta1.NginxTLS{
+					{SecretName: "my-secret-1", Hosts: []string{"www.example.com", "*.example.com"}, IssuerRef: &v1beta1.NginxTLSIssuerRef{Name: "letsencrypt"}},
+					{SecretName: "my-secret-2", IssuerRef: &v1beta1.NginxTLSIssuerRef{}},
+					{SecretName: "my-secret-3", Hosts: []string{"*"}, IssuerRef: &v1beta1.NginxTLSIssuerRef{Name: "letsencrypt"}},
+				},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: [spec.tls[1].issuerRef.name: Required value, spec.tls[1].hosts: Required value: hosts are required to issue the certificate, spec.tls[2].hosts[0]: Invalid value: "*": a wildcard DNS-1123 subdomain must start with '*.', followed by a valid DNS subdomain, which must consist of lower case alphanumeric characters, '-' or '.' and end with an alphanumeric character (e.g. '*.example.com', regex used for validation is '\*\.[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')]`,
+		},
+
+		"duplicated volume names": {
+			spec: v1beta1.NginxSpec{
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					Volumes: []corev1.Volume{
+						{Name: "my-volume"},
+						{Name: "my-volume"},
+					},
+				},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.podTemplate.volumes[1].name: Duplicate value: "my-volume"`,
+		},
+
+		"valid monitoring": {
+			spec: v1beta1.NginxSpec{
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+				},
+				Services:   []v1beta1.NginxNamedService{{Name: "metrics"}},
+				Monitoring: &v1beta1.NginxMonitoring{},
+			},
+		},
+
+		"monitoring ports already in use": {
+			spec: v1beta1.NginxSpec{
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					Ports: []corev1.ContainerPort{
+						{Name: "http", ContainerPort: 8080},
+						{Name: "status", ContainerPort: 8090},
+					},
+				},
+				Monitoring: &v1beta1.NginxMonitoring{Port: 8080},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: [spec.monitoring.port: Invalid value: 8080: port is already used by container port "http", spec.monitoring.stubStatusPort: Invalid value: 8090: port is already used by container port "status"]`,
+		},
+
+		"monitoring ports with the same number": {
+			spec: v1beta1.NginxSpec{
+				Monitoring: &v1beta1.NginxMonitoring{Port: 9000, StubStatusPort: 9000},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.monitoring.stubStatusPort: Duplicate value: 9000`,
+		},
+
+		"service named as the metrics one": {
+			spec: v1beta1.NginxSpec{
+				Services:   []v1beta1.NginxNamedService{{Name: "metrics"}},
+				Monitoring: &v1beta1.NginxMonitoring{Monitor: v1beta1.NginxMonitorServiceMonitor},
+			},
+			expectedError: `Nginx.nginx.tsuru.io "my-nginx" is invalid: spec.services[0].name: Invalid value: "metrics": name is reserved for the metrics Service of the ServiceMonitor`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nginx := &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+
+			v := &NginxValidator{}
+			createErr := v.ValidateCreate(context.TODO(), nginx)
+			updateErr := v.ValidateUpdate(context.TODO(), &v1beta1.Nginx{}, nginx)
+
+			if tt.expectedError == "" {
+				assert.NoError(t, createErr)
+				assert.NoError(t, updateErr)
+				return
+			}
+
+			require.Error(t, createErr)
+			assert.True(t, apierrors.IsInvalid(createErr))
+			assert.EqualError(t, createErr, tt.expectedError)
+			assert.EqualError(t, updateErr, tt.expectedError)
+		})
+	}
+}
+
+func TestNginxValidator_ValidateDelete(t *testing.T) {
+	v := &NginxValidator{}
+	assert.NoError(t, v.ValidateDelete(context.TODO(), &v1beta1.Nginx{
+		Spec: v1beta1.NginxSpec{Config: &v1beta1.ConfigRef{Kind: v1beta1.ConfigKindConfigMap}},
+	}))
+}
+
+func TestNginxDefaulter(t *testing.T) {
+	tests := map[string]struct {
+		spec     v1beta1.NginxSpec
+		expected v1beta1.NginxSpec
+	}{
+		"empty spec": {
+			expected: v1beta1.NginxSpec{
+				Image: "nginx:latest",
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					Ports: []corev1.ContainerPort{
+						{Name: "http", ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
+						{Name: "https", ContainerPort: 8443, Protocol: corev1.ProtocolTCP},
+					},
+				},
+			},
+		},
+
+		"host network": {
+			spec: v1beta1.NginxSpec{
+				PodTemplate: v1beta1.NginxPodTemplateSpec{HostNetwork: true},
+			},
+			expected: v1beta1.NginxSpec{
+				Image: "nginx:latest",
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					HostNetwork: true,
+					Ports: []corev1.ContainerPort{
+						{Name: "http", ContainerPort: 80, Protocol: corev1.ProtocolTCP},
+						{Name: "https", ContainerPort: 443, Protocol: corev1.ProtocolTCP},
+					},
+				},
+			},
+		},
+
+		"already set fields are kept": {
+			spec: v1beta1.NginxSpec{
+				Image: "tsuru/nginx:1.22",
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 9000}},
+				},
+			},
+			expected: v1beta1.NginxSpec{
+				Image: "tsuru/nginx:1.22",
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					Ports: []corev1.ContainerPort{
+						{Name: "http", ContainerPort: 9000},
+						{Name: "https", ContainerPort: 8443, Protocol: corev1.ProtocolTCP},
+					},
+				},
+			},
+		},
+
+		"autoscaling starts with min replicas": {
+			spec: v1beta1.NginxSpec{
+				Autoscaling: &v1beta1.NginxAutoscaling{
+					MinReplicas: func(n int32) *int32 { return &n }(int32(2)),
+					MaxReplicas: 10,
+				},
+			},
+			expected: v1beta1.NginxSpec{
+				Replicas: func(n int32) *int32 { return &n }(int32(2)),
+				Image:    "nginx:latest",
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					Ports: []corev1.ContainerPort{
+						{Name: "http", ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
+						{Name: "https", ContainerPort: 8443, Protocol: corev1.ProtocolTCP},
+					},
+				},
+				Autoscaling: &v1beta1.NginxAutoscaling{
+					MinReplicas: func(n int32) *int32 { return &n }(int32(2)),
+					MaxReplicas: 10,
+				},
+			},
+		},
+
+		"autoscaling keeps current replicas": {
+			spec: v1beta1.NginxSpec{
+				Replicas:    func(n int32) *int32 { return &n }(int32(7)),
+				Autoscaling: &v1beta1.NginxAutoscaling{MaxReplicas: 10},
+			},
+			expected: v1beta1.NginxSpec{
+				Replicas: func(n int32) *int32 { return &n }(int32(7)),
+				Image:    "nginx:latest",
+				PodTemplate: v1beta1.NginxPodTemplateSpec{
+					Ports: []corev1.ContainerPort{
+						{Name: "http", ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
+						{Name: "https", ContainerPort: 8443, Protocol: corev1.ProtocolTCP},
+					},
+				},
+				Autoscaling: &v1beta1.NginxAutoscaling{MaxReplicas: 10},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nginx := &v1beta1.Nginx{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-nginx", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+
+			d := &NginxDefaulter{}
+			require.NoError(t, d.Default(context.TODO(), nginx))
+			assert.Equal(t, tt.expected, nginx.Spec)
+		})
+	}
+}